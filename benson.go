@@ -0,0 +1,159 @@
+package aigo
+
+// Benson 无条件活棋算法
+// 对一方来说，不是这一方棋子的点连成的最大连通块叫做这一方的区域（可以包含空点和对方的棋子）。
+// 如果区域里所有的空点都是某条棋链的气，这个区域就是这条棋链的要害区域（vital region）。
+// 反复去掉要害区域少于两个的棋链，以及和被去掉的棋链相邻的区域，剩下的棋链就是无条件活棋：
+// 对方怎么下、自己一直跳过，这些棋链都不会被提走。
+
+// Benson 算法对一方的计算结果
+type BensonResult struct {
+	Color        Player        // 计算的是哪一方
+	Alive        []*StoneGroup // 无条件活的棋链
+	VitalRegions [][]Point     // 活棋的要害区域，每个区域是一组交叉点
+}
+
+// 这一方的某条棋链是否无条件活
+func (r *BensonResult) IsAlive(p Point) bool {
+	for _, sg := range r.Alive {
+		for _, s := range sg.Stones {
+			if s == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Benson 算法计算时的区域
+type bensonRegion struct {
+	points  []int32
+	borders []int32 // 包围这个区域的棋链，用链头表示
+	vital   []int32 // 以这个区域为要害区域的棋链
+	alive   bool
+}
+
+// 用 Benson 算法计算指定一方的无条件活棋和要害区域
+func (b *Board) Benson(color Player) *BensonResult {
+	alive := map[int32]bool{}
+	for i, c := range b.grid {
+		if c == color && b.head[i] == int32(i) {
+			alive[int32(i)] = true
+		}
+	}
+
+	// 找出所有区域，以及包围区域的棋链和要害区域
+	regions := []*bensonRegion{}
+	gen := b.nextMark()
+	for i, c := range b.grid {
+		if c == color || b.mark[i] == gen {
+			continue
+		}
+		r := &bensonRegion{points: []int32{int32(i)}, alive: true}
+		b.mark[i] = gen
+		for k := 0; k < len(r.points); k++ {
+			for _, n := range b.adj[4*r.points[k] : 4*r.points[k]+4] {
+				if n < 0 {
+					continue
+				}
+				if b.grid[n] == color {
+					r.borders = appendUniqueInt32(r.borders, b.head[n])
+				} else if b.mark[n] != gen {
+					b.mark[n] = gen
+					r.points = append(r.points, n)
+				}
+			}
+		}
+		for _, h := range r.borders {
+			if b.isVitalRegion(r.points, h) {
+				r.vital = append(r.vital, h)
+			}
+		}
+		regions = append(regions, r)
+	}
+
+	// 反复去掉要害区域不足两个的棋链，和被去掉的棋链相邻的区域
+	for changed := true; changed; {
+		changed = false
+		vital_count := map[int32]int{}
+		for _, r := range regions {
+			if !r.alive {
+				continue
+			}
+			for _, h := range r.vital {
+				vital_count[h]++
+			}
+		}
+		for h := range alive {
+			if vital_count[h] < 2 {
+				delete(alive, h)
+				changed = true
+			}
+		}
+		for _, r := range regions {
+			if !r.alive {
+				continue
+			}
+			for _, h := range r.borders {
+				if !alive[h] {
+					r.alive = false
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	result := &BensonResult{Color: color}
+	for i, c := range b.grid {
+		if c == color && b.head[i] == int32(i) && alive[int32(i)] {
+			result.Alive = append(result.Alive, b.stoneGroupAt(int32(i)))
+		}
+	}
+	for _, r := range regions {
+		if !r.alive || len(r.vital) == 0 {
+			continue
+		}
+		points := make([]Point, len(r.points))
+		for k, e := range r.points {
+			points[k] = b.point(e)
+		}
+		result.VitalRegions = append(result.VitalRegions, points)
+	}
+	return result
+}
+
+// 双方的无条件活棋，按 Player 下标，None 的位置为 nil
+func (b *Board) UnconditionalLife() [3]*BensonResult {
+	return [3]*BensonResult{Black: b.Benson(Black), White: b.Benson(White)}
+}
+
+// 区域里的空点是否都是链头为 h 的棋链的气
+func (b *Board) isVitalRegion(points []int32, h int32) bool {
+	for _, e := range points {
+		if b.grid[e] != None {
+			continue
+		}
+		liberty := false
+		for _, n := range b.adj[4*e : 4*e+4] {
+			if n >= 0 && b.grid[n] != None && b.head[n] == h {
+				liberty = true
+				break
+			}
+		}
+		if !liberty {
+			return false
+		}
+	}
+	return true
+}
+
+// 不重复地追加
+func appendUniqueInt32(list []int32, v int32) []int32 {
+	for _, e := range list {
+		if e == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
@@ -0,0 +1,123 @@
+package aigo
+
+import (
+	"testing"
+)
+
+func bensonBoard(t *testing.T, rows ...string) *Board {
+	return gameFromDiagram(t, ChineseRules, Black, rows...).BoardPosition
+}
+
+func TestBensonTwoEyes(t *testing.T) {
+	b := bensonBoard(t,
+		".X.X.",
+		"XXXXX",
+		".....",
+		".....",
+		".....",
+	)
+	r := b.Benson(Black)
+	if len(r.Alive) != 1 || len(r.Alive[0].Stones) != 7 {
+		t.Fatalf("两个眼的棋应该是活棋: %v", r.Alive)
+	}
+	if len(r.VitalRegions) != 3 {
+		t.Errorf("应该有三个要害区域: %v", r.VitalRegions)
+	}
+	if !r.IsAlive(Point{Row: 4, Col: 3}) || r.IsAlive(Point{Row: 1, Col: 1}) {
+		t.Error("IsAlive 不对")
+	}
+
+	// 只有一个眼
+	b = bensonBoard(t,
+		".....",
+		"XXXXX",
+		".....",
+		".....",
+		".....",
+	)
+	if r := b.Benson(Black); len(r.Alive) != 0 || len(r.VitalRegions) != 0 {
+		t.Errorf("一个眼的棋不是无条件活: %v %v", r.Alive, r.VitalRegions)
+	}
+}
+
+func TestBensonBigEye(t *testing.T) {
+	// 一个单眼加一个三目的大眼，大眼里的空点都是气，仍然是要害区域
+	b := bensonBoard(t,
+		".X...",
+		"XXXXX",
+		".....",
+		".....",
+		".....",
+	)
+	if r := b.Benson(Black); len(r.Alive) != 1 || len(r.VitalRegions) != 2 {
+		t.Errorf("大眼加单眼应该是活棋: %v %v", r.Alive, r.VitalRegions)
+	}
+
+	// 大眼里有对方的棋子也不影响
+	b = bensonBoard(t,
+		"O.X..",
+		"XXXXX",
+		".....",
+		".....",
+		".....",
+	)
+	if r := b.Benson(Black); len(r.Alive) != 1 {
+		t.Errorf("眼里有对方的棋子也应该是活棋: %v", r.Alive)
+	}
+
+	// 中间的点不是气，3x3 的大眼不是要害区域
+	b = bensonBoard(t,
+		"XXXXX",
+		"X...X",
+		"X...X",
+		"X...X",
+		"XXXXX",
+	)
+	if r := b.Benson(Black); len(r.Alive) != 0 {
+		t.Errorf("只有一个大眼不是无条件活: %v", r.Alive)
+	}
+}
+
+func TestBensonEdge(t *testing.T) {
+	// 边上的两个眼，两边的空和外面连在一起，不算要害区域
+	b := bensonBoard(t,
+		".......",
+		".......",
+		".XXXXX.",
+		".X.X.X.",
+	)
+	r := b.Benson(Black)
+	if len(r.Alive) != 1 || len(r.VitalRegions) != 2 {
+		t.Errorf("边上两个眼应该是活棋: %v %v", r.Alive, r.VitalRegions)
+	}
+
+	// 右边的棋链只有一个要害区域，去掉它以后，左边的棋链也只剩一个
+	b = bensonBoard(t,
+		".....",
+		"XXX.X",
+		".X.XX",
+	)
+	if r := b.Benson(Black); len(r.Alive) != 0 {
+		t.Errorf("共用的眼不能算两次: %v", r.Alive)
+	}
+}
+
+func TestUnconditionalLife(t *testing.T) {
+	b := bensonBoard(t,
+		".X.XO.O",
+		"XXXXOOO",
+		"XXXXOOO",
+		"XXXXOOO",
+		"X.X.O.O",
+	)
+	life := b.UnconditionalLife()
+	if life[None] != nil {
+		t.Error("None 不需要计算")
+	}
+	if len(life[Black].Alive) != 1 || life[Black].Color != Black {
+		t.Errorf("黑棋应该是活棋: %v", life[Black].Alive)
+	}
+	if len(life[White].Alive) != 1 || len(life[White].VitalRegions) != 2 {
+		t.Errorf("白棋应该是活棋: %v %v", life[White].Alive, life[White].VitalRegions)
+	}
+}
@@ -0,0 +1,425 @@
+package aigo
+
+import (
+	"log"
+	"math/bits"
+	"math/rand"
+)
+
+// 位棋盘，给 11 路以下的小棋盘做随机模拟用
+// 黑白棋子各用一个 128 位的位集合表示，交叉点的下标同 Board（(行-1)*宽+(列-1)），
+// 相邻点、棋链、气、眼、地盘都用移位和与或运算算出来，不需要 map，也不保存棋链。
+// 只记录简单劫，没有全局同形判断。
+
+// 位棋盘支持的最大边长
+const MaxBitBoardSize = 11
+
+// 128 位的位集合
+type bitset128 struct {
+	lo, hi uint64
+}
+
+func bitAt(i int) bitset128 {
+	if i < 64 {
+		return bitset128{lo: 1 << uint(i)}
+	}
+	return bitset128{hi: 1 << uint(i-64)}
+}
+
+func (a bitset128) and(b bitset128) bitset128    { return bitset128{a.lo & b.lo, a.hi & b.hi} }
+func (a bitset128) or(b bitset128) bitset128     { return bitset128{a.lo | b.lo, a.hi | b.hi} }
+func (a bitset128) andNot(b bitset128) bitset128 { return bitset128{a.lo &^ b.lo, a.hi &^ b.hi} }
+func (a bitset128) isZero() bool                 { return a.lo == 0 && a.hi == 0 }
+func (a bitset128) count() int                   { return bits.OnesCount64(a.lo) + bits.OnesCount64(a.hi) }
+
+func (a bitset128) has(i int) bool {
+	return !a.and(bitAt(i)).isZero()
+}
+
+// 最低的一位的下标，集合为空时返回 -1
+func (a bitset128) lowest() int {
+	if a.lo != 0 {
+		return bits.TrailingZeros64(a.lo)
+	}
+	if a.hi != 0 {
+		return 64 + bits.TrailingZeros64(a.hi)
+	}
+	return -1
+}
+
+// 左移 n 位，下标变大，n 小于 64
+func (a bitset128) shl(n uint) bitset128 {
+	if n == 0 {
+		return a
+	}
+	return bitset128{lo: a.lo << n, hi: a.hi<<n | a.lo>>(64-n)}
+}
+
+// 右移 n 位，下标变小，n 小于 64
+func (a bitset128) shr(n uint) bitset128 {
+	if n == 0 {
+		return a
+	}
+	return bitset128{lo: a.lo>>n | a.hi<<(64-n), hi: a.hi >> n}
+}
+
+// 位棋盘
+type BitBoard struct {
+	Width, Height uint16
+	stones        [3]bitset128 // 以 Player 为下标，None 不用
+	onBoard       bitset128    // 棋盘上所有的交叉点
+	notFirstCol   bitset128    // 去掉第一列，左右移位时挡住换行过来的位
+	notLastCol    bitset128    // 去掉最后一列
+	ko            int          // 上一步提劫形成的禁着点，没有时为 -1
+	Prisoners     [3]int       // 以 Player 为下标，各方提掉对方的棋子数
+	PassStones    bool         // AGA 规则：随机模拟里每跳过一次，交给对方一颗子作为提子
+}
+
+// 指定长宽的棋盘能不能用位棋盘表示
+func FitsBitBoard(w uint16, h uint16) bool {
+	return 1 <= w && w <= MaxBitBoardSize && 1 <= h && h <= MaxBitBoardSize
+}
+
+// 构造一个指定长宽的空的位棋盘，长宽都不能超过 MaxBitBoardSize
+func NewBitBoard(w uint16, h uint16) *BitBoard {
+	if !FitsBitBoard(w, h) {
+		log.Panicf("%dx%d 的棋盘不能用位棋盘表示", w, h)
+	}
+	bb := &BitBoard{Width: w, Height: h, ko: -1}
+	for r := 0; r < int(h); r++ {
+		for c := 0; c < int(w); c++ {
+			bit := bitAt(r*int(w) + c)
+			bb.onBoard = bb.onBoard.or(bit)
+			if c != 0 {
+				bb.notFirstCol = bb.notFirstCol.or(bit)
+			}
+			if c != int(w)-1 {
+				bb.notLastCol = bb.notLastCol.or(bit)
+			}
+		}
+	}
+	return bb
+}
+
+// 从 Board 生成位棋盘，棋盘太大时 panic，调用前用 FitsBitBoard 检查
+func (b *Board) BitBoard() *BitBoard {
+	bb := NewBitBoard(b.Width, b.Height)
+	for i, c := range b.grid {
+		if c != None {
+			bb.stones[c] = bb.stones[c].or(bitAt(i))
+		}
+	}
+	return bb
+}
+
+// 生成内容相同的 Board
+func (bb *BitBoard) Board() *Board {
+	b := NewBoard(bb.Width, bb.Height)
+	for _, c := range []Player{Black, White} {
+		for s := bb.stones[c]; !s.isZero(); {
+			i := s.lowest()
+			s = s.andNot(bitAt(i))
+			b.PlaceStone(c, bb.point(i))
+		}
+	}
+	return b
+}
+
+// 位棋盘没有指针，复制一份结构体就是深拷贝
+func (bb *BitBoard) Copy() *BitBoard {
+	nb := *bb
+	return &nb
+}
+
+func (bb *BitBoard) index(p Point) int {
+	return int(p.Row-1)*int(bb.Width) + int(p.Col-1)
+}
+
+func (bb *BitBoard) point(i int) Point {
+	return Point{Row: uint16(i/int(bb.Width)) + 1, Col: uint16(i%int(bb.Width)) + 1}
+}
+
+// 检查棋子是否在棋盘上
+func (bb *BitBoard) IsOnGrid(p Point) bool {
+	b1 := (1 <= p.Row) && (p.Row <= bb.Height)
+	b2 := (1 <= p.Col) && (p.Col <= bb.Width)
+	return b1 && b2
+}
+
+// 返回棋盘某个位置的棋子颜色，棋盘外返回 None
+func (bb *BitBoard) Get(p Point) Player {
+	if !bb.IsOnGrid(p) {
+		return None
+	}
+	i := bb.index(p)
+	switch {
+	case bb.stones[Black].has(i):
+		return Black
+	case bb.stones[White].has(i):
+		return White
+	}
+	return None
+}
+
+// 比较两个棋盘的棋子
+func (bb *BitBoard) Equal(c *BitBoard) bool {
+	return c != nil && bb.Width == c.Width && bb.Height == c.Height && bb.stones == c.stones
+}
+
+// 棋盘上的空点
+func (bb *BitBoard) empty() bitset128 {
+	return bb.onBoard.andNot(bb.stones[Black]).andNot(bb.stones[White])
+}
+
+// 集合 s 里的点上下左右相邻的点，s 是棋链时结果里也会有 s 自己的点
+func (bb *BitBoard) neighbors(s bitset128) bitset128 {
+	w := uint(bb.Width)
+	n := s.shl(1).and(bb.notFirstCol)
+	n = n.or(s.shr(1).and(bb.notLastCol))
+	n = n.or(s.shl(w)).or(s.shr(w))
+	return n.and(bb.onBoard)
+}
+
+// 集合 s 里的点斜对角相邻的点
+func (bb *BitBoard) diagonals(s bitset128) bitset128 {
+	w := uint(bb.Width)
+	n := s.shl(w + 1).and(bb.notFirstCol)
+	n = n.or(s.shl(w - 1).and(bb.notLastCol))
+	n = n.or(s.shr(w - 1).and(bb.notFirstCol))
+	n = n.or(s.shr(w + 1).and(bb.notLastCol))
+	return n.and(bb.onBoard)
+}
+
+// 从 seed 出发在 within 里不断向相邻点扩张，得到连通的区域
+func (bb *BitBoard) flood(seed, within bitset128) bitset128 {
+	g := seed.and(within)
+	for {
+		n := g.or(bb.neighbors(g).and(within))
+		if n == g {
+			return g
+		}
+		g = n
+	}
+}
+
+// 一组棋子的气
+func (bb *BitBoard) liberties(chain bitset128) bitset128 {
+	return bb.neighbors(chain).and(bb.empty())
+}
+
+// 找出 p 所在的棋链，p 上没有棋子时返回 nil
+func (bb *BitBoard) GetStoneGroup(p Point) *StoneGroup {
+	color := bb.Get(p)
+	if color == None {
+		return nil
+	}
+	chain := bb.flood(bitAt(bb.index(p)), bb.stones[color])
+	sg := &StoneGroup{Color: color}
+	for s := chain; !s.isZero(); {
+		i := s.lowest()
+		s = s.andNot(bitAt(i))
+		sg.Stones = append(sg.Stones, bb.point(i))
+	}
+	for s := bb.liberties(chain); !s.isZero(); {
+		i := s.lowest()
+		s = s.andNot(bitAt(i))
+		sg.Liberties = append(sg.Liberties, bb.point(i))
+	}
+	return sg
+}
+
+// p 所在棋链的气数，没有棋子时返回 0
+func (bb *BitBoard) LibertyCount(p Point) int {
+	color := bb.Get(p)
+	if color == None {
+		return 0
+	}
+	return bb.liberties(bb.flood(bitAt(bb.index(p)), bb.stones[color])).count()
+}
+
+// 落子，规则同 Board.PlaceStone：没有气的对方棋链被提走，自杀的棋链也被提走
+// 不检查劫，需要时先用 CheckPlay 检查
+func (bb *BitBoard) PlaceStone(turn Player, p Point) error {
+	if !bb.IsOnGrid(p) { // 是否在棋盘上
+		return ErrOffBoard
+	}
+	i := bb.index(p)
+	bit := bitAt(i)
+	if !bb.empty().has(i) { // 指定位置有棋子了
+		return ErrOccupied
+	}
+	other := turn.Other()
+	bb.stones[turn] = bb.stones[turn].or(bit)
+
+	// 相邻的对方棋链，没有气的提走
+	captured := bitset128{}
+	adj := bb.neighbors(bit).and(bb.stones[other])
+	for !adj.isZero() {
+		chain := bb.flood(bitAt(adj.lowest()), bb.stones[other])
+		adj = adj.andNot(chain)
+		if bb.liberties(chain).isZero() {
+			captured = captured.or(chain)
+		}
+	}
+	bb.stones[other] = bb.stones[other].andNot(captured)
+	bb.Prisoners[turn] += captured.count()
+
+	bb.ko = -1
+	own := bb.flood(bit, bb.stones[turn])
+	libs := bb.liberties(own)
+	if libs.isZero() { // 自杀，自己的棋子也提走
+		bb.stones[turn] = bb.stones[turn].andNot(own)
+		bb.Prisoners[other] += own.count()
+	} else if captured.count() == 1 && own.count() == 1 && libs.count() == 1 {
+		bb.ko = captured.lowest()
+	}
+	return nil
+}
+
+// 检查 turn 能不能在 p 落子：不能下在棋盘外、有子的地方、简单劫的禁着点，不能自杀
+func (bb *BitBoard) CheckPlay(turn Player, p Point) error {
+	if !bb.IsOnGrid(p) {
+		return ErrOffBoard
+	}
+	i := bb.index(p)
+	bit := bitAt(i)
+	empty := bb.empty()
+	if !empty.has(i) {
+		return ErrOccupied
+	}
+	if i == bb.ko {
+		return ErrKo
+	}
+	adj := bb.neighbors(bit)
+	if !adj.and(empty).isZero() { // 有空的邻居，肯定有气
+		return nil
+	}
+	// 连上的己方棋链还有别的气
+	own := bb.flood(adj.and(bb.stones[turn]), bb.stones[turn])
+	if !bb.liberties(own).andNot(bit).isZero() {
+		return nil
+	}
+	// 能提掉相邻的对方棋链
+	other := turn.Other()
+	opp := adj.and(bb.stones[other])
+	for !opp.isZero() {
+		chain := bb.flood(bitAt(opp.lowest()), bb.stones[other])
+		opp = opp.andNot(chain)
+		if bb.liberties(chain) == bit {
+			return nil
+		}
+	}
+	return ErrSuicide
+}
+
+// 指定的位置，对某个棋子来说是否是眼，判断方法同 Board.IsPointAnEye
+func (bb *BitBoard) IsPointAnEye(p Point, color Player) bool {
+	if !bb.IsOnGrid(p) {
+		return false
+	}
+	i := bb.index(p)
+	if !bb.empty().has(i) {
+		return false // 眼必须是空点
+	}
+	bit := bitAt(i)
+	if !bb.neighbors(bit).andNot(bb.stones[color]).isZero() {
+		return false // 四个相邻的点，都必须是己方的棋
+	}
+	corners := bb.diagonals(bit)
+	friendly_corners := corners.and(bb.stones[color]).count()
+	off_board_corners := 4 - corners.count()
+	if off_board_corners > 0 { // 空点在边缘或角落
+		return off_board_corners+friendly_corners == 4
+	}
+	return friendly_corners >= 3
+}
+
+// 评估各方领土，同 Board.EvaluateTerritory
+func (bb *BitBoard) EvaluateTerritory() *Territory {
+	territory := &Territory{
+		NumBlackStones: bb.stones[Black].count(),
+		NumWhiteStones: bb.stones[White].count(),
+	}
+	empty := bb.empty()
+	for rest := empty; !rest.isZero(); {
+		region := bb.flood(bitAt(rest.lowest()), empty)
+		rest = rest.andNot(region)
+		border := bb.neighbors(region)
+		black := !border.and(bb.stones[Black]).isZero()
+		white := !border.and(bb.stones[White]).isZero()
+		switch {
+		case black && !white:
+			territory.NumBlackTerritory += region.count()
+		case white && !black:
+			territory.NumWhiteTerritory += region.count()
+		default:
+			territory.NumDame += region.count()
+			for s := region; !s.isZero(); {
+				i := s.lowest()
+				s = s.andNot(bitAt(i))
+				territory.DamePoints = append(territory.DamePoints, bb.point(i))
+			}
+		}
+	}
+	return territory
+}
+
+// 从当前局面开始随机下到双方都跳过，同 FastRandomBot：合法又不是自己眼的点等概率选择
+// passes 是开始前已经连续跳过的次数
+func (bb *BitBoard) Playout(turn Player, passes int, rnd *rand.Rand) {
+	n := int(bb.Width) * int(bb.Height)
+	max_moves := 3 * n
+	candidates := make([]int, 0, n)
+	for step := 0; step < max_moves && passes < 2; step++ {
+		candidates = candidates[:0]
+		for s := bb.empty(); !s.isZero(); {
+			i := s.lowest()
+			s = s.andNot(bitAt(i))
+			candidates = append(candidates, i)
+		}
+		moved := false
+		for len(candidates) > 0 {
+			k := rnd.Intn(len(candidates))
+			p := bb.point(candidates[k])
+			candidates[k] = candidates[len(candidates)-1]
+			candidates = candidates[:len(candidates)-1]
+
+			if bb.CheckPlay(turn, p) != nil || bb.IsPointAnEye(p, turn) {
+				continue
+			}
+			bb.PlaceStone(turn, p)
+			moved = true
+			break
+		}
+		if moved {
+			passes = 0
+		} else {
+			passes++
+			bb.ko = -1
+			if bb.PassStones {
+				bb.Prisoners[turn.Other()]++
+			}
+		}
+		turn = turn.Other()
+	}
+}
+
+// 用位棋盘从 gs 开始随机模拟一盘，按 gs 的规则计分，返回赢家
+// 棋盘要能用位棋盘表示（FitsBitBoard），给 MCTS 之类的随机模拟用
+func (gs *GameState) BitBoardPlayout(rnd *rand.Rand) Player {
+	if gs.IsOver() {
+		return gs.playoutWinner()
+	}
+	bb := gs.BoardPosition.BitBoard()
+	bb.Prisoners = gs.Prisoners
+	bb.PassStones = gs.Rules.PassStones
+	if gs.KoPoint != nil {
+		bb.ko = bb.index(*gs.KoPoint)
+	}
+	passes := 0
+	if gs.LastMove != nil && gs.LastMove.IsPass {
+		passes = 1
+	}
+	bb.Playout(gs.PlayerTurn, passes, rnd)
+	return gs.scoreTerritory(bb.EvaluateTerritory(), bb.Prisoners).Winner()
+}
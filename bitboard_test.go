@@ -0,0 +1,155 @@
+package aigo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// 随机落子序列，位棋盘和 Board 的棋链、气、眼、自杀判断、地盘都必须一致
+func TestBitBoardMatchesBoard(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	sizes := [][2]uint16{{5, 5}, {9, 9}, {11, 11}, {7, 11}, {11, 3}}
+	for _, size := range sizes {
+		w, h := size[0], size[1]
+		for game := 0; game < 5; game++ {
+			board := NewBoard(w, h)
+			bboard := NewBitBoard(w, h)
+			turn := Black
+			for i := 0; i < 3*int(w)*int(h); i++ {
+				p := Point{Row: uint16(rnd.Intn(int(h)) + 1), Col: uint16(rnd.Intn(int(w)) + 1)}
+				err1 := board.PlaceStone(turn, p)
+				err2 := bboard.PlaceStone(turn, p)
+				if err1 != err2 {
+					t.Fatalf("%dx%d %v 落子结果不一致: %v, %v", w, h, p, err1, err2)
+				}
+				if err1 != nil {
+					continue
+				}
+				turn = turn.Other()
+				for r := uint16(1); r <= h; r++ {
+					for c := uint16(1); c <= w; c++ {
+						q := Point{Row: r, Col: c}
+						sg, bsg := board.GetStoneGroup(q), bboard.GetStoneGroup(q)
+						if (sg == nil) != (bsg == nil) || (sg != nil && !sg.Equal(bsg)) {
+							t.Fatalf("%dx%d 第%d步后 %v 棋链不一致:\n%v\n%v", w, h, i, q, sg, bsg)
+						}
+						if board.LibertyCount(q) != bboard.LibertyCount(q) {
+							t.Fatalf("%dx%d 第%d步后 %v 气数不一致", w, h, i, q)
+						}
+						for _, color := range []Player{Black, White} {
+							if board.IsPointAnEye(q, color) != bboard.IsPointAnEye(q, color) {
+								t.Fatalf("%dx%d 第%d步后 %v 眼的判断不一致", w, h, i, q)
+							}
+						}
+						if sg == nil && bboard.index(q) != bboard.ko {
+							suicide := bboard.CheckPlay(turn, q) == ErrSuicide
+							if suicide != board.isSelfCapture(turn, q) {
+								t.Fatalf("%dx%d 第%d步后 %v 自杀判断不一致", w, h, i, q)
+							}
+						}
+					}
+				}
+				t1, t2 := board.EvaluateTerritory(), bboard.EvaluateTerritory()
+				if t1.NumBlackStones != t2.NumBlackStones || t1.NumWhiteStones != t2.NumWhiteStones ||
+					t1.NumBlackTerritory != t2.NumBlackTerritory || t1.NumWhiteTerritory != t2.NumWhiteTerritory ||
+					t1.NumDame != t2.NumDame || len(t2.DamePoints) != t2.NumDame {
+					t.Fatalf("%dx%d 第%d步后地盘不一致:\n%+v\n%+v", w, h, i, t1, t2)
+				}
+			}
+			if !bboard.Board().Equal(board) || !board.BitBoard().Equal(bboard) {
+				t.Fatalf("%dx%d 转换以后不一致", w, h)
+			}
+		}
+	}
+}
+
+func TestBitBoardKo(t *testing.T) {
+	game := gameFromDiagram(t, ChineseRules, Black,
+		".....",
+		".....",
+		".XO..",
+		"XO.O.",
+		".XO..",
+	)
+	bboard := game.BoardPosition.BitBoard()
+	if err := bboard.PlaceStone(Black, Point{Row: 2, Col: 3}); err != nil { // 提劫
+		t.Fatal(err)
+	}
+	if bboard.Prisoners[Black] != 1 {
+		t.Errorf("黑棋应该提了一个子: %v", bboard.Prisoners)
+	}
+	if err := bboard.CheckPlay(White, Point{Row: 2, Col: 2}); err != ErrKo {
+		t.Errorf("不能马上提回: %v", err)
+	}
+	if err := bboard.CheckPlay(White, Point{Row: 5, Col: 5}); err != nil {
+		t.Errorf("空的地方可以下: %v", err)
+	}
+	bboard.PlaceStone(White, Point{Row: 5, Col: 5})
+	if err := bboard.CheckPlay(Black, Point{Row: 2, Col: 2}); err != nil {
+		t.Errorf("劫材以后黑棋可以粘劫: %v", err)
+	}
+	if NewBitBoard(11, 11) == nil || FitsBitBoard(12, 9) || FitsBitBoard(9, 0) {
+		t.Error("位棋盘的大小限制不对")
+	}
+}
+
+func TestBitBoardPlayout(t *testing.T) {
+	game := NewGameWithRules(9, 9, ChineseRules)
+	bboard := game.BoardPosition.BitBoard()
+	bboard.Playout(Black, 0, rand.New(rand.NewSource(1)))
+	territory := bboard.EvaluateTerritory()
+	if territory.NumBlackStones == 0 || territory.NumWhiteStones == 0 {
+		t.Errorf("随机模拟以后双方都应该有棋子: %+v", territory)
+	}
+	// 双方都不填自己的眼，模拟结束时所有的空点都是眼
+	for r := uint16(1); r <= 9; r++ {
+		for c := uint16(1); c <= 9; c++ {
+			p := Point{Row: r, Col: c}
+			if bboard.Get(p) == None && !bboard.IsPointAnEye(p, Black) && !bboard.IsPointAnEye(p, White) &&
+				(bboard.CheckPlay(Black, p) == nil || bboard.CheckPlay(White, p) == nil) {
+				t.Errorf("%v 还可以下", p)
+			}
+		}
+	}
+
+	w1 := game.BitBoardPlayout(rand.New(rand.NewSource(2)))
+	w2 := game.BitBoardPlayout(rand.New(rand.NewSource(2)))
+	if w1 != w2 || w1 == None {
+		t.Errorf("同样的种子结果应该一样: %v %v", w1, w2)
+	}
+}
+
+// AGA 规则跳过时交出的子，位棋盘模拟和 GameState 上模拟的算法一样
+// 1x1 的棋盘上哪里都不能下，只能跳过，结果只由跳过交出的子和贴目决定
+func TestBitBoardPlayoutPassStones(t *testing.T) {
+	rules := AGARules
+	rules.Komi = -0.5
+	game, _ := NewGameWithRules(1, 1, rules).ApplyMove(NewPass())
+	slow := game.Copy()
+	for !slow.IsOver() {
+		slow.Play(NewFastRandomBot().SelectMove(slow))
+	}
+	want := slow.Winner().Winner()
+	if got := game.BitBoardPlayout(rand.New(rand.NewSource(1))); got != want || want != Black {
+		t.Errorf("位棋盘模拟的赢家 %v, 应该是 %v", got, want)
+	}
+}
+
+func BenchmarkBitBoardPlayout(b *testing.B) {
+	game := NewGameOfSize(9, 9)
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < b.N; i++ {
+		game.BitBoardPlayout(rnd)
+	}
+}
+
+func BenchmarkFastRandomBotPlayout(b *testing.B) {
+	bot := NewFastRandomBot()
+	for i := 0; i < b.N; i++ {
+		gs := NewGameOfSize(9, 9)
+		for !gs.IsOver() {
+			gs.Play(bot.SelectMove(gs))
+		}
+		gs.Winner()
+	}
+}
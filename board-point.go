@@ -13,17 +13,3 @@ func NewBoardPoint(row, col uint16, player Player) BoardPoint {
 	bp.Player = player
 	return bp
 }
-
-var (
-	BoardPointHashCode2 = MapInitTest()
-	EmptyBoardHashCode2 = int64(9181944435492932548)
-)
-
-func MapInitTest() map[BoardPoint]int64 {
-	mappp := make(map[BoardPoint]int64)
-	mappp[NewBoardPoint(1, 1, None)] = 6402364705153495313
-	mappp[NewBoardPoint(1, 1, White)] = 444191475187629924
-	mappp[NewBoardPoint(1, 1, Black)] = 3180807544946524599
-
-	return mappp
-}
@@ -0,0 +1,142 @@
+package aigo
+
+// 可撤销的落子记录
+// 搜索时在同一个棋盘上 Play / Undo，不需要每个节点复制一份棋盘
+type BoardChange struct {
+	Pnt          Point   // 落子位置
+	Color        Player  // 落子颜色
+	Captured     []Point // 被提走的对方棋子
+	SelfCaptured []Point // 自杀时被提走的己方棋子，包括落下的这颗
+	HashDelta    int64   // 这步落子带来的 Zobrist 哈希变化，异或回去即可还原
+	hashHiDelta  int64   // 128位哈希高64位的变化
+}
+
+// 在棋盘上原地落子，返回可用于 Undo 的记录
+// 规则同 PlaceStone，不做劫争和自杀的判断，自杀的棋子会被提走
+func (b *Board) Play(turn Player, p Point) (*BoardChange, error) {
+	if !b.IsOnGrid(p) { // 是否在棋盘上
+		return nil, ErrOffBoard
+	}
+	if b.Get(p) != None { // 指定位置有棋子了
+		return nil, ErrOccupied
+	}
+	before, beforeHi := b.hash, b.hashHi
+	var captured, self_captured []int32
+	b.place(turn, b.index(p), &captured, &self_captured)
+
+	c := &BoardChange{Pnt: p, Color: turn, HashDelta: before ^ b.hash, hashHiDelta: beforeHi ^ b.hashHi}
+	if len(captured) > 0 {
+		c.Captured = make([]Point, len(captured))
+		for k, e := range captured {
+			c.Captured[k] = b.point(e)
+		}
+	}
+	if len(self_captured) > 0 {
+		c.SelfCaptured = make([]Point, len(self_captured))
+		for k, e := range self_captured {
+			c.SelfCaptured[k] = b.point(e)
+		}
+	}
+	return c, nil
+}
+
+// 撤销 Play 的落子，棋盘恢复到落子之前的状态
+// 必须按 Play 的相反顺序撤销
+func (b *Board) Undo(c *BoardChange) {
+	i := b.index(c.Pnt)
+	b.setColor(i, None)
+	for _, p := range c.Captured {
+		b.setColor(b.index(p), c.Color.Other())
+	}
+	for _, p := range c.SelfCaptured {
+		if p != c.Pnt {
+			b.setColor(b.index(p), c.Color)
+		}
+	}
+	b.hash ^= c.HashDelta
+	b.hashHi ^= c.hashHiDelta
+
+	// 落子点周围的棋链可能被拆开，被提的棋子要重新连成棋链，
+	// 这些棋子周围的棋链气数也变了，统一重建
+	// 重建过的棋子都打上 gen 标记，数气只会标记空点，不会覆盖它
+	gen := b.nextMark()
+	rebuild := func(s int32) {
+		if s < 0 || b.grid[s] == None || b.mark[s] == gen {
+			return
+		}
+		b.rebuildChain(s, gen)
+	}
+	for _, n := range b.adj[4*i : 4*i+4] {
+		rebuild(n)
+	}
+	for _, list := range [][]Point{c.Captured, c.SelfCaptured} {
+		for _, p := range list {
+			k := b.index(p)
+			rebuild(k)
+			for _, n := range b.adj[4*k : 4*k+4] {
+				rebuild(n)
+			}
+		}
+	}
+}
+
+// 从棋子 s 开始按颜色重新找出整条棋链，重建链表和气数
+// 找到的棋子用 gen 标记
+func (b *Board) rebuildChain(s int32, gen uint32) {
+	color := b.grid[s]
+	b.mark[s] = gen
+	stones := []int32{s}
+	for k := 0; k < len(stones); k++ {
+		e := stones[k]
+		for _, n := range b.adj[4*e : 4*e+4] {
+			if n >= 0 && b.grid[n] == color && b.mark[n] != gen {
+				b.mark[n] = gen
+				stones = append(stones, n)
+			}
+		}
+	}
+	for k, e := range stones {
+		b.head[e] = s
+		b.next[e] = stones[(k+1)%len(stones)]
+	}
+	b.size[s] = int32(len(stones))
+	b.libs[s] = b.countLiberties(s)
+}
+
+// 在空点 p 落子是否是自杀，不需要真的落子
+// 有空的相邻点、能提掉对方的子、或者连上一条不止一口气的己方棋链，都不是自杀
+func (b *Board) isSelfCapture(turn Player, p Point) bool {
+	i := b.index(p)
+	for _, n := range b.adj[4*i : 4*i+4] {
+		if n < 0 {
+			continue
+		}
+		switch b.grid[n] {
+		case None:
+			return false
+		case turn:
+			if b.libs[b.head[n]] > 1 {
+				return false
+			}
+		default:
+			if b.libs[b.head[n]] == 1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// 落子后形成的劫：单个棋子提掉对方单个棋子，并且自己只剩被提的那一口气
+// 返回对方下一步不能马上提回的位置，没有劫时返回 nil
+func (b *Board) koPoint(c *BoardChange) *Point {
+	if c == nil || len(c.Captured) != 1 || len(c.SelfCaptured) > 0 {
+		return nil
+	}
+	h := b.head[b.index(c.Pnt)]
+	if b.size[h] != 1 || b.libs[h] != 1 {
+		return nil
+	}
+	ko := c.Captured[0]
+	return &ko
+}
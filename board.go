@@ -3,39 +3,104 @@ package aigo
 import (
 	"bytes"
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"log"
 	"strings"
 )
 
-// 棋盘类，defined by width, height, and a map of stone groups.
+// 棋盘类，defined by width, height and a flat grid of points.
+// 交叉点按行展开存放在数组里，下标为 (Row-1)*Width + (Col-1)。
+// 同一棋链的棋子用循环链表串起来，每个棋子记录所在棋链的代表点，
+// 代表点上记录棋链的棋子数和气数，落子和提子时增量更新。
 type Board struct {
 	Width, Height uint16
-	stoneMap      map[Point]*StoneGroup // 从棋盘点映射到棋链的map对象
-	hash          int64                 // 使用 Zobrist哈希 来增强劫争判断用的
+	grid          []Player // 每个交叉点上的棋子颜色
+	head          []int32  // 棋子所在棋链的代表点
+	next          []int32  // 棋链内的循环链表，指向同一棋链的下一个棋子
+	libs          []int32  // 以代表点为下标，棋链的气数
+	size          []int32  // 以代表点为下标，棋链的棋子数
+	adj           []int32  // 每个交叉点上下左右四个相邻点，-1 表示在棋盘外；只读，各副本共享
+	zob           []int32  // 每个交叉点在 Zobrist 哈希表中的编号；只读，各副本共享
+	ring          []int32  // 每个交叉点周围一圈 8 个点，顺序见 Pattern3x3，-1 表示在棋盘外；只读，各副本共享
+	pat           []uint16 // 每个交叉点周围的 3x3 模式，棋子变化时增量更新
+	mark          []uint32 // 数气时的访问标记
+	markGen       uint32   // 当前的访问标记值
+	hash          int64    // 使用 Zobrist哈希 来增强劫争判断用的
+	hashHi        int64    // 128位 Zobrist 哈希的高64位
 }
 
 // 构造一个指定长宽的棋盘
+// 长宽都不能超过 MaxBoardSize
 func NewBoard(w uint16, h uint16) *Board {
-	m := make(map[Point]*StoneGroup, w*h)
-	return &Board{w, h, m, EmptyBoardHashCode}
+	if w > MaxBoardSize || h > MaxBoardSize {
+		log.Panicf("棋盘 %dx%d 超过了最大尺寸 %d", w, h, MaxBoardSize)
+	}
+	n := int(w) * int(h)
+	b := &Board{
+		Width:  w,
+		Height: h,
+		grid:   make([]Player, n),
+		head:   make([]int32, n),
+		next:   make([]int32, n),
+		libs:   make([]int32, n),
+		size:   make([]int32, n),
+		adj:    make([]int32, 4*n),
+		zob:    make([]int32, n),
+		ring:   make([]int32, 8*n),
+		pat:    make([]uint16, n),
+		hash:   Zobrist.Empty.Lo,
+		hashHi: Zobrist.Empty.Hi,
+	}
+	for i := 0; i < n; i++ {
+		p := b.point(int32(i))
+		b.zob[i] = int32(zobristIndex(p))
+		for k, np := range p.Neighbors() {
+			if b.IsOnGrid(np) {
+				b.adj[4*i+k] = b.index(np)
+			} else {
+				b.adj[4*i+k] = -1
+			}
+		}
+		for k, np := range p.ring() {
+			if b.IsOnGrid(np) {
+				b.ring[8*i+k] = b.index(np)
+			} else {
+				b.ring[8*i+k] = -1
+				b.pat[i] |= uint16(patternOffBoard) << (2 * k)
+			}
+		}
+	}
+	return b
 }
 
 // Method returns a deep copy of a Board struct.
 func (b *Board) Copy() *Board {
-	nb := NewBoard(b.Width, b.Height)
-	for _, e := range b.GetAllStoneGroups() {
-		csg := e.Copy()
-		for _, p := range csg.Stones {
-			nb.stoneMap[p] = csg
-		}
-	}
+	nb := &Board{
+		Width:  b.Width,
+		Height: b.Height,
+		grid:   make([]Player, len(b.grid)),
+		head:   make([]int32, len(b.head)),
+		next:   make([]int32, len(b.next)),
+		libs:   make([]int32, len(b.libs)),
+		size:   make([]int32, len(b.size)),
+		adj:    b.adj,
+		zob:    b.zob,
+		ring:   b.ring,
+		pat:    make([]uint16, len(b.pat)),
+		hash:   b.hash,
+		hashHi: b.hashHi,
+	}
+	copy(nb.grid, b.grid)
+	copy(nb.pat, b.pat)
+	copy(nb.head, b.head)
+	copy(nb.next, b.next)
+	copy(nb.libs, b.libs)
+	copy(nb.size, b.size)
 	return nb
 }
 
 // Method returns a boolean comparison of two Board structs.
-// 比较两个棋盘
+// 比较两个棋盘，棋链完全由每个点的颜色决定，所以只需要比较颜色
 func (b *Board) Equal(c *Board) bool {
 	if c == nil {
 		return false
@@ -43,19 +108,8 @@ func (b *Board) Equal(c *Board) bool {
 	if b.Width != c.Width || b.Height != c.Height {
 		return false
 	}
-	bsg, csg := b.GetAllStoneGroups(), c.GetAllStoneGroups() // 所有棋链对比
-	if len(bsg) != len(csg) {
-		return false
-	}
-	for _, e1 := range bsg {
-		t := false
-		for _, e2 := range csg {
-			if e1.Equal(e2) { // 比较棋链
-				t = true
-				break
-			}
-		}
-		if !t {
+	for i, e := range b.grid {
+		if c.grid[i] != e {
 			return false
 		}
 	}
@@ -72,7 +126,8 @@ func (b *Board) String() string {
 }
 
 // 定义一个字符串变量,其中每个字母代表围棋棋盘的一列。忽略字母I，以免与数字1混淆。
-const COLS = "ABCDEFGHJKLMNOPQRST"
+// 一共 25 个字母，对应 MaxBoardSize
+const COLS = "ABCDEFGHJKLMNOPQRSTUVWXYZ"
 
 func (b *Board) PrintBoard() string {
 	bbuf := strings.Builder{}
@@ -80,23 +135,19 @@ func (b *Board) PrintBoard() string {
 	for row := int(b.Height); row > 0; row-- {
 		bbuf.WriteString(fmt.Sprintf("%02d ", row))
 		for col := 1; col <= int(b.Width); col++ {
-			pp, ex := b.stoneMap[Point{Col: uint16(col), Row: uint16(row)}]
-			if !ex {
+			switch b.Get(Point{Col: uint16(col), Row: uint16(row)}) {
+			case Black:
+				bbuf.WriteString("X") // 黑棋
+			case White:
+				bbuf.WriteString("O") // 白棋
+			default:
 				bbuf.WriteString(".") // 空白位置
-			} else if pp == nil {
-				log.Printf("Row:%d,Col:%d is nil", row, col)
-			} else {
-				if pp.Color == Black {
-					bbuf.WriteString("X") // 黑棋
-				} else {
-					bbuf.WriteString("O") // 白棋
-				}
 			}
 		}
 		bbuf.WriteString("\r\n")
 	}
 	bbuf.WriteString("   ")
-	for i := 0; i < int(b.Height); i++ {
+	for i := 0; i < int(b.Width); i++ {
 		bbuf.WriteByte(COLS[i])
 	}
 	bbuf.WriteString("\r\n")
@@ -111,43 +162,84 @@ func BytesToInt(bys []byte) int {
 	return int(data)
 }
 
+// 交叉点在数组中的下标，调用方需保证点在棋盘上
+func (b *Board) index(p Point) int32 {
+	return int32(p.Row-1)*int32(b.Width) + int32(p.Col-1)
+}
+
+// 数组下标对应的交叉点
+func (b *Board) point(i int32) Point {
+	return Point{Row: uint16(i/int32(b.Width)) + 1, Col: uint16(i%int32(b.Width)) + 1}
+}
+
 // 返回棋盘某个交叉点的内容
 // 如果已经落子，返回它的棋链对象
 // 否则返回 nil
+// 返回的棋链是按当前棋盘生成的快照，之后棋盘的变化不会反映到它上面
 func (b *Board) GetStoneGroup(p Point) *StoneGroup {
-	if sg, e := b.stoneMap[p]; e {
-		return sg
+	if !b.IsOnGrid(p) {
+		return nil
 	}
-	return nil
+	i := b.index(p)
+	if b.grid[i] == None {
+		return nil
+	}
+	return b.stoneGroupAt(b.head[i])
+}
+
+// 根据棋链代表点生成棋链对象
+func (b *Board) stoneGroupAt(h int32) *StoneGroup {
+	sg := &StoneGroup{
+		Color:     b.grid[h],
+		Stones:    make([]Point, 0, b.size[h]),
+		Liberties: make([]Point, 0, b.libs[h]),
+	}
+	gen := b.nextMark()
+	s := h
+	for {
+		sg.Stones = append(sg.Stones, b.point(s))
+		for _, n := range b.adj[4*s : 4*s+4] {
+			if n >= 0 && b.grid[n] == None && b.mark[n] != gen {
+				b.mark[n] = gen
+				sg.Liberties = append(sg.Liberties, b.point(n))
+			}
+		}
+		s = b.next[s]
+		if s == h {
+			break
+		}
+	}
+	return sg
 }
 
 // 返回棋盘某个位置的内容
 // 否则 是具体的棋子颜色
 func (b *Board) Get(p Point) Player {
-	sg := b.GetStoneGroup(p)
-	if sg == nil {
+	if !b.IsOnGrid(p) {
 		return None
 	}
-	return sg.Color
+	return b.grid[b.index(p)]
+}
+
+// 返回某个位置所在棋链的气数，空点或棋盘外返回 0
+func (b *Board) LibertyCount(p Point) int {
+	if !b.IsOnGrid(p) {
+		return 0
+	}
+	i := b.index(p)
+	if b.grid[i] == None {
+		return 0
+	}
+	return int(b.libs[b.head[i]])
 }
 
 // Method returns a slice of pointers to all unique StoneGroups.
 // 返回排重过的棋链列表
 func (b *Board) GetAllStoneGroups() []*StoneGroup {
 	v := []*StoneGroup{}
-	for _, e := range b.stoneMap {
-		if e == nil {
-			continue
-		}
-		f := false // 是否已经记录了某个棋链？
-		for _, vsg := range v {
-			if vsg == e {
-				f = true
-				break
-			}
-		}
-		if !f {
-			v = append(v, e)
+	for i, e := range b.grid {
+		if e != None && b.head[i] == int32(i) {
+			v = append(v, b.stoneGroupAt(int32(i)))
 		}
 	}
 	return v
@@ -160,123 +252,185 @@ func (b *Board) GetAllStoneGroups() []*StoneGroup {
 func (b *Board) PlaceStone(turn Player, p Point) error {
 	// error checking
 	if !b.IsOnGrid(p) { // 是否在棋盘上
-		return errors.New("given point is not within the board")
+		return ErrOffBoard
 	}
-	if b.GetStoneGroup(p) != nil { // 指定位置有棋子了
-		return errors.New("given point on the board is already occupied")
+	if b.Get(p) != None { // 指定位置有棋子了
+		return ErrOccupied
 	}
+	b.place(turn, b.index(p), nil, nil)
+	return nil
+}
 
-	// initialize utilities
-	// 棋链列表排重增加
-	add_group := func(list []*StoneGroup, item *StoneGroup) []*StoneGroup {
-		for _, e := range list {
-			if e == item {
-				return list
-			}
-		}
-		return append(list, item)
-	}
-	adjacent_same_color := []*StoneGroup{}
-	adjacent_opposite_color := []*StoneGroup{}
-	adjacent_liberties := []Point{}
-
-	// collect information about neighbors
-	for _, e := range p.Neighbors() {
-		if !b.IsOnGrid(e) {
+// 在空点 i 落子，返回被提走的对方棋子数
+// captured 不为 nil 时，被提走的对方棋子下标会追加到里面
+// 落子后自己的棋链没有气（自杀），整条棋链也会被提走，下标追加到 self_captured 里
+func (b *Board) place(turn Player, i int32, captured, self_captured *[]int32) int {
+	b.setColor(i, turn)
+	b.head[i] = i
+	b.next[i] = i
+	b.size[i] = 1
+	b.libs[i] = 0
+	// 使用 Zobrist哈希 后，增加的代码
+	// 对棋盘应用这个交叉点与棋子颜色所对应的哈希值
+	b.xorStone(i, turn)
+
+	// 相邻的棋链，同一棋链只处理一次
+	var seen [4]int32
+	num_seen := 0
+	var dead [4]int32
+	num_dead := 0
+	h := i
+	for _, n := range b.adj[4*i : 4*i+4] {
+		if n < 0 || b.grid[n] == None {
 			continue
 		}
-		nsg := b.GetStoneGroup(e)
-		if nsg == nil { // 这个位置是空位， 加气
-			adjacent_liberties = append(adjacent_liberties, e)
+		nh := b.head[n]
+		dup := false
+		for _, e := range seen[:num_seen] {
+			if e == nh {
+				dup = true
+				break
+			}
+		}
+		if dup {
 			continue
 		}
-		if nsg.Color == turn {
-			adjacent_same_color = add_group(adjacent_same_color, nsg)
-		} else {
-			adjacent_opposite_color = add_group(adjacent_opposite_color, nsg)
+		seen[num_seen] = nh
+		num_seen++
+		if b.grid[n] == turn { // 同样颜色的合并棋链
+			h = b.merge(h, nh)
+		} else { // 不同颜色的减少气
+			b.libs[nh]--
+			if b.libs[nh] == 0 {
+				dead[num_dead] = nh
+				num_dead++
+			}
 		}
 	}
+	b.libs[h] = b.countLiberties(h)
 
-	// apply game logic
-	newsg := StoneGroup{turn, []Point{p}, adjacent_liberties}
-
-	for _, e := range adjacent_same_color { // 同样颜色的增加棋链
-		err := newsg.MergeIn(e)
-		if err != nil {
-			return err
-		}
+	num_captured := 0
+	for _, e := range dead[:num_dead] { // 如果气数为0， 提取棋子
+		num_captured += b.removeChain(e, captured)
 	}
-	for _, e := range newsg.Stones { // 修改棋子到棋链的映射关系
-		b.stoneMap[e] = &newsg
+	if b.libs[h] == 0 { // 自杀，自己的棋子也提走
+		b.removeChain(h, self_captured)
 	}
+	return num_captured
+}
 
-	// 使用 Zobrist哈希 后，增加的代码
-	// 对棋盘应用这个交叉点与棋子颜色所对应的哈希值
-	b.hash ^= BoardPointHashCode[NewBoardPoint(p.Row, p.Col, turn)]
-
-	for _, e := range adjacent_opposite_color { // 不同颜色的减少气
-		err := e.RemoveLiberty(p)
-		if err != nil {
-			return err
+// 合并两条棋链，小的并入大的，返回合并后的代表点
+func (b *Board) merge(a, c int32) int32 {
+	if b.size[a] < b.size[c] {
+		a, c = c, a
+	}
+	s := c
+	for {
+		b.head[s] = a
+		s = b.next[s]
+		if s == c {
+			break
 		}
 	}
-	for _, e := range adjacent_opposite_color { // 如果气数为0， 提取棋子
-		if e.NumLiberties() == 0 {
+	b.next[a], b.next[c] = b.next[c], b.next[a]
+	b.size[a] += b.size[c]
+	return a
+}
 
-			err := b.removeStones(e)
-			if err != nil {
-				return err
+// 数棋链的气，h 是棋链代表点
+func (b *Board) countLiberties(h int32) int32 {
+	gen := b.nextMark()
+	count := int32(0)
+	s := h
+	for {
+		for _, n := range b.adj[4*s : 4*s+4] {
+			if n >= 0 && b.grid[n] == None && b.mark[n] != gen {
+				b.mark[n] = gen
+				count++
 			}
 		}
+		s = b.next[s]
+		if s == h {
+			break
+		}
 	}
-	return nil
+	return count
 }
 
-// Method removes a StoneGroup from the board.
-func (b *Board) removeStones(sg *StoneGroup) error {
-	for _, e := range sg.Stones {
-		if b.stoneMap[e] != sg {
-			return errors.New("StoneGroup to be removed does not match board state")
+// 取一个新的访问标记值
+func (b *Board) nextMark() uint32 {
+	if b.mark == nil {
+		b.mark = make([]uint32, len(b.grid))
+	}
+	b.markGen++
+	if b.markGen == 0 { // 溢出后清空重来
+		for i := range b.mark {
+			b.mark[i] = 0
 		}
+		b.markGen = 1
 	}
-	for _, e := range sg.Stones {
-		for _, p := range e.Neighbors() {
-			if !b.IsOnGrid(p) {
+	return b.markGen
+}
+
+// Method removes a StoneGroup from the board.
+// 提走整条棋链，相邻的棋链增加气，返回提走的棋子数
+func (b *Board) removeChain(h int32, captured *[]int32) int {
+	color := b.grid[h]
+	n := int(b.size[h])
+	s := h
+	for {
+		nxt := b.next[s]
+		b.setColor(s, None)
+		if captured != nil {
+			*captured = append(*captured, s)
+		}
+		// 在 Zobrist哈希中，需要通过逆应用这步动作的哈希值来实现提子
+		b.xorStone(s, color)
+
+		var seen [4]int32
+		num_seen := 0
+		for _, e := range b.adj[4*s : 4*s+4] {
+			if e < 0 || b.grid[e] == None || b.grid[e] == color {
 				continue
 			}
-			nsg := b.GetStoneGroup(p)
-			if nsg != nil && nsg != sg {
-				// 加气可以不成功
-				nsg.AddLiberty(e)
+			eh := b.head[e]
+			dup := false
+			for _, x := range seen[:num_seen] {
+				if x == eh {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				seen[num_seen] = eh
+				num_seen++
+				b.libs[eh]++
 			}
 		}
-		delete(b.stoneMap, e)
-		// b.stoneMap[e] = nil 绝对不能这么做，会有问题的。
-
-		// 在 Zobrist哈希中，需要通过逆应用这步动作的哈希值来实现提子
-		b.hash ^= BoardPointHashCode[NewBoardPoint(e.Row, e.Col, sg.Color)]
-
+		s = nxt
+		if s == h {
+			break
+		}
 	}
-	return nil
+	return n
 }
 
 // 指定的位置，对某个棋子来说是否是眼
 // 往自己的眼下棋是不允许的。
 // 所有的相邻交叉点以及4个对角相邻点中有3个以上都是己方的棋子才算眼
 func (b *Board) IsPointAnEye(p Point, color Player) bool {
-	sg := b.GetStoneGroup(p)
-	if sg != nil {
+	if b.Get(p) != None {
 		return false // 眼必须是空点
 	}
 
 	// 四个相邻的点，都必须是己方的棋
 	for _, neighbor := range p.Neighbors() {
 		if b.IsOnGrid(neighbor) {
-			nsg := b.GetStoneGroup(neighbor)
-			if nsg == nil {
+			nc := b.Get(neighbor)
+			if nc == None {
 				return false // 邻居是空点，不算眼
 			}
-			if nsg.Color != color {
+			if nc != color {
 				return false // 不是自己的棋，不算眼
 			}
 		}
@@ -293,11 +447,8 @@ func (b *Board) IsPointAnEye(p Point, color Player) bool {
 	}
 	for _, corner := range corners {
 		if b.IsOnGrid(corner) {
-			csg := b.GetStoneGroup(corner)
-			if csg != nil {
-				if csg.Color == color {
-					friendly_corners++
-				}
+			if b.Get(corner) == color {
+				friendly_corners++
 			}
 		} else {
 			off_board_corners++
@@ -325,6 +476,30 @@ func (b *Board) GetZobristHash() int64 {
 	return b.hash
 }
 
+// 返回 128 位的 Zobrist 哈希值，低 64 位与 GetZobristHash 相同
+func (b *Board) GetZobristHash128() Hash128 {
+	return Hash128{Hi: b.hashHi, Lo: b.hash}
+}
+
+// 不用增量维护的值，按棋盘上的棋子从头计算 Zobrist 哈希
+// 用来校验 PlaceStone、Undo 等增量更新的结果
+func (b *Board) RecomputeZobristHash() Hash128 {
+	h := Zobrist.Empty
+	for i, e := range b.grid {
+		if e != None {
+			h = h.Xor(Zobrist.Stones[b.zob[i]][e-Black])
+		}
+	}
+	return h
+}
+
+// 在交叉点 i 放上或拿走 color 颜色的棋子时更新哈希值
+func (b *Board) xorStone(i int32, color Player) {
+	k := Zobrist.Stones[b.zob[i]][color-Black]
+	b.hash ^= k.Lo
+	b.hashHi ^= k.Hi
+}
+
 // 评估各方领土
 // 假设棋盘上所有死棋都被提走了，然后开始计算胜负
 func (b *Board) EvaluateTerritory() *Territory {
@@ -379,6 +554,14 @@ func (b *Board) EvaluateTerritory() *Territory {
 	return territory
 }
 
+// 评估各方领土，同时找出双活的棋链、公气和眼
+// 找公气要在棋盘上 Play / Undo，比 EvaluateTerritory 慢，只在终局计分时使用
+func (b *Board) EvaluateTerritoryWithSeki() *Territory {
+	territory := b.EvaluateTerritory()
+	b.findSeki(territory)
+	return territory
+}
+
 // 递归收集棋盘空点位置归属
 // start_pos 开始位置
 // visited 用于判断该位置是否已经收集
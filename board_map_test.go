@@ -0,0 +1,203 @@
+package aigo
+
+import (
+	"errors"
+)
+
+// 基于 map 的棋盘实现，是 Board 改为数组存储之前的版本。
+// 只在测试里保留下来，用于基准测试对比，以及随机对局的交叉验证。
+type MapBoard struct {
+	Width, Height uint16
+	stoneMap      map[Point]*StoneGroup // 从棋盘点映射到棋链的map对象
+	hash          int64                 // 使用 Zobrist哈希 来增强劫争判断用的
+}
+
+// 构造一个指定长宽的 map 棋盘
+func NewMapBoard(w uint16, h uint16) *MapBoard {
+	m := make(map[Point]*StoneGroup, w*h)
+	return &MapBoard{w, h, m, EmptyBoardHashCode}
+}
+
+// Method returns a deep copy of a MapBoard struct.
+func (b *MapBoard) Copy() *MapBoard {
+	nb := NewMapBoard(b.Width, b.Height)
+	for _, e := range b.GetAllStoneGroups() {
+		csg := e.Copy()
+		for _, p := range csg.Stones {
+			nb.stoneMap[p] = csg
+		}
+	}
+	nb.hash = b.hash
+	return nb
+}
+
+// 比较两个棋盘
+func (b *MapBoard) Equal(c *MapBoard) bool {
+	if c == nil {
+		return false
+	}
+	if b.Width != c.Width || b.Height != c.Height {
+		return false
+	}
+	bsg, csg := b.GetAllStoneGroups(), c.GetAllStoneGroups() // 所有棋链对比
+	if len(bsg) != len(csg) {
+		return false
+	}
+	for _, e1 := range bsg {
+		t := false
+		for _, e2 := range csg {
+			if e1.Equal(e2) { // 比较棋链
+				t = true
+				break
+			}
+		}
+		if !t {
+			return false
+		}
+	}
+	return true
+}
+
+// 检查棋子是否在棋盘上
+func (b *MapBoard) IsOnGrid(p Point) bool {
+	b1 := (1 <= p.Row) && (p.Row <= b.Height)
+	b2 := (1 <= p.Col) && (p.Col <= b.Width)
+	return b1 && b2
+}
+
+// 返回棋盘某个交叉点的棋链，没有棋子返回 nil
+func (b *MapBoard) GetStoneGroup(p Point) *StoneGroup {
+	if sg, e := b.stoneMap[p]; e {
+		return sg
+	}
+	return nil
+}
+
+// 返回棋盘某个位置的棋子颜色
+func (b *MapBoard) Get(p Point) Player {
+	sg := b.GetStoneGroup(p)
+	if sg == nil {
+		return None
+	}
+	return sg.Color
+}
+
+// 返回 Zobrist 哈希值
+func (b *MapBoard) GetZobristHash() int64 {
+	return b.hash
+}
+
+// 返回排重过的棋链列表
+func (b *MapBoard) GetAllStoneGroups() []*StoneGroup {
+	v := []*StoneGroup{}
+	for _, e := range b.stoneMap {
+		if e == nil {
+			continue
+		}
+		f := false // 是否已经记录了某个棋链？
+		for _, vsg := range v {
+			if vsg == e {
+				f = true
+				break
+			}
+		}
+		if !f {
+			v = append(v, e)
+		}
+	}
+	return v
+}
+
+// 指定位置下棋，规则同 Board.PlaceStone
+func (b *MapBoard) PlaceStone(turn Player, p Point) error {
+	if !b.IsOnGrid(p) { // 是否在棋盘上
+		return ErrOffBoard
+	}
+	if b.GetStoneGroup(p) != nil { // 指定位置有棋子了
+		return ErrOccupied
+	}
+
+	// 棋链列表排重增加
+	add_group := func(list []*StoneGroup, item *StoneGroup) []*StoneGroup {
+		for _, e := range list {
+			if e == item {
+				return list
+			}
+		}
+		return append(list, item)
+	}
+	adjacent_same_color := []*StoneGroup{}
+	adjacent_opposite_color := []*StoneGroup{}
+	adjacent_liberties := []Point{}
+
+	for _, e := range p.Neighbors() {
+		if !b.IsOnGrid(e) {
+			continue
+		}
+		nsg := b.GetStoneGroup(e)
+		if nsg == nil { // 这个位置是空位， 加气
+			adjacent_liberties = append(adjacent_liberties, e)
+			continue
+		}
+		if nsg.Color == turn {
+			adjacent_same_color = add_group(adjacent_same_color, nsg)
+		} else {
+			adjacent_opposite_color = add_group(adjacent_opposite_color, nsg)
+		}
+	}
+
+	newsg := StoneGroup{turn, []Point{p}, adjacent_liberties}
+	for _, e := range adjacent_same_color { // 同样颜色的增加棋链
+		err := newsg.MergeIn(e)
+		if err != nil {
+			return err
+		}
+	}
+	for _, e := range newsg.Stones { // 修改棋子到棋链的映射关系
+		b.stoneMap[e] = &newsg
+	}
+	b.hash ^= Zobrist.Point(p, turn).Lo
+
+	for _, e := range adjacent_opposite_color { // 不同颜色的减少气
+		err := e.RemoveLiberty(p)
+		if err != nil {
+			return err
+		}
+	}
+	for _, e := range adjacent_opposite_color { // 如果气数为0， 提取棋子
+		if e.NumLiberties() == 0 {
+			err := b.removeStones(e)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if newsg.NumLiberties() == 0 { // 自杀，自己的棋子也提走
+		return b.removeStones(&newsg)
+	}
+	return nil
+}
+
+// Method removes a StoneGroup from the board.
+func (b *MapBoard) removeStones(sg *StoneGroup) error {
+	for _, e := range sg.Stones {
+		if b.stoneMap[e] != sg {
+			return errors.New("StoneGroup to be removed does not match board state")
+		}
+	}
+	for _, e := range sg.Stones {
+		for _, p := range e.Neighbors() {
+			if !b.IsOnGrid(p) {
+				continue
+			}
+			nsg := b.GetStoneGroup(p)
+			if nsg != nil && nsg != sg {
+				// 加气可以不成功
+				nsg.AddLiberty(e)
+			}
+		}
+		delete(b.stoneMap, e)
+		b.hash ^= Zobrist.Point(e, sg.Color).Lo
+	}
+	return nil
+}
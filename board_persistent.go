@@ -0,0 +1,231 @@
+package aigo
+
+// 不可变的棋盘，落子返回新的棋盘，和原来的棋盘共享没有变化的部分。
+// 交叉点按 Board 的下标顺序每 32 个分成一块（持久化向量的叶子），
+// 落子时只复制有棋子变化的块和块指针数组，其他块新旧棋盘共用。
+// MCTS 之类要保存大量局面的地方，内存随着变化的数量增长，而不是随着局面数增长。
+// 不保存棋链，需要时从棋子出发搜索。
+type PersistentBoard struct {
+	Width, Height uint16
+	leaves        []*persistentLeaf
+	hash          int64 // 同 Board 的 Zobrist 哈希
+	hashHi        int64
+}
+
+const persistentLeafBits = 5
+const persistentLeafSize = 1 << persistentLeafBits
+
+type persistentLeaf [persistentLeafSize]Player
+
+// 所有空棋盘共用的叶子
+var emptyPersistentLeaf = &persistentLeaf{}
+
+// 构造一个指定长宽的空棋盘
+func NewPersistentBoard(w uint16, h uint16) *PersistentBoard {
+	n := int(w) * int(h)
+	b := &PersistentBoard{
+		Width:  w,
+		Height: h,
+		leaves: make([]*persistentLeaf, (n+persistentLeafSize-1)/persistentLeafSize),
+		hash:   Zobrist.Empty.Lo,
+		hashHi: Zobrist.Empty.Hi,
+	}
+	for k := range b.leaves {
+		b.leaves[k] = emptyPersistentLeaf
+	}
+	return b
+}
+
+// 从 Board 生成不可变棋盘
+func (b *Board) Persistent() *PersistentBoard {
+	pb := NewPersistentBoard(b.Width, b.Height)
+	var owned uint64
+	for i, c := range b.grid {
+		if c != None {
+			pb.set(int32(i), c, &owned)
+		}
+	}
+	pb.hash, pb.hashHi = b.hash, b.hashHi
+	return pb
+}
+
+// 生成一个内容相同的可变的 Board
+func (b *PersistentBoard) Board() *Board {
+	nb := NewBoard(b.Width, b.Height)
+	for i := int32(0); i < int32(b.Width)*int32(b.Height); i++ {
+		if c := b.at(i); c != None {
+			nb.PlaceStone(c, nb.point(i))
+		}
+	}
+	return nb
+}
+
+func (b *PersistentBoard) index(p Point) int32 {
+	return int32(p.Row-1)*int32(b.Width) + int32(p.Col-1)
+}
+
+func (b *PersistentBoard) point(i int32) Point {
+	return Point{Row: uint16(i/int32(b.Width)) + 1, Col: uint16(i%int32(b.Width)) + 1}
+}
+
+func (b *PersistentBoard) at(i int32) Player {
+	return b.leaves[i>>persistentLeafBits][i&(persistentLeafSize-1)]
+}
+
+// 修改新棋盘上的一个交叉点，叶子第一次修改时复制一份，owned 记录已经复制过的叶子
+func (b *PersistentBoard) set(i int32, c Player, owned *uint64) {
+	k := i >> persistentLeafBits
+	if *owned&(1<<uint(k)) == 0 {
+		leaf := *b.leaves[k]
+		b.leaves[k] = &leaf
+		*owned |= 1 << uint(k)
+	}
+	b.leaves[k][i&(persistentLeafSize-1)] = c
+}
+
+// 检查棋子是否在棋盘上
+func (b *PersistentBoard) IsOnGrid(p Point) bool {
+	b1 := (1 <= p.Row) && (p.Row <= b.Height)
+	b2 := (1 <= p.Col) && (p.Col <= b.Width)
+	return b1 && b2
+}
+
+// 返回棋盘某个位置的棋子颜色，棋盘外返回 None
+func (b *PersistentBoard) Get(p Point) Player {
+	if !b.IsOnGrid(p) {
+		return None
+	}
+	return b.at(b.index(p))
+}
+
+// 返回 Zobrist 哈希值
+func (b *PersistentBoard) GetZobristHash() int64 {
+	return b.hash
+}
+
+// 返回 128 位的 Zobrist 哈希值
+func (b *PersistentBoard) GetZobristHash128() Hash128 {
+	return Hash128{Hi: b.hashHi, Lo: b.hash}
+}
+
+// 比较两个棋盘，共用的叶子不用逐个比较
+func (b *PersistentBoard) Equal(c *PersistentBoard) bool {
+	if c == nil || b.Width != c.Width || b.Height != c.Height {
+		return false
+	}
+	for k, leaf := range b.leaves {
+		if leaf != c.leaves[k] && *leaf != *c.leaves[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// 找出 p 所在的棋链，p 上没有棋子时返回 nil
+func (b *PersistentBoard) GetStoneGroup(p Point) *StoneGroup {
+	color := b.Get(p)
+	if color == None {
+		return nil
+	}
+	stones, libs := b.chainAt(b.index(p), make([]bool, int(b.Width)*int(b.Height)))
+	sg := &StoneGroup{Color: color}
+	for _, e := range stones {
+		sg.Stones = append(sg.Stones, b.point(e))
+	}
+	for _, e := range libs {
+		sg.Liberties = append(sg.Liberties, b.point(e))
+	}
+	return sg
+}
+
+// 从 i 出发找出整条棋链和它的气，visited 用来标记访问过的点
+func (b *PersistentBoard) chainAt(i int32, visited []bool) (stones []int32, libs []int32) {
+	color := b.at(i)
+	visited[i] = true
+	stones = []int32{i}
+	for k := 0; k < len(stones); k++ {
+		for _, np := range b.point(stones[k]).Neighbors() {
+			if !b.IsOnGrid(np) {
+				continue
+			}
+			n := b.index(np)
+			if visited[n] {
+				continue
+			}
+			switch b.at(n) {
+			case color:
+				visited[n] = true
+				stones = append(stones, n)
+			case None:
+				visited[n] = true
+				libs = append(libs, n)
+			}
+		}
+	}
+	// 气的标记要清掉，相邻的其他棋链还要用
+	for _, e := range libs {
+		visited[e] = false
+	}
+	return stones, libs
+}
+
+// 落子，返回新的棋盘，原来的棋盘不变
+// 规则同 Board.PlaceStone，没有气的对方棋链被提走，自杀的棋链也被提走
+func (b *PersistentBoard) Play(turn Player, p Point) (*PersistentBoard, error) {
+	if !b.IsOnGrid(p) { // 是否在棋盘上
+		return nil, ErrOffBoard
+	}
+	i := b.index(p)
+	if b.at(i) != None { // 指定位置有棋子了
+		return nil, ErrOccupied
+	}
+	nb := &PersistentBoard{
+		Width:  b.Width,
+		Height: b.Height,
+		leaves: make([]*persistentLeaf, len(b.leaves)),
+		hash:   b.hash,
+		hashHi: b.hashHi,
+	}
+	copy(nb.leaves, b.leaves)
+	var owned uint64
+	nb.place(i, turn, &owned)
+
+	visited := make([]bool, int(b.Width)*int(b.Height))
+	captured := false
+	for _, np := range p.Neighbors() {
+		if !nb.IsOnGrid(np) {
+			continue
+		}
+		n := nb.index(np)
+		if nb.at(n) != turn.Other() || visited[n] {
+			continue
+		}
+		if stones, libs := nb.chainAt(n, visited); len(libs) == 0 { // 如果气数为0， 提取棋子
+			nb.remove(stones, &owned)
+			captured = true
+		}
+	}
+	// 提了子肯定有气，没有提子时才可能是自杀
+	if !captured {
+		if stones, libs := nb.chainAt(i, visited); len(libs) == 0 { // 自杀，自己的棋子也提走
+			nb.remove(stones, &owned)
+		}
+	}
+	return nb, nil
+}
+
+func (b *PersistentBoard) place(i int32, c Player, owned *uint64) {
+	b.set(i, c, owned)
+	k := Zobrist.Point(b.point(i), c)
+	b.hash ^= k.Lo
+	b.hashHi ^= k.Hi
+}
+
+func (b *PersistentBoard) remove(stones []int32, owned *uint64) {
+	for _, e := range stones {
+		k := Zobrist.Point(b.point(e), b.at(e))
+		b.hash ^= k.Lo
+		b.hashHi ^= k.Hi
+		b.set(e, None, owned)
+	}
+}
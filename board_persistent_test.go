@@ -0,0 +1,221 @@
+package aigo
+
+import (
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"os"
+	"runtime"
+	"testing"
+)
+
+// 随机落子序列，不可变棋盘和 Board 的结果必须一致，而且之前的棋盘都不能被改动
+func TestPersistentBoardMatchesBoard(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	for game := 0; game < 10; game++ {
+		board := NewBoard(9, 9)
+		pboard := NewPersistentBoard(9, 9)
+		history := []*PersistentBoard{pboard}
+		snapshots := []*Board{board.Copy()}
+		turn := Black
+		for i := 0; i < 200; i++ {
+			p := Point{Row: uint16(rnd.Intn(9) + 1), Col: uint16(rnd.Intn(9) + 1)}
+			err1 := board.PlaceStone(turn, p)
+			next, err2 := pboard.Play(turn, p)
+			if err1 != err2 {
+				t.Fatalf("%v 落子结果不一致: %v, %v", p, err1, err2)
+			}
+			if err1 != nil {
+				continue
+			}
+			pboard = next
+			if pboard.GetZobristHash128() != board.GetZobristHash128() {
+				t.Fatalf("第%d步 %v 哈希不一致", i, p)
+			}
+			for r := uint16(1); r <= 9; r++ {
+				for c := uint16(1); c <= 9; c++ {
+					q := Point{Row: r, Col: c}
+					sg, psg := board.GetStoneGroup(q), pboard.GetStoneGroup(q)
+					if (sg == nil) != (psg == nil) || (sg != nil && !sg.Equal(psg)) {
+						t.Fatalf("第%d步后 %v 棋链不一致:\n%v\n%v", i, q, sg, psg)
+					}
+				}
+			}
+			history = append(history, pboard)
+			snapshots = append(snapshots, board.Copy())
+			turn = turn.Other()
+		}
+		for k, e := range history {
+			if !e.Board().Equal(snapshots[k]) || e.GetZobristHash128() != snapshots[k].GetZobristHash128() {
+				t.Fatalf("第%d个棋盘被后面的落子改动了", k)
+			}
+			if !snapshots[k].Persistent().Equal(e) {
+				t.Fatalf("第%d个棋盘转换以后不一致", k)
+			}
+		}
+	}
+}
+
+func TestPersistentBoardSharing(t *testing.T) {
+	pboard := NewPersistentBoard(19, 19)
+	next, err := pboard.Play(Black, Point{Row: 1, Col: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared := 0
+	for k := range pboard.leaves {
+		if pboard.leaves[k] == next.leaves[k] {
+			shared++
+		}
+	}
+	if shared != len(pboard.leaves)-1 {
+		t.Errorf("落一个子只应该复制一块，共用了 %d / %d", shared, len(pboard.leaves))
+	}
+	if pboard.Get(Point{Row: 1, Col: 1}) != None || next.Get(Point{Row: 1, Col: 1}) != Black {
+		t.Error("原来的棋盘被修改了")
+	}
+	if _, err := next.Play(White, Point{Row: 1, Col: 1}); err != ErrOccupied {
+		t.Errorf("有子的地方不能下: %v", err)
+	}
+	if _, err := next.Play(White, Point{Row: 20, Col: 1}); err != ErrOffBoard {
+		t.Errorf("棋盘外不能下: %v", err)
+	}
+}
+
+// 保存整盘棋的所有局面，和每步复制一份 Board 比较
+func BenchmarkPersistentBoardHistory(b *testing.B) {
+	points := benchmarkPoints(400)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		board := NewPersistentBoard(19, 19)
+		history := make([]*PersistentBoard, 0, len(points))
+		turn := Black
+		for _, p := range points {
+			if next, err := board.Play(turn, p); err == nil {
+				board = next
+				history = append(history, board)
+				turn = turn.Other()
+			}
+		}
+	}
+}
+
+func BenchmarkBoardCopyHistory(b *testing.B) {
+	points := benchmarkPoints(400)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		board := NewBoard(19, 19)
+		history := make([]*Board, 0, len(points))
+		turn := Black
+		for _, p := range points {
+			if board.PlaceStone(turn, p) == nil {
+				history = append(history, board.Copy())
+				turn = turn.Other()
+			}
+		}
+	}
+}
+
+func BenchmarkPersistentBoardPlay(b *testing.B) {
+	board, _ := benchmarkBoards()
+	pboard := board.Persistent()
+	p := Point{Row: 10, Col: 10}
+	for pboard.Get(p) != None {
+		p.Col = p.Col%19 + 1
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		pboard.Play(Black, p)
+	}
+}
+
+func BenchmarkBoardCopyPlay(b *testing.B) {
+	board, _ := benchmarkBoards()
+	p := Point{Row: 10, Col: 10}
+	for board.Get(p) != None {
+		p.Col = p.Col%19 + 1
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		board.Copy().PlaceStone(Black, p)
+	}
+}
+
+// 搜索树节点从不可变棋盘生成的局面和 ApplyMove 得到的一样
+func TestMCTSNodeState(t *testing.T) {
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	bot := NewMCTSAgent(50, 1.4)
+	gs := NewGameOfSize(5, 5)
+	root := bot.build_tree(gs)
+	states := fullStates(root, gs, nil)
+	k := 0
+	var check func(node *MCTSNode)
+	check = func(node *MCTSNode) {
+		want := states[k]
+		k++
+		got := node.state()
+		if node != root && node.game_state.BoardPosition != nil {
+			t.Fatal("搜索树的节点不应该保存 Board")
+		}
+		if !got.BoardPosition.Equal(want.BoardPosition) || got.ZobristHash128() != want.ZobristHash128() || got.IsOver() != want.IsOver() {
+			t.Fatalf("节点的局面不对:\n%v\n应该是:\n%v", got, want)
+		}
+		for _, child := range node.children {
+			check(child)
+		}
+	}
+	check(root)
+}
+
+// MCTS 搜索树占用的内存：节点保存不可变棋盘，和每个节点保存 ApplyMove 得到的完整局面比较
+func BenchmarkMCTSTreePersistent(b *testing.B) {
+	benchmarkMCTSTree(b, false)
+}
+
+func BenchmarkMCTSTreeFullStates(b *testing.B) {
+	benchmarkMCTSTree(b, true)
+}
+
+func benchmarkMCTSTree(b *testing.B, full bool) {
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	bot := NewMCTSAgent(100, 1.4)
+	bot.rnd = rand.New(rand.NewSource(1))
+	gs := NewGameOfSize(19, 19)
+	for _, p := range benchmarkPoints(60) {
+		if ngs, err := gs.ApplyMove(NewPlay(p)); err == nil {
+			gs = ngs
+		}
+	}
+	var retained uint64
+	for i := 0; i < b.N; i++ {
+		before := heapInUse()
+		root := bot.build_tree(gs)
+		var states []*GameState
+		if full {
+			states = fullStates(root, gs, nil)
+		}
+		retained += heapInUse() - before
+		runtime.KeepAlive(root)
+		runtime.KeepAlive(states)
+	}
+	b.ReportMetric(float64(retained)/float64(b.N), "tree-B/op")
+}
+
+// 原来的搜索树每个节点都保存 ApplyMove 得到的完整局面
+func fullStates(node *MCTSNode, gs *GameState, states []*GameState) []*GameState {
+	states = append(states, gs)
+	for _, child := range node.children {
+		ngs, _ := gs.ApplyMove(*child.move)
+		states = fullStates(child, ngs, states)
+	}
+	return states
+}
+
+func heapInUse() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
@@ -1,6 +1,7 @@
 package aigo
 
 import (
+	"math/rand"
 	"testing"
 )
 
@@ -223,3 +224,116 @@ func TestEmptyTriangle(t *testing.T) {
 		t.Errorf("%v != %v", blackStoneGroup.Liberties, []Point{{3, 2}, {2, 3}, {1, 3}})
 	}
 }
+
+// 随机落子序列，数组棋盘和 map 棋盘的结果必须一致
+func TestBoardMatchesMapBoard(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for game := 0; game < 20; game++ {
+		board := NewBoard(9, 9)
+		mboard := NewMapBoard(9, 9)
+		turn := Black
+		for i := 0; i < 200; i++ {
+			p := Point{Row: uint16(rnd.Intn(9) + 1), Col: uint16(rnd.Intn(9) + 1)}
+			err1 := board.PlaceStone(turn, p)
+			err2 := mboard.PlaceStone(turn, p)
+			if (err1 == nil) != (err2 == nil) {
+				t.Fatalf("%v 落子结果不一致: %v, %v", p, err1, err2)
+			}
+			if board.GetZobristHash() != mboard.GetZobristHash() {
+				t.Fatalf("第%d步 %v 哈希不一致", i, p)
+			}
+			for r := uint16(1); r <= 9; r++ {
+				for c := uint16(1); c <= 9; c++ {
+					q := Point{Row: r, Col: c}
+					sg, msg := board.GetStoneGroup(q), mboard.GetStoneGroup(q)
+					if (sg == nil) != (msg == nil) || (sg != nil && !sg.Equal(msg)) {
+						t.Fatalf("第%d步后 %v 棋链不一致:\n%v\n%v", i, q, sg, msg)
+					}
+					if sg != nil && board.LibertyCount(q) != msg.NumLiberties() {
+						t.Fatalf("第%d步后 %v 气数不一致", i, q)
+					}
+				}
+			}
+			turn = turn.Other()
+		}
+	}
+}
+
+// 基准测试用的随机落子序列
+func benchmarkPoints(n int) []Point {
+	rnd := rand.New(rand.NewSource(42))
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{Row: uint16(rnd.Intn(19) + 1), Col: uint16(rnd.Intn(19) + 1)}
+	}
+	return points
+}
+
+func BenchmarkBoardPlaceStone(b *testing.B) {
+	points := benchmarkPoints(400)
+	for i := 0; i < b.N; i++ {
+		board := NewBoard(19, 19)
+		turn := Black
+		for _, p := range points {
+			if board.PlaceStone(turn, p) == nil {
+				turn = turn.Other()
+			}
+		}
+	}
+}
+
+func BenchmarkMapBoardPlaceStone(b *testing.B) {
+	points := benchmarkPoints(400)
+	for i := 0; i < b.N; i++ {
+		board := NewMapBoard(19, 19)
+		turn := Black
+		for _, p := range points {
+			if board.PlaceStone(turn, p) == nil {
+				turn = turn.Other()
+			}
+		}
+	}
+}
+
+func benchmarkBoards() (*Board, *MapBoard) {
+	board := NewBoard(19, 19)
+	mboard := NewMapBoard(19, 19)
+	turn := Black
+	for _, p := range benchmarkPoints(200) {
+		if board.PlaceStone(turn, p) == nil {
+			mboard.PlaceStone(turn, p)
+			turn = turn.Other()
+		}
+	}
+	return board, mboard
+}
+
+func BenchmarkBoardCopy(b *testing.B) {
+	board, _ := benchmarkBoards()
+	for i := 0; i < b.N; i++ {
+		board.Copy()
+	}
+}
+
+func BenchmarkMapBoardCopy(b *testing.B) {
+	_, mboard := benchmarkBoards()
+	for i := 0; i < b.N; i++ {
+		mboard.Copy()
+	}
+}
+
+func BenchmarkBoardEqual(b *testing.B) {
+	board, _ := benchmarkBoards()
+	other := board.Copy()
+	for i := 0; i < b.N; i++ {
+		board.Equal(other)
+	}
+}
+
+func BenchmarkMapBoardEqual(b *testing.B) {
+	_, mboard := benchmarkBoards()
+	other := mboard.Copy()
+	for i := 0; i < b.N; i++ {
+		mboard.Equal(other)
+	}
+}
@@ -0,0 +1,69 @@
+package aigo
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestRectangularBoard(t *testing.T) {
+	game := NewGameOfSize(7, 11)
+	b := game.BoardPosition
+	if b.Width != 7 || b.Height != 11 {
+		t.Fatalf("应该是 7 列 11 行: %dx%d", b.Width, b.Height)
+	}
+	if n := len(game.LegalMoves()); n != 7*11+2 {
+		t.Errorf("空棋盘应该有 %d 个合法动作: %d", 7*11+2, n)
+	}
+
+	// 最上面一行、最右边一列也要算到
+	game, _ = game.ApplyMove(NewPlay(Point{Row: 11, Col: 7}))
+	game, _ = game.ApplyMove(NewPlay(Point{Row: 11, Col: 1}))
+	game, _ = game.ApplyMove(NewPlay(Point{Row: 10, Col: 7}))
+	if d := CaptureDiff(game); d != -1 {
+		t.Errorf("轮到白棋，棋子差应该是 -1: %d", d)
+	}
+	for _, m := range game.LegalMoves() {
+		if m.IsPlay && !game.BoardPosition.IsOnGrid(m.Pnt) {
+			t.Errorf("合法动作 %v 不在棋盘上", m)
+		}
+	}
+
+	lines := strings.Split(strings.TrimRight(game.BoardPosition.PrintBoard(), "\r\n"), "\r\n")
+	if len(lines) != 12 || lines[0] != "11 O.....X" || lines[11] != "   ABCDEFG" {
+		t.Errorf("打印的棋盘不对:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestLargeBoard(t *testing.T) {
+	game := NewGameOfSize(21, 21)
+	if n := len(game.LegalMoves()); n != 21*21+2 {
+		t.Errorf("空棋盘应该有 %d 个合法动作: %d", 21*21+2, n)
+	}
+	lines := strings.Split(strings.TrimRight(game.BoardPosition.PrintBoard(), "\r\n"), "\r\n")
+	if lines[len(lines)-1] != "   ABCDEFGHJKLMNOPQRSTUV" {
+		t.Errorf("列号不对: %q", lines[len(lines)-1])
+	}
+
+	// 随机下棋，Zobrist 哈希和数目都要覆盖到整个棋盘
+	rnd := rand.New(rand.NewSource(1))
+	for k := 0; k < 400; k++ {
+		p := Point{Row: uint16(rnd.Intn(21)) + 1, Col: uint16(rnd.Intn(21)) + 1}
+		if !game.IsValidMove(NewPlay(p)) {
+			continue
+		}
+		game, _ = game.ApplyMove(NewPlay(p))
+	}
+	b := game.BoardPosition
+	if b.GetZobristHash128() != b.RecomputeZobristHash() {
+		t.Error("21x21 棋盘的哈希不对")
+	}
+	territory := b.EvaluateTerritory()
+	total := territory.NumBlackStones + territory.NumWhiteStones + territory.NumBlackTerritory + territory.NumWhiteTerritory + territory.NumDame
+	if total != 21*21 {
+		t.Errorf("数目应该覆盖整个棋盘: %d", total)
+	}
+	if m := NewPlay(Point{Row: 21, Col: 21}); m.StringChessRecord() != "V21" {
+		t.Errorf("右上角应该是 V21: %v", m.StringChessRecord())
+	}
+}
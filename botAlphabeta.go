@@ -25,12 +25,14 @@ func (bot *AlphaBetaAgent) SelectMove(gs *GameState) Move {
 	best_score := MIN_SCORE
 	best_black := MIN_SCORE
 	best_white := MIN_SCORE
-	for _, possible_move := range gs.LegalMoves() {
-		next_state, err := gs.ApplyMove(possible_move)
+	work := gs.Copy() // 在副本上原地落子和撤销，不修改调用方的对象
+	for _, possible_move := range work.LegalMoves() {
+		undo, err := work.Play(possible_move)
 		if err != nil {
 			log.Fatalln(err)
 		}
-		opponent_best_outcome := next_state.AlphaBetaResult(bot.MaxDepth, best_black, best_white, bot.EvalFn)
+		opponent_best_outcome := work.AlphaBetaResult(bot.MaxDepth, best_black, best_white, bot.EvalFn)
+		work.Undo(undo)
 		our_best_outcome := -1 * opponent_best_outcome
 
 		if len(best_moves) <= 0 || our_best_outcome > best_score {
@@ -57,7 +59,11 @@ func (bot *AlphaBetaAgent) SelectMove(gs *GameState) Move {
 // 多了2个输入参数， 目前的 best_black, best_white
 func (gs *GameState) AlphaBetaResult(max_depth, best_black, best_white int, evalFn func(gs *GameState) int) int {
 	if gs.IsOver() {
-		if gs.Winner() == gs.PlayerTurn {
+		result := gs.Winner()
+		if result.IsDraw() { // 和棋不算输赢
+			return 0
+		}
+		if result.Winner() == gs.PlayerTurn {
 			return MAX_SCORE
 		} else {
 			return MIN_SCORE
@@ -70,12 +76,13 @@ func (gs *GameState) AlphaBetaResult(max_depth, best_black, best_white int, eval
 	best_so_far := MIN_SCORE
 
 	for _, candidate_move := range gs.LegalMoves() {
-		next_state, err := gs.ApplyMove(candidate_move)
+		undo, err := gs.Play(candidate_move)
 		if err != nil {
 			log.Panicln(err)
 		}
 		// 递归自身
-		opponent_best_result := next_state.AlphaBetaResult(max_depth-1, best_black, best_white, evalFn)
+		opponent_best_result := gs.AlphaBetaResult(max_depth-1, best_black, best_white, evalFn)
+		gs.Undo(undo)
 
 		our_result := -1 * opponent_best_result
 		if our_result > best_so_far {
@@ -8,6 +8,7 @@ import (
 // 加速的随机下棋机器人
 type FastRandomBot struct {
 	point_cache []Point // 缓存的棋盘点集合，每次都需要把它打散下
+	EyeAnalysis bool    // 用 EyeStatus 判断眼，只不填真眼，假眼照样下
 }
 
 func NewFastRandomBot() *FastRandomBot {
@@ -40,7 +41,7 @@ func (bot *FastRandomBot) SelectMove(gs *GameState) Move {
 		candidate := bot.point_cache[i]
 
 		if gs.IsValidMove(NewPlay(candidate)) {
-			if !gs.BoardPosition.IsPointAnEye(candidate, gs.PlayerTurn) {
+			if !gs.BoardPosition.isOwnEye(candidate, gs.PlayerTurn, bot.EyeAnalysis) {
 				return NewPlay(candidate)
 			}
 		}
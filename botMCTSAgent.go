@@ -3,20 +3,40 @@ package aigo
 import (
 	"log"
 	"math"
+	"math/rand"
+	"time"
 )
 
 //
 type MCTSAgent struct {
 	num_rounds  int //
 	temperature float64
+	rnd         *rand.Rand // 位棋盘随机模拟用的随机数
 }
 
 func NewMCTSAgent(numrounds int, temperature float64) *MCTSAgent {
-	bot := &MCTSAgent{num_rounds: numrounds, temperature: temperature}
+	bot := &MCTSAgent{num_rounds: numrounds, temperature: temperature, rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
 	return bot
 }
 
 func (bot *MCTSAgent) SelectMove(gs *GameState) Move {
+	root := bot.build_tree(gs)
+
+	var best_move *Move
+	best_pct := -1.0
+	for _, child := range root.children {
+		child_pct := child.winning_frac(gs.PlayerTurn)
+		if child_pct > best_pct {
+			best_pct = child_pct
+			best_move = child.move
+		}
+	}
+	log.Println(len(root.children))
+	return *best_move
+}
+
+// 从 gs 开始模拟 num_rounds 次，返回搜索树的根节点
+func (bot *MCTSAgent) build_tree(gs *GameState) *MCTSNode {
 	root := NewMCTSNode(gs, nil, nil)
 
 	for i := 0; i < bot.num_rounds; i++ {
@@ -31,25 +51,14 @@ func (bot *MCTSAgent) SelectMove(gs *GameState) Move {
 		}
 
 		log.Printf("curr %d :%v ", i, node.move)
-		winner := bot.simulate_random_game(node.game_state)
+		winner := bot.simulate_random_game(node.state())
 
 		for node != nil {
 			node.record_win(winner)
 			node = node.parent
 		}
 	}
-
-	var best_move *Move
-	best_pct := -1.0
-	for _, child := range root.children {
-		child_pct := child.winning_frac(gs.PlayerTurn)
-		if child_pct > best_pct {
-			best_pct = child_pct
-			best_move = child.move
-		}
-	}
-	log.Println(len(root.children))
-	return *best_move
+	return root
 }
 
 // 使用 搜索树置信区间上界公式 找一个应该探索的节点
@@ -77,19 +86,23 @@ func (bot *MCTSAgent) select_child(node MCTSNode) *MCTSNode {
 }
 
 // 随机模拟一盘游戏
+// 小棋盘用位棋盘模拟，快得多；大棋盘用 FastRandomBot 在 GameState 上模拟
 func (bot *MCTSAgent) simulate_random_game(gs *GameState) Player {
+	if FitsBitBoard(gs.BoardPosition.Width, gs.BoardPosition.Height) {
+		return gs.BitBoardPlayout(bot.rnd)
+	}
 	bots := map[Player]IAgent{
 		White: NewFastRandomBot(),
 		Black: NewFastRandomBot(),
 	}
-	var err error
+	gs = gs.Copy() // 模拟在副本上原地进行，不影响搜索树里的节点
 	for !gs.IsOver() {
 		bot_move := bots[gs.PlayerTurn].SelectMove(gs)
-		gs, err = gs.ApplyMove(bot_move)
+		_, err := gs.Play(bot_move)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 	}
-	return gs.Winner()
+	return gs.playoutWinner()
 }
@@ -28,12 +28,14 @@ func NewDepthPrunedAgent(depth int, evalFn func(gs *GameState) int) *DepthPruned
 func (bot *DepthPrunedAgent) SelectMove(gs *GameState) Move {
 	best_moves := []Move{}
 	best_score := MIN_SCORE
-	for _, possible_move := range gs.LegalMoves() {
-		next_state, err := gs.ApplyMove(possible_move)
+	work := gs.Copy() // 在副本上原地落子和撤销，不修改调用方的对象
+	for _, possible_move := range work.LegalMoves() {
+		undo, err := work.Play(possible_move)
 		if err != nil {
 			log.Fatalln(err)
 		}
-		opponent_best_outcome := next_state.BestResult(bot.MaxDepth, bot.EvalFn)
+		opponent_best_outcome := work.BestResult(bot.MaxDepth, bot.EvalFn)
+		work.Undo(undo)
 		our_best_outcome := -1 * opponent_best_outcome
 
 		if len(best_moves) <= 0 || our_best_outcome > best_score {
@@ -51,7 +53,11 @@ func (bot *DepthPrunedAgent) SelectMove(gs *GameState) Move {
 // 通过剪枝算法，棋局评估函数 ，找最佳走法
 func (gs *GameState) BestResult(max_depth int, evalFn func(gs *GameState) int) int {
 	if gs.IsOver() {
-		if gs.Winner() == gs.PlayerTurn {
+		result := gs.Winner()
+		if result.IsDraw() { // 和棋不算输赢
+			return 0
+		}
+		if result.Winner() == gs.PlayerTurn {
 			return MAX_SCORE
 		} else {
 			return MIN_SCORE
@@ -64,12 +70,13 @@ func (gs *GameState) BestResult(max_depth int, evalFn func(gs *GameState) int) i
 	best_so_far := MIN_SCORE
 
 	for _, candidate_move := range gs.LegalMoves() {
-		next_state, err := gs.ApplyMove(candidate_move)
+		undo, err := gs.Play(candidate_move)
 		if err != nil {
 			log.Panicln(err)
 		}
 
-		opponent_best_result := next_state.BestResult(max_depth-1, evalFn)
+		opponent_best_result := gs.BestResult(max_depth-1, evalFn)
+		gs.Undo(undo)
 
 		our_result := -1 * opponent_best_result
 		if our_result > best_so_far {
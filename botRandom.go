@@ -12,6 +12,7 @@ type IAgent interface {
 // 随机下棋机器人
 type RandomBot struct {
 	IAgent
+	EyeAnalysis bool // 用 EyeStatus 判断眼，只不填真眼，假眼照样下
 }
 
 func (bot RandomBot) SelectMove(gs *GameState) Move {
@@ -23,7 +24,7 @@ func (bot RandomBot) SelectMove(gs *GameState) Move {
 			candidate := Point{Row: r, Col: c}
 			if gs.IsValidMove(NewPlay(candidate)) {
 				// log.Println("pass IsValidMove")
-				if !gs.BoardPosition.IsPointAnEye(candidate, gs.PlayerTurn) {
+				if !gs.BoardPosition.isOwnEye(candidate, gs.PlayerTurn, bot.EyeAnalysis) {
 					// log.Println("pass IsPointAnEye")
 					candidates = append(candidates, candidate)
 				}
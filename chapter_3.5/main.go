@@ -1,63 +1,78 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"ghj1976/aigo"
 	"log"
-	"math"
 	"math/rand"
 	"os"
-	"path"
-	"strings"
-	"time"
 )
 
-// 给棋盘的不同动作生成zobrist哈希
+// 校验 Zobrist 哈希
+// 随机下棋、随机悔棋，每一步都检查棋盘增量维护的哈希值与从头计算的结果是否一致
 func main() {
-	rand.Seed(time.Now().Unix())
+	games := flag.Int("games", 20, "每种棋盘下多少盘")
+	seed := flag.Int64("seed", 1, "随机数种子")
+	flag.Parse()
 
-	buf := strings.Builder{}
-	buf.WriteString("package aigo\n")
-	buf.WriteString("\n")
+	rnd := rand.New(rand.NewSource(*seed))
+	sizes := [][2]uint16{{5, 5}, {9, 9}, {13, 13}, {19, 19}, {25, 25}, {7, 11}}
 
-	buf.WriteString("var (\n")
-	buf.WriteString(fmt.Sprintf("	EmptyBoardHashCode = int64(%d)\n", rand.Int63n(math.MaxInt64)))
-	buf.WriteString("	BoardPointHashCode = MapInit()\n")
-	buf.WriteString(")\n")
-	buf.WriteString("\n")
-
-	buf.WriteString("func MapInit() map[BoardPoint]int64 {\n")
-	buf.WriteString("	mappp := make(map[BoardPoint]int64)\n")
-	for i := 1; i <= 19; i++ {
-		for j := 1; j <= 19; j++ {
-			buf.WriteString(fmt.Sprintf("	mappp[NewBoardPoint(%d, %d, None)] = int64(%d)\n", i, j, rand.Int63n(math.MaxInt64)))
-			buf.WriteString(fmt.Sprintf("	mappp[NewBoardPoint(%d, %d, Black)] = int64(%d)\n", i, j, rand.Int63n(math.MaxInt64)))
-			buf.WriteString(fmt.Sprintf("	mappp[NewBoardPoint(%d, %d, White)] = int64(%d)\n", i, j, rand.Int63n(math.MaxInt64)))
+	failed := 0
+	for _, size := range sizes {
+		checked := 0
+		for i := 0; i < *games; i++ {
+			n, err := verifyGame(rnd, size[0], size[1])
+			checked += n
+			if err != nil {
+				log.Printf("%dx%d 第%d盘: %v", size[0], size[1], i+1, err)
+				failed++
+			}
 		}
+		fmt.Printf("%dx%d: 校验了 %d 个局面\n", size[0], size[1], checked)
 	}
-
-	buf.WriteString("	return mappp\n")
-	buf.WriteString("}\n")
-	buf.WriteString("\n")
-
-	WriteString2File("../../zobrist.go", buf.String())
+	if failed > 0 {
+		os.Exit(1)
+	}
+	fmt.Println("增量哈希全部正确")
 }
 
-// WriteString2File 写文件
-func WriteString2File(shortFileName, txt string) {
-	filename := path.Join(".", shortFileName)
-	log.Println(filename)
-
-	fo, err := os.Create(filename)
-	if err != nil {
-		log.Println(err)
-		return
+// 下一盘随机棋，返回校验过的局面数
+func verifyGame(rnd *rand.Rand, w, h uint16) (int, error) {
+	game := aigo.NewGameOfSize(w, h)
+	undos := []*aigo.UndoRecord{}
+	checked := 0
+	for step := 0; step < int(w)*int(h)*3 && !game.IsOver(); step++ {
+		if len(undos) > 0 && rnd.Intn(5) == 0 { // 随机悔一步棋
+			game.Undo(undos[len(undos)-1])
+			undos = undos[:len(undos)-1]
+		} else {
+			move := randomMove(rnd, game)
+			u, err := game.Play(move)
+			if err != nil {
+				return checked, err
+			}
+			undos = append(undos, u)
+		}
+		board := game.BoardPosition
+		if board.GetZobristHash128() != board.RecomputeZobristHash() {
+			return checked, fmt.Errorf("第%d步后哈希不一致\n%s", step, board.PrintBoard())
+		}
+		checked++
 	}
-	defer fo.Close()
-	fo.WriteString("\xEF\xBB\xBF") // 写入UTF-8 BOM ， 避免文件乱码
-	fo.WriteString(txt)
+	return checked, nil
+}
 
-	if err != nil {
-		log.Println(err)
-		return
+// 随机选一个合法的落子，找不到就跳过
+func randomMove(rnd *rand.Rand, game *aigo.GameState) aigo.Move {
+	board := game.BoardPosition
+	for try := 0; try < 20; try++ {
+		p := aigo.Point{Row: uint16(rnd.Intn(int(board.Height)) + 1), Col: uint16(rnd.Intn(int(board.Width)) + 1)}
+		move := aigo.NewPlay(p)
+		if game.IsValidMove(move) {
+			return move
+		}
 	}
+	return aigo.NewPass()
 }
@@ -18,33 +18,45 @@ func main() {
 
 	game := aigo.NewGameOfSize(9, 9)
 	bot := aigo.RandomBot{}
+	var result *aigo.MoveResult // 上一步的结果
 
 	for !game.IsOver() {
 		fmt.Printf("\x1bc") // 清屏
 		fmt.Println(game.BoardPosition.PrintBoard())
+		if result != nil && len(result.Captured) > 0 {
+			fmt.Printf("%v 提掉 %d 子，提子数 黑:%d 白:%d\n", result.Player, len(result.Captured), result.Prisoners[aigo.Black], result.Prisoners[aigo.White])
+		}
 
 		var move aigo.Move
 		var err error
 		if game.PlayerTurn == aigo.Black {
-			fmt.Println("请输入:")
-			text, ex := reader.ReadString('\n')
-			log.Println(text)
-			text = strings.ToUpper(text)
-			if ex != nil {
-				log.Fatalln(ex)
-			}
-			text = strings.Replace(text, "\n", "", -1)
-			p := aigo.PointFromCoords(text)
-			if p != nil {
+			for { // 输入不合法时说明原因，重新输入
+				fmt.Println("请输入:")
+				text, ex := reader.ReadString('\n')
+				log.Println(text)
+				text = strings.ToUpper(text)
+				if ex != nil {
+					log.Fatalln(ex)
+				}
+				text = strings.Replace(text, "\n", "", -1)
+				p := aigo.PointFromCoords(text)
+				if p == nil {
+					fmt.Println("坐标格式不对，例如 D4")
+					continue
+				}
 				move = aigo.NewPlay(*p)
+				if e := game.CheckMove(move); e != nil {
+					fmt.Println("不能下在这里:", e)
+					continue
+				}
+				break
 			}
-
 		} else {
 			move = bot.SelectMove(game)
 		}
 		fmt.Println(aigo.PrintMove(game.PlayerTurn, move))
 
-		game, err = game.ApplyMove(move)
+		game, result, err = game.ApplyMoveResult(move)
 		if err != nil {
 			log.Println(err)
 		}
@@ -30,26 +30,26 @@ func main() {
 		var move aigo.Move
 		var err error
 		if game.PlayerTurn == aigo.Black {
-
-			text := ""
-			for {
+			for { // 输入不合法时说明原因，重新输入
 				fmt.Print("请输入:")
-				var ex error
-				text, ex = reader.ReadString('\n')
+				text, ex := reader.ReadString('\n')
 				log.Println(text)
 				text = strings.ToUpper(text)
 				if ex != nil {
 					log.Fatalln(ex)
 				}
 				text = strings.Replace(text, "\n", "", -1)
-				if len(text) == 2 {
-					break
+				p := aigo.PointFromCoords(text)
+				if p == nil {
+					fmt.Println("坐标格式不对，例如 D4")
+					continue
 				}
-			}
-
-			p := aigo.PointFromCoords(text)
-			if p != nil {
 				move = aigo.NewPlay(*p)
+				if e := game.CheckMove(move); e != nil {
+					fmt.Println("不能下在这里:", e)
+					continue
+				}
+				break
 			}
 		} else {
 			move = bot.SelectMove(game)
@@ -0,0 +1,201 @@
+package aigo
+
+import (
+	"math/rand"
+	"time"
+)
+
+// 死子估计
+// EvaluateTerritory 假设死子都已经提走了，机器人之间双方跳过结束的对局并不满足这个假设。
+// 这里从终局局面出发随机模拟很多盘，统计每个交叉点最后归谁，
+// 大部分模拟里都被对方吃掉的棋链就判为死棋。
+// 有两个真眼的棋链、Benson 算法判为无条件活的棋链肯定是活棋，不会被判死。
+
+// 死子估计的结果
+type DeadStoneEstimate struct {
+	Ownership  map[Point]float64 // 每个交叉点的归属，1 表示肯定是黑棋的，-1 表示肯定是白棋的，0 表示说不清
+	DeadGroups []*StoneGroup     // 判为死棋的棋链
+}
+
+// 死子估计器
+type DeadStoneEstimator struct {
+	Playouts  int     // 模拟对局的次数
+	Threshold float64 // 棋链的平均归属偏向对方超过这个值，就判为死棋
+	rnd       *rand.Rand
+}
+
+// 构造死子估计器，playouts 是模拟对局的次数
+func NewDeadStoneEstimator(playouts int) *DeadStoneEstimator {
+	return NewDeadStoneEstimatorWithSeed(playouts, time.Now().UnixNano())
+}
+
+// 指定随机数种子构造死子估计器，同样的种子得到同样的结果
+func NewDeadStoneEstimatorWithSeed(playouts int, seed int64) *DeadStoneEstimator {
+	return &DeadStoneEstimator{
+		Playouts:  playouts,
+		Threshold: 0.5,
+		rnd:       rand.New(rand.NewSource(seed)),
+	}
+}
+
+// 估计当前局面的归属和死子
+func (e *DeadStoneEstimator) Estimate(gs *GameState) *DeadStoneEstimate {
+	b := gs.BoardPosition
+	total := make([]float64, len(b.grid))
+	for i := 0; i < e.Playouts; i++ {
+		turn := gs.PlayerTurn
+		if i%2 == 1 { // 一半的模拟让对方先下，避免先手的影响
+			turn = turn.Other()
+		}
+		final := e.playout(b, turn, gs.KoPoint)
+		for k, owner := range final.ownerMap() {
+			switch owner {
+			case Black:
+				total[k]++
+			case White:
+				total[k]--
+			}
+		}
+	}
+
+	est := &DeadStoneEstimate{Ownership: make(map[Point]float64, len(total))}
+	for k := range total {
+		if e.Playouts > 0 {
+			total[k] /= float64(e.Playouts)
+		}
+		est.Ownership[b.point(int32(k))] = total[k]
+	}
+
+	life := b.UnconditionalLife()
+	for _, sg := range b.GetAllStoneGroups() {
+		if b.countEyes(sg) >= 2 || life[sg.Color].IsAlive(sg.Stones[0]) {
+			continue
+		}
+		sum := 0.0
+		for _, p := range sg.Stones {
+			sum += total[b.index(p)]
+		}
+		mean := sum / float64(len(sg.Stones))
+		if sg.Color == White {
+			mean = -mean
+		}
+		if mean < -e.Threshold {
+			est.DeadGroups = append(est.DeadGroups, sg)
+		}
+	}
+	return est
+}
+
+// 从指定局面开始随机下到双方都跳过，返回终局的棋盘
+// 不填自己的眼，不自杀，不马上提劫，不自己送吃（落子后只剩一口气又没有提子），
+// 其他合法的点等概率选择
+func (e *DeadStoneEstimator) playout(start *Board, turn Player, ko *Point) *Board {
+	b := start.Copy()
+	max_moves := 3 * len(b.grid)
+	candidates := make([]int32, 0, len(b.grid))
+	passes := 0
+	for step := 0; step < max_moves && passes < 2; step++ {
+		candidates = candidates[:0]
+		for i, c := range b.grid {
+			if c == None {
+				candidates = append(candidates, int32(i))
+			}
+		}
+		moved := false
+		for len(candidates) > 0 {
+			k := e.rnd.Intn(len(candidates))
+			i := candidates[k]
+			candidates[k] = candidates[len(candidates)-1]
+			candidates = candidates[:len(candidates)-1]
+
+			p := b.point(i)
+			if (ko != nil && *ko == p) || b.IsPointAnEye(p, turn) || b.isSelfCapture(turn, p) {
+				continue
+			}
+			c, _ := b.Play(turn, p)
+			if len(c.Captured) == 0 && b.libs[b.head[i]] == 1 {
+				b.Undo(c)
+				continue
+			}
+			ko = b.koPoint(c)
+			moved = true
+			break
+		}
+		if moved {
+			passes = 0
+		} else {
+			passes++
+			ko = nil
+		}
+		turn = turn.Other()
+	}
+	return b
+}
+
+// 每个交叉点的归属：有棋子的归棋子的颜色，空点只被一方包围时归这一方，否则为 None
+func (b *Board) ownerMap() []Player {
+	owner := make([]Player, len(b.grid))
+	copy(owner, b.grid)
+	gen := b.nextMark()
+	region := []int32{}
+	for i, c := range b.grid {
+		if c != None || b.mark[i] == gen {
+			continue
+		}
+		// 找出整块空白区域和它的边界颜色
+		region = append(region[:0], int32(i))
+		b.mark[i] = gen
+		border := None
+		mixed := false
+		for k := 0; k < len(region); k++ {
+			for _, n := range b.adj[4*region[k] : 4*region[k]+4] {
+				if n < 0 {
+					continue
+				}
+				switch nc := b.grid[n]; {
+				case nc == None:
+					if b.mark[n] != gen {
+						b.mark[n] = gen
+						region = append(region, n)
+					}
+				case border == None:
+					border = nc
+				case border != nc:
+					mixed = true
+				}
+			}
+		}
+		if !mixed {
+			for _, k := range region {
+				owner[k] = border
+			}
+		}
+	}
+	return owner
+}
+
+// 棋链的眼数，只数棋链的气里面 IsPointAnEye 认为是眼的点
+func (b *Board) countEyes(sg *StoneGroup) int {
+	eyes := 0
+	for _, p := range sg.Liberties {
+		if b.IsPointAnEye(p, sg.Color) {
+			eyes++
+		}
+	}
+	return eyes
+}
+
+// 先估计死子，把死子提走算作对方的提子，再按规则计算结果
+func (gs *GameState) ComputeGameResultWithDeadStones(e *DeadStoneEstimator) (*GameResult, *DeadStoneEstimate) {
+	est := e.Estimate(gs)
+	board := gs.BoardPosition.Copy()
+	prisoners := gs.Prisoners
+	for _, sg := range est.DeadGroups {
+		i := board.index(sg.Stones[0])
+		if board.grid[i] == None {
+			continue
+		}
+		prisoners[sg.Color.Other()] += board.removeChain(board.head[i], nil)
+	}
+	return gs.scoreBoard(board, prisoners), est
+}
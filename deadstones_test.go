@@ -0,0 +1,56 @@
+package aigo
+
+import (
+	"testing"
+)
+
+// 黑棋占左边，白棋占右边，都已经做活，双方的空里各有一颗对方的死子
+func deadStonesGame(t *testing.T, rules Ruleset) *GameState {
+	game := gameFromDiagram(t, rules, Black,
+		"..XO...",
+		"XXXOOOO",
+		"XXXO...",
+		"..XO.X.",
+		".OXO...",
+		"XXXOOOO",
+		"X.XO.O.",
+	)
+	game, _ = game.ApplyMove(NewPass())
+	game, _ = game.ApplyMove(NewPass())
+	return game
+}
+
+func TestDeadStoneEstimate(t *testing.T) {
+	game := deadStonesGame(t, ChineseRules)
+	est := NewDeadStoneEstimatorWithSeed(200, 1).Estimate(game)
+	if len(est.DeadGroups) != 2 {
+		t.Fatalf("应该有两块死棋: %v", est.DeadGroups)
+	}
+	for _, sg := range est.DeadGroups {
+		p := sg.Stones[0]
+		if !(p == Point{Row: 3, Col: 2} && sg.Color == White) && !(p == Point{Row: 4, Col: 6} && sg.Color == Black) {
+			t.Errorf("判错了死棋: %v", sg)
+		}
+	}
+	if est.Ownership[Point{Row: 1, Col: 1}] < 0.5 || est.Ownership[Point{Row: 7, Col: 7}] > -0.5 {
+		t.Errorf("归属不对: %v %v", est.Ownership[Point{Row: 1, Col: 1}], est.Ownership[Point{Row: 7, Col: 7}])
+	}
+}
+
+func TestComputeGameResultWithDeadStones(t *testing.T) {
+	game := deadStonesGame(t, ChineseRules)
+	raw := game.ComputeGameResult()
+	if raw.B != 18 || raw.W != 20 { // 有死子的空都算成单官
+		t.Errorf("不去死子的结果 %+v", raw)
+	}
+	result, _ := game.ComputeGameResultWithDeadStones(NewDeadStoneEstimatorWithSeed(200, 1))
+	if result.B != 21 || result.W != 28 {
+		t.Errorf("数子法去掉死子的结果 %+v", result)
+	}
+
+	game = deadStonesGame(t, JapaneseRules)
+	result, _ = game.ComputeGameResultWithDeadStones(NewDeadStoneEstimatorWithSeed(200, 1))
+	if result.B != 8 || result.W != 15 { // 死子算作提子
+		t.Errorf("数目法去掉死子的结果 %+v", result)
+	}
+}
@@ -0,0 +1,212 @@
+package aigo
+
+// 眼的分析
+// IsPointAnEye 是书里数对角点的办法，不看棋链的死活，会把一些假眼当成真眼，
+// 也不认识一个点以上的眼位。这里：
+//   - 单个点的眼结合周围棋链的气分成真眼和假眼
+//   - 找出被一方包围的多个点的眼位
+//   - 数每条棋链可能做出的眼
+
+// 单个点的眼的状态
+type EyeStatus int
+
+const (
+	NoEye    EyeStatus = iota // 不是眼：不是空点，或者相邻的点不全是己方棋子
+	FalseEye                  // 假眼：看起来是眼，但是对方可以把周围的棋子断开或者提掉
+	RealEye                   // 真眼
+)
+
+func (s EyeStatus) String() string {
+	switch s {
+	case FalseEye:
+		return "FalseEye"
+	case RealEye:
+		return "RealEye"
+	}
+	return "NoEye"
+}
+
+// 眼位最多几个点，再大就当作地盘，不算眼位
+const MaxEyeSpaceSize = 7
+
+// 被一方包围的眼位
+type EyeSpace struct {
+	Color  Player        // 谁的眼位
+	Points []Point       // 眼位里的点，可以有对方的棋子
+	Chains []*StoneGroup // 包围眼位的棋链
+	Eyes   int           // 这一方先走时最多能做出几个眼，0、1 或者 2
+}
+
+// p 对 color 来说是真眼、假眼还是不是眼
+// 相邻的点都是同一条棋链时一定是真眼；相邻的棋链只剩这一口气时是假眼。
+// 其他情况看对角点有几个是坏的：对方的棋子（只有一口气、可以提掉的不算），
+// 己方只有一口气的棋子，以及不是己方的眼的空点。
+// 棋盘中间最多一个坏点，边上和角上不能有坏点。
+func (b *Board) EyeStatus(p Point, color Player) EyeStatus {
+	if !b.IsOnGrid(p) || b.Get(p) != None {
+		return NoEye
+	}
+	i := b.index(p)
+	head := int32(-1)
+	single := true
+	for _, n := range b.adj[4*i : 4*i+4] {
+		if n < 0 {
+			continue
+		}
+		if b.grid[n] != color {
+			return NoEye
+		}
+		if b.libs[b.head[n]] == 1 { // 对方下在这里就能提掉相邻的棋子
+			return FalseEye
+		}
+		if head < 0 {
+			head = b.head[n]
+		} else if b.head[n] != head {
+			single = false
+		}
+	}
+	if head < 0 { // 1 路棋盘，没有相邻的点
+		return NoEye
+	}
+	if single {
+		return RealEye
+	}
+
+	bad, off_board := 0, 0
+	for k := 1; k < 8; k += 2 { // ring 里奇数位置是对角点
+		d := b.ring[8*i+int32(k)]
+		switch {
+		case d < 0:
+			off_board++
+		case b.grid[d] == color:
+			if b.libs[b.head[d]] == 1 {
+				bad++
+			}
+		case b.grid[d] == None:
+			if !b.surroundedBy(d, color) {
+				bad++
+			}
+		default:
+			if b.libs[b.head[d]] > 1 {
+				bad++
+			}
+		}
+	}
+	if (off_board == 0 && bad <= 1) || (off_board > 0 && bad == 0) {
+		return RealEye
+	}
+	return FalseEye
+}
+
+// 下标 i 相邻的点是否全是 color 的棋子
+func (b *Board) surroundedBy(i int32, color Player) bool {
+	for _, n := range b.adj[4*i : 4*i+4] {
+		if n >= 0 && b.grid[n] != color {
+			return false
+		}
+	}
+	return true
+}
+
+// color 一方所有的眼位：不是 color 的点连成的、只被 color 包围、不超过 MaxEyeSpaceSize 个点的区域
+func (b *Board) EyeSpaces(color Player) []*EyeSpace {
+	// 先找出所有区域再生成眼位，生成眼位时用到的 stoneGroupAt 会重新标记
+	type region struct{ points, borders []int32 }
+	regions := []region{}
+	gen := b.nextMark()
+	for i, c := range b.grid {
+		if c == color || b.mark[i] == gen {
+			continue
+		}
+		points := []int32{int32(i)}
+		borders := []int32{}
+		b.mark[i] = gen
+		for k := 0; k < len(points); k++ {
+			for _, n := range b.adj[4*points[k] : 4*points[k]+4] {
+				if n < 0 {
+					continue
+				}
+				if b.grid[n] == color {
+					borders = appendUniqueInt32(borders, b.head[n])
+				} else if b.mark[n] != gen {
+					b.mark[n] = gen
+					points = append(points, n)
+				}
+			}
+		}
+		if len(points) <= MaxEyeSpaceSize && len(borders) > 0 {
+			regions = append(regions, region{points, borders})
+		}
+	}
+
+	spaces := []*EyeSpace{}
+	for _, r := range regions {
+		space := &EyeSpace{Color: color, Points: b.points(r.points)}
+		for _, h := range r.borders {
+			space.Chains = append(space.Chains, b.stoneGroupAt(h))
+		}
+		space.Eyes = b.eyeSpaceEyes(r.points, color)
+		spaces = append(spaces, space)
+	}
+	return spaces
+}
+
+// 眼位先走时最多能做出几个眼
+// 一个点的看是不是真眼；两个点只有一个眼；方四只有一个眼；
+// 其他三个点以上的眼位，下在要点上可以做出两个眼。不考虑对方在里面的棋子。
+func (b *Board) eyeSpaceEyes(points []int32, color Player) int {
+	switch len(points) {
+	case 1:
+		if b.EyeStatus(b.point(points[0]), color) == RealEye {
+			return 1
+		}
+		return 0
+	case 2:
+		return 1
+	case 4:
+		if b.isSquare(points) {
+			return 1
+		}
+	}
+	return 2
+}
+
+// 4 个点是不是排成 2x2 的方块
+func (b *Board) isSquare(points []int32) bool {
+	rows, cols := map[uint16]bool{}, map[uint16]bool{}
+	for _, e := range points {
+		p := b.point(e)
+		rows[p.Row] = true
+		cols[p.Col] = true
+	}
+	return len(rows) == 2 && len(cols) == 2
+}
+
+// p 处棋链可能做出的眼数，把它参与包围的所有眼位的眼数加起来
+// p 处没有棋子时返回 0
+func (b *Board) PotentialEyes(p Point) int {
+	color := b.Get(p)
+	if color == None {
+		return 0
+	}
+	h := b.head[b.index(p)]
+	eyes := 0
+	for _, space := range b.EyeSpaces(color) {
+		for _, sg := range space.Chains {
+			if b.head[b.index(sg.Stones[0])] == h {
+				eyes += space.Eyes
+				break
+			}
+		}
+	}
+	return eyes
+}
+
+// 随机机器人判断是不是自己的眼，不往自己的眼里下
+// analysis 为 true 时用 EyeStatus，只有真眼才不下；否则用书里的 IsPointAnEye
+func (b *Board) isOwnEye(p Point, color Player, analysis bool) bool {
+	if analysis {
+		return b.EyeStatus(p, color) == RealEye
+	}
+	return b.IsPointAnEye(p, color)
+}
@@ -0,0 +1,128 @@
+package aigo
+
+import (
+	"testing"
+)
+
+func TestEyeStatus(t *testing.T) {
+	cases := []struct {
+		name string
+		rows []string
+		p    Point
+		want EyeStatus
+		book bool // IsPointAnEye 的结果
+	}{
+		{"角上的真眼", []string{
+			".....",
+			".....",
+			".....",
+			"XX...",
+			".X...",
+		}, Point{Row: 1, Col: 1}, RealEye, true},
+		{"棋链只剩这一口气", []string{
+			".....",
+			".....",
+			"OOO..",
+			"XXO..",
+			".XO..",
+		}, Point{Row: 1, Col: 1}, FalseEye, true},
+		{"对角的白子可以提掉", []string{
+			".....",
+			".XXO.",
+			".X.X.",
+			".OXX.",
+			".X...",
+		}, Point{Row: 3, Col: 3}, RealEye, false},
+		{"假眼", []string{
+			".....",
+			".XXO.",
+			".X.X.",
+			"OOXX.",
+			".X...",
+		}, Point{Row: 3, Col: 3}, FalseEye, false},
+		{"不是眼", []string{
+			".....",
+			".XXO.",
+			".X.O.",
+			".XXX.",
+			".....",
+		}, Point{Row: 3, Col: 3}, NoEye, false},
+	}
+	for _, c := range cases {
+		game := gameFromDiagram(t, ChineseRules, Black, c.rows...)
+		b := game.BoardPosition
+		if got := b.EyeStatus(c.p, Black); got != c.want {
+			t.Errorf("%s: 期望 %v，实际 %v", c.name, c.want, got)
+		}
+		if got := b.IsPointAnEye(c.p, Black); got != c.book {
+			t.Errorf("%s: IsPointAnEye 期望 %v，实际 %v", c.name, c.book, got)
+		}
+		if b.isOwnEye(c.p, Black, true) != (c.want == RealEye) || b.isOwnEye(c.p, Black, false) != c.book {
+			t.Errorf("%s: 随机机器人的眼的判断不对", c.name)
+		}
+	}
+}
+
+func TestEyeSpaces(t *testing.T) {
+	game := gameFromDiagram(t, ChineseRules, Black,
+		".....",
+		".....",
+		"XXXXX",
+		"X.X.X",
+		"XXX.X",
+		"..X.X",
+	)
+	b := game.BoardPosition
+	spaces := b.EyeSpaces(Black)
+	if len(spaces) != 3 {
+		t.Fatalf("应该有 3 个眼位: %v", len(spaces))
+	}
+	sizes := map[int]int{}
+	for _, s := range spaces {
+		if len(s.Chains) != 1 || s.Color != Black {
+			t.Errorf("眼位只被一条黑棋包围: %+v", s)
+		}
+		sizes[len(s.Points)] = s.Eyes
+	}
+	if sizes[1] != 1 || sizes[2] != 1 || sizes[3] != 2 {
+		t.Errorf("眼位的眼数不对: %v", sizes)
+	}
+	if n := b.PotentialEyes(Point{Row: 4, Col: 1}); n != 4 {
+		t.Errorf("棋链可能的眼数: %d", n)
+	}
+	if n := b.PotentialEyes(Point{Row: 5, Col: 1}); n != 0 {
+		t.Errorf("空点的眼数: %d", n)
+	}
+
+	// 方四只有一个眼
+	game = gameFromDiagram(t, ChineseRules, Black,
+		"......",
+		"......",
+		"XXXX..",
+		"X..X..",
+		"X..X..",
+		"XXXX..",
+	)
+	spaces = game.BoardPosition.EyeSpaces(Black)
+	if len(spaces) != 1 || len(spaces[0].Points) != 4 || spaces[0].Eyes != 1 {
+		t.Errorf("方四: %+v", spaces)
+	}
+}
+
+// 用眼的分析的随机机器人能正常下完一盘
+func TestRandomBotEyeAnalysis(t *testing.T) {
+	bots := map[Player]IAgent{
+		Black: RandomBot{EyeAnalysis: true},
+		White: &FastRandomBot{EyeAnalysis: true},
+	}
+	game := NewGameOfSize(5, 5)
+	for step := 0; !game.IsOver(); step++ {
+		if step > 500 {
+			t.Fatal("对局没有结束")
+		}
+		var err error
+		if game, err = game.ApplyMove(bots[game.PlayerTurn].SelectMove(game)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -1,39 +1,185 @@
 package aigo
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 )
 
-// 围棋的胜负判断中国规则
-// 因为执黑先行，所以结束时黑棋要比白棋多7.5目才算赢。
+// 围棋的胜负判断，计分方法和贴目由 Ruleset 决定
+// 因为执黑先行，所以结束时黑棋要比白棋多出贴目才算赢，中国规则是7.5目。
+// 贴目是整数时可能和棋（持棋），这时没有赢家。
+// 对局也可能因为认输、超时、判负结束，这时只有赢家，没有得分。
+
+// 对局结果的类型
+type ResultKind int
+
+const (
+	ResultScore   ResultKind = iota // 计分，B+3.5
+	ResultResign                    // 认输，B+R
+	ResultTime                      // 超时，B+T
+	ResultForfeit                   // 判负，B+F
+	ResultWin                       // 只知道谁赢了，不知道原因和差距，B+
+	ResultDraw                      // 和棋，0
+	ResultVoid                      // 无胜负，Void
+	ResultUnknown                   // 结果未知，?
+)
 
 // 围棋游戏结果判定类
 type GameResult struct {
-	B    int     // 黑棋气数
-	W    int     // 白棋
-	KOMI float64 //
+	B    int     // 黑棋得分，数子法是子数，数目法是目数
+	W    int     // 白棋得分
+	KOMI float64 // 贴目
+
+	Kind   ResultKind // 结果的类型
+	Win    Player     // 赢家，和棋、无胜负、结果未知时为 None
+	Margin float64    // 计分时赢了多少，解析出来的计分结果只有这个，没有 B、W
 }
 
-// 赢家是谁？
-func (gr *GameResult) Winner() Player {
-	if float64(gr.B) > float64(gr.W)+gr.KOMI {
-		return Black
+// 按得分和贴目构造结果，正好相等时是和棋
+func NewScoreResult(b, w int, komi float64) *GameResult {
+	gr := &GameResult{B: b, W: w, KOMI: komi}
+	diff := float64(b) - (float64(w) + komi)
+	switch {
+	case diff > 0:
+		gr.Win = Black
+	case diff < 0:
+		gr.Win = White
+	default:
+		gr.Kind = ResultDraw
 	}
-	return White
+	gr.Margin = math.Abs(diff)
+	return gr
+}
+
+// 构造不计分的结果，比如认输、超时；和棋、无胜负时 winner 为 None
+func NewGameResult(kind ResultKind, winner Player) *GameResult {
+	return &GameResult{Kind: kind, Win: winner}
+}
+
+// 直接写 GameResult{B: .., W: .., KOMI: ..} 构造的结果没有 Win、Margin，按得分和贴目算出来
+func (gr *GameResult) resolve() *GameResult {
+	if gr.Kind == ResultScore && gr.Win == None {
+		return NewScoreResult(gr.B, gr.W, gr.KOMI)
+	}
+	return gr
+}
+
+// 赢家是谁？和棋等没有赢家时返回 None
+func (gr *GameResult) Winner() Player {
+	return gr.resolve().Win
 }
 
-// 赢多少子？
+// 是否和棋
+func (gr *GameResult) IsDraw() bool {
+	return gr.resolve().Kind == ResultDraw
+}
+
+// 赢多少子？不是计分的结果返回 0
 func (gr *GameResult) WinningMargin() float64 {
-	w := float64(gr.W) + gr.KOMI
-	return math.Abs(float64(gr.B) - w)
+	gr = gr.resolve()
+	if gr.Kind != ResultScore {
+		return 0
+	}
+	return gr.Margin
 }
 
 // 序列化字符串
 func (gr *GameResult) String() string {
-	w := float64(gr.W) + gr.KOMI
-	if float64(gr.B) > w {
-		return fmt.Sprintf("黑胜%.1f子", (float64(gr.B) - w))
+	gr = gr.resolve()
+	name := map[Player]string{Black: "黑", White: "白"}[gr.Win]
+	switch gr.Kind {
+	case ResultScore:
+		return fmt.Sprintf("%s胜%.1f子", name, gr.Margin)
+	case ResultResign:
+		return name + "中盘胜"
+	case ResultTime:
+		return name + "超时胜"
+	case ResultForfeit:
+		return name + "判胜"
+	case ResultWin:
+		return name + "胜"
+	case ResultDraw:
+		return "和棋"
+	case ResultVoid:
+		return "无胜负"
+	}
+	return "结果未知"
+}
+
+// SGF 的 RE 属性格式：B+3.5、W+R、W+T、B+F、B+、0、Void、?
+// GTP 的 final_score 也用这个格式
+func (gr *GameResult) SGF() string {
+	gr = gr.resolve()
+	prefix := ""
+	switch gr.Win {
+	case Black:
+		prefix = "B+"
+	case White:
+		prefix = "W+"
+	}
+	switch gr.Kind {
+	case ResultScore:
+		return prefix + strconv.FormatFloat(gr.Margin, 'f', -1, 64)
+	case ResultResign:
+		return prefix + "R"
+	case ResultTime:
+		return prefix + "T"
+	case ResultForfeit:
+		return prefix + "F"
+	case ResultWin:
+		return prefix
+	case ResultDraw:
+		return "0"
+	case ResultVoid:
+		return "Void"
+	}
+	return "?"
+}
+
+// 解析 SGF 的 RE 属性或者 GTP final_score 格式的结果
+// 认输、超时、判负也接受 Resign、Time、Forfeit 的全称，和棋接受 Draw，大小写都可以
+func ParseGameResult(s string) (*GameResult, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "0", "draw", "jigo":
+		return NewGameResult(ResultDraw, None), nil
+	case "void":
+		return NewGameResult(ResultVoid, None), nil
+	case "?", "":
+		return NewGameResult(ResultUnknown, None), nil
+	}
+	if len(s) < 2 || s[1] != '+' {
+		return nil, fmt.Errorf("invalid game result %q", s)
+	}
+	var winner Player
+	switch s[0] {
+	case 'B', 'b':
+		winner = Black
+	case 'W', 'w':
+		winner = White
+	default:
+		return nil, fmt.Errorf("invalid game result %q", s)
+	}
+	switch reason := s[2:]; strings.ToLower(reason) {
+	case "":
+		return NewGameResult(ResultWin, winner), nil
+	case "r", "resign":
+		return NewGameResult(ResultResign, winner), nil
+	case "t", "time":
+		return NewGameResult(ResultTime, winner), nil
+	case "f", "forfeit":
+		return NewGameResult(ResultForfeit, winner), nil
+	default:
+		margin, err := strconv.ParseFloat(reason, 64)
+		if err != nil || margin < 0 || math.IsNaN(margin) || math.IsInf(margin, 0) {
+			return nil, fmt.Errorf("invalid game result %q", s)
+		}
+		if margin == 0 {
+			return nil, errors.New("a winning margin of zero is a draw, use \"0\"")
+		}
+		return &GameResult{Kind: ResultScore, Win: winner, Margin: margin}, nil
 	}
-	return fmt.Sprintf("白胜%.1f子", (w - float64(gr.B)))
 }
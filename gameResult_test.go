@@ -0,0 +1,121 @@
+package aigo
+
+import (
+	"testing"
+)
+
+func TestGameResultNotation(t *testing.T) {
+	cases := []struct {
+		result *GameResult
+		sgf    string
+		text   string
+		winner Player
+	}{
+		{NewScoreResult(40, 30, 6.5), "B+3.5", "黑胜3.5子", Black},
+		{NewScoreResult(30, 30, 7.5), "W+7.5", "白胜7.5子", White},
+		{NewScoreResult(37, 30, 7), "0", "和棋", None}, // 整数贴目可能和棋
+		{NewGameResult(ResultResign, White), "W+R", "白中盘胜", White},
+		{NewGameResult(ResultTime, Black), "B+T", "黑超时胜", Black},
+		{NewGameResult(ResultForfeit, White), "W+F", "白判胜", White},
+		{NewGameResult(ResultWin, Black), "B+", "黑胜", Black},
+		{NewGameResult(ResultVoid, None), "Void", "无胜负", None},
+		{NewGameResult(ResultUnknown, None), "?", "结果未知", None},
+	}
+	for _, c := range cases {
+		if got := c.result.SGF(); got != c.sgf {
+			t.Errorf("SGF 期望 %s，实际 %s", c.sgf, got)
+		}
+		if got := c.result.String(); got != c.text {
+			t.Errorf("String 期望 %s，实际 %s", c.text, got)
+		}
+		if got := c.result.Winner(); got != c.winner {
+			t.Errorf("%s: 赢家期望 %v，实际 %v", c.sgf, c.winner, got)
+		}
+		parsed, err := ParseGameResult(c.sgf)
+		if err != nil {
+			t.Fatalf("%s: %v", c.sgf, err)
+		}
+		if parsed.Kind != c.result.Kind || parsed.Winner() != c.winner || parsed.WinningMargin() != c.result.WinningMargin() {
+			t.Errorf("%s 解析结果不对: %+v", c.sgf, parsed)
+		}
+	}
+	if !NewScoreResult(37, 30, 7).IsDraw() || NewScoreResult(37, 30, 6.5).IsDraw() {
+		t.Error("和棋判断不对")
+	}
+}
+
+// 直接写字段构造的计分结果，按得分和贴目算出赢家
+func TestGameResultLiteral(t *testing.T) {
+	gr := &GameResult{B: 40, W: 30, KOMI: 6.5}
+	if gr.Winner() != Black || gr.WinningMargin() != 3.5 || gr.String() != "黑胜3.5子" || gr.SGF() != "B+3.5" {
+		t.Errorf("黑胜: %v %v %v", gr.Winner(), gr.WinningMargin(), gr)
+	}
+	gr = &GameResult{B: 30, W: 30, KOMI: 7.5}
+	if gr.Winner() != White || gr.SGF() != "W+7.5" {
+		t.Errorf("白胜: %v %v", gr.Winner(), gr)
+	}
+	gr = &GameResult{B: 37, W: 30, KOMI: 7}
+	if gr.Winner() != None || !gr.IsDraw() || gr.String() != "和棋" {
+		t.Errorf("和棋: %v %v", gr.Winner(), gr)
+	}
+}
+
+func TestParseGameResult(t *testing.T) {
+	for s, want := range map[string]string{
+		"W+Resign":  "W+R",
+		"b+time":    "B+T",
+		"B+Forfeit": "B+F",
+		"Draw":      "0",
+		" B+12 ":    "B+12",
+		"W+0.5":     "W+0.5",
+		"":          "?",
+	} {
+		r, err := ParseGameResult(s)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+		if r.SGF() != want {
+			t.Errorf("%q 期望 %s，实际 %s", s, want, r.SGF())
+		}
+	}
+	for _, s := range []string{"X+R", "B3.5", "B+abc", "W+-1", "B+0", "黑胜"} {
+		if _, err := ParseGameResult(s); err == nil {
+			t.Errorf("%q 应该解析失败", s)
+		}
+	}
+}
+
+func TestGameStateWinner(t *testing.T) {
+	game := NewGameOfSize(5, 5)
+	if game.Winner() != nil {
+		t.Error("对局没有结束")
+	}
+	game, _ = game.ApplyMove(NewPlay(Point{Row: 3, Col: 3}))
+	game, _ = game.ApplyMove(NewResign())
+	result := game.Winner()
+	if result.Kind != ResultResign || result.Winner() != Black || result.SGF() != "B+R" {
+		t.Errorf("白棋认输: %+v", result)
+	}
+
+	// 整数贴目数出来一样多时是和棋
+	game = NewGameWithRules(1, 2, Ruleset{Scoring: AreaScoring, Komi: 0})
+	game, _ = game.ApplyMove(NewPass())
+	game, _ = game.ApplyMove(NewPass())
+	if result := game.Winner(); !result.IsDraw() || result.Winner() != None {
+		t.Errorf("空棋盘不贴目是和棋: %+v", result)
+	}
+	// 搜索里和棋不算输赢
+	if score := game.AlphaBetaResult(1, MIN_SCORE, MIN_SCORE, CaptureDiff); score != 0 {
+		t.Errorf("alpha-beta 的和棋: %d", score)
+	}
+	if score := game.BestResult(1, CaptureDiff); score != 0 {
+		t.Errorf("剪枝搜索的和棋: %d", score)
+	}
+	node := NewMCTSNode(game, nil, nil)
+	node.record_win(None)
+	node.record_win(Black)
+	if node.winning_frac(Black) != 0.75 || node.winning_frac(White) != 0.25 {
+		t.Errorf("MCTS 的和棋算半次: %v", node.win_count)
+	}
+}
@@ -3,6 +3,7 @@ package aigo
 import (
 	"fmt"
 	"log"
+	"math/rand"
 	"testing"
 )
 
@@ -47,7 +48,7 @@ func TestLiberties(t *testing.T) {
 		log.Fatalln(err)
 	}
 
-	if len(game.BoardPosition.stoneMap[Point{Row: 1, Col: 1}].Liberties) != 2 {
+	if game.BoardPosition.GetStoneGroup(Point{Row: 1, Col: 1}).NumLiberties() != 2 {
 		log.Println(game)
 		fmt.Println(game.BoardPosition.PrintBoard())
 		t.Errorf("气数不正确001!")
@@ -57,7 +58,7 @@ func TestLiberties(t *testing.T) {
 	if err != nil {
 		log.Fatalln(err)
 	}
-	if len(game.BoardPosition.stoneMap[Point{Row: 3, Col: 1}].Liberties) != 3 {
+	if game.BoardPosition.GetStoneGroup(Point{Row: 3, Col: 1}).NumLiberties() != 3 {
 		log.Println(game)
 		fmt.Println(game.BoardPosition.PrintBoard())
 		t.Errorf("气数不正确002!")
@@ -140,3 +141,73 @@ func TestIsPointAnEye(t *testing.T) {
 	}
 
 }
+
+// 原地落子后逐步撤销，每一步都要跟 ApplyMove 的结果一致，撤销后还原到原来的状态
+func TestPlayUndo(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	for round := 0; round < 10; round++ {
+		game := NewGameOfSize(9, 9)
+		states := []*GameState{game}
+		work := game.Copy()
+		undos := []*UndoRecord{}
+		for i := 0; i < 150 && !work.IsOver(); i++ {
+			moves := work.LegalMoves()
+			move := moves[rnd.Intn(len(moves)-1)] // 不认输
+			next, err := states[len(states)-1].ApplyMove(move)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u, err := work.Play(move)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !work.BoardPosition.Equal(next.BoardPosition) || work.BoardPosition.GetZobristHash() != next.BoardPosition.GetZobristHash() {
+				t.Fatalf("第%d步 %v 后棋盘不一致", i, move)
+			}
+			if (work.KoPoint == nil) != (next.KoPoint == nil) || (work.KoPoint != nil && *work.KoPoint != *next.KoPoint) {
+				t.Fatalf("第%d步 %v 后劫的状态不一致", i, move)
+			}
+			states = append(states, next)
+			undos = append(undos, u)
+		}
+		for k := len(undos) - 1; k >= 0; k-- {
+			work.Undo(undos[k])
+			want := states[k]
+			if !work.BoardPosition.Equal(want.BoardPosition) || work.BoardPosition.GetZobristHash() != want.BoardPosition.GetZobristHash() {
+				t.Fatalf("撤销到第%d步后棋盘不一致", k)
+			}
+			if work.PlayerTurn != want.PlayerTurn || len(work.PreviousZobristHashStateArr) != len(want.PreviousZobristHashStateArr) {
+				t.Fatalf("撤销到第%d步后状态不一致", k)
+			}
+			for _, sg := range want.BoardPosition.GetAllStoneGroups() {
+				if !sg.Equal(work.BoardPosition.GetStoneGroup(sg.Stones[0])) {
+					t.Fatalf("撤销到第%d步后棋链不一致: %v", k, sg)
+				}
+			}
+		}
+	}
+}
+
+// 提一子形成劫，对方不能马上提回
+func TestKoPoint(t *testing.T) {
+	game := NewGameOfSize(5, 5)
+	var err error
+	for _, p := range []Point{{2, 1}, {2, 2}, {1, 2}, {1, 3}, {5, 5}} {
+		game, err = game.ApplyMove(NewPlay(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 白在 1,1 提掉黑棋 1,2
+	game, err = game.ApplyMove(NewPlay(Point{1, 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.KoPoint == nil || *game.KoPoint != (Point{1, 2}) {
+		fmt.Println(game.BoardPosition.PrintBoard())
+		t.Fatalf("劫的位置不对: %v", game.KoPoint)
+	}
+	if game.IsValidMove(NewPlay(Point{1, 2})) {
+		t.Errorf("不能马上提劫")
+	}
+}
@@ -2,7 +2,6 @@ package aigo
 
 import (
 	"fmt"
-	"log"
 )
 
 // A game state, defined by board position, next turn, previous game state, and last move made.
@@ -11,7 +10,13 @@ type GameState struct {
 	PlayerTurn                  Player     // 当前是那个玩家的回合
 	PreviousState               *GameState // 上一回合的游戏状态  在 Zobrist 提速后，这个仍然保留，作为上一步信息的记录
 	PreviousZobristHashStateArr []int64    // 提速用的，之前回合的Zobrist哈希数组
+	PreviousSituationHashArr    []int64    // 之前回合的棋盘哈希加上轮到谁下，情境同形用
 	LastMove                    *Move      // 上一步动作
+	KoPoint                     *Point     // 上一步形成了劫，对方不能马上提回的位置；没有劫时为 nil
+	Rules                       Ruleset    // 对局使用的规则
+	Prisoners                   [3]int     // 以 Player 为下标，各方提掉对方的棋子数，AGA 规则下包括对方跳过交出的子
+	Handicap                    int        // 让子数，不是让子棋时为 0
+	Setup                       *Setup     // 这个状态是摆棋得到的，记录摆上的棋子；正常落子得到的状态为 nil
 }
 
 // 围棋默认19*19棋盘
@@ -20,13 +25,21 @@ func NewGame() *GameState {
 }
 
 // Constructor function builds a new GameState with Board of passed width and height.
-func NewGameOfSize(h uint16, w uint16) *GameState {
+// 默认使用中国规则
+func NewGameOfSize(w uint16, h uint16) *GameState {
+	return NewGameWithRules(w, h, ChineseRules)
+}
+
+// 使用指定规则开始一盘新棋
+func NewGameWithRules(w uint16, h uint16, rules Ruleset) *GameState {
 	gs := &GameState{}
-	gs.BoardPosition = NewBoard(h, w)
+	gs.BoardPosition = NewBoard(w, h)
 	gs.PlayerTurn = Black // 默认是黑棋先行
 	gs.PreviousState = nil
 	gs.LastMove = nil
 	gs.PreviousZobristHashStateArr = []int64{gs.BoardPosition.GetZobristHash()}
+	gs.PreviousSituationHashArr = []int64{situationHash(gs.BoardPosition.GetZobristHash(), gs.PlayerTurn)}
+	gs.Rules = rules
 	return gs
 }
 
@@ -36,12 +49,49 @@ func NewGameState(board *Board, next_player Player, previous *GameState, last_mo
 	gs.PlayerTurn = next_player
 	gs.PreviousState = previous
 	gs.LastMove = last_move
-	arr, _ := updateInt64Arr(previous.PreviousZobristHashStateArr, board.GetZobristHash())
+	// 限制容量，保证追加时复制一份，不会跟其他分支的 GameState 共用底层数组
+	n := len(previous.PreviousZobristHashStateArr)
+	arr, _ := updateInt64Arr(previous.PreviousZobristHashStateArr[:n:n], board.GetZobristHash())
 	gs.PreviousZobristHashStateArr = arr
+	n = len(previous.PreviousSituationHashArr)
+	arr, _ = updateInt64Arr(previous.PreviousSituationHashArr[:n:n], situationHash(board.GetZobristHash(), next_player))
+	gs.PreviousSituationHashArr = arr
+	gs.Rules = previous.Rules
+	gs.Prisoners = previous.Prisoners
+	gs.Handicap = previous.Handicap
 
 	return gs
 }
 
+// 返回一个棋盘独立的副本，之前的历史记录共享
+// 在副本上 Play / Undo 不会影响原来的对象
+func (gs *GameState) Copy() *GameState {
+	ngs := *gs
+	ngs.BoardPosition = gs.BoardPosition.Copy()
+	n := len(gs.PreviousZobristHashStateArr)
+	ngs.PreviousZobristHashStateArr = gs.PreviousZobristHashStateArr[:n:n]
+	n = len(gs.PreviousSituationHashArr)
+	ngs.PreviousSituationHashArr = gs.PreviousSituationHashArr[:n:n]
+	return &ngs
+}
+
+// 整个局面的 Zobrist 哈希：棋盘、轮到谁下、劫的禁着点
+func (gs *GameState) ZobristHash() int64 {
+	return gs.ZobristHash128().Lo
+}
+
+// 整个局面的 128 位 Zobrist 哈希
+func (gs *GameState) ZobristHash128() Hash128 {
+	h := gs.BoardPosition.GetZobristHash128()
+	if gs.PlayerTurn == White {
+		h = h.Xor(Zobrist.WhiteToMove)
+	}
+	if gs.KoPoint != nil {
+		h = h.Xor(Zobrist.KoPoint(*gs.KoPoint))
+	}
+	return h
+}
+
 // Method implements Stringer interface for GameState struct.
 func (gs *GameState) String() string {
 	s := fmt.Sprintln("Next turn: ", gs.PlayerTurn, "\nLast move: ", gs.LastMove)
@@ -50,32 +100,118 @@ func (gs *GameState) String() string {
 
 // Method returns a new GameState created from applying the given move.
 // 执行落子动作后，返回新的 GameState 对象指针
-// 不做规则判断
+// 先用 CheckMove 做规则判断，不合法时返回原来的对象和 CheckMove 的错误
 // 下棋的顺序是固定的， 不用传递谁下的这个参数
 func (gs *GameState) ApplyMove(m Move) (*GameState, error) {
+	ngs, _, err := gs.applyMove(m)
+	return ngs, err
+	// return &GameState{next_board, gs.PlayerTurn.Other(), gs, &m}, nil
+}
+
+// ApplyMove 的实现，同时返回棋盘的变化，跳过和认输时为 nil
+func (gs *GameState) applyMove(m Move) (*GameState, *BoardChange, error) {
+	if err := gs.CheckMove(m); err != nil {
+		return gs, nil, err
+	}
 	var next_board *Board
+	var change *BoardChange
 	if m.IsPlay {
 		next_board = gs.BoardPosition.Copy()
-		err := next_board.PlaceStone(gs.PlayerTurn, m.Pnt)
+		var err error
+		change, err = next_board.Play(gs.PlayerTurn, m.Pnt)
 		if err != nil {
-			return gs, err
+			return gs, nil, err
 		}
 	} else {
 		next_board = gs.BoardPosition // 跳过或认输场景，棋盘不变
 	}
-	return NewGameState(next_board, gs.PlayerTurn.Other(), gs, &m), nil
-	// return &GameState{next_board, gs.PlayerTurn.Other(), gs, &m}, nil
+	ngs := NewGameState(next_board, gs.PlayerTurn.Other(), gs, &m)
+	ngs.afterMove(gs.PlayerTurn, m, change)
+	return ngs, change, nil
+}
+
+// 原地执行落子动作的撤销记录
+type UndoRecord struct {
+	state  GameState    // 落子之前 GameState 的浅拷贝，包括劫的状态和哈希历史
+	change *BoardChange // 棋盘的变化，包括提走的棋子和 Zobrist 哈希的变化；跳过和认输时为 nil
+}
+
+// 在当前 GameState 上原地执行落子动作，不复制棋盘
+// 返回的记录交给 Undo 可以精确还原，多步落子要按相反顺序撤销
+// 原地落子后，PreviousState 只用来记录上一步的动作，它的棋盘与当前对象共享
+// 不做 ApplyMove 里 CheckMove 的规则判断（对局结束、自杀、劫争），调用方要保证动作合法
+func (gs *GameState) Play(m Move) (*UndoRecord, error) {
+	u := &UndoRecord{state: *gs}
+	if m.IsPlay {
+		c, err := gs.BoardPosition.Play(gs.PlayerTurn, m.Pnt)
+		if err != nil {
+			return nil, err
+		}
+		u.change = c
+	}
+	gs.PlayerTurn = gs.PlayerTurn.Other()
+	gs.PreviousState = &u.state
+	gs.LastMove = &m
+	gs.afterMove(u.state.PlayerTurn, m, u.change)
+	if _, ex := containsInt64(gs.PreviousZobristHashStateArr, gs.BoardPosition.hash); !ex {
+		gs.PreviousZobristHashStateArr = append(gs.PreviousZobristHashStateArr, gs.BoardPosition.hash)
+	}
+	sh := situationHash(gs.BoardPosition.hash, gs.PlayerTurn)
+	if _, ex := containsInt64(gs.PreviousSituationHashArr, sh); !ex {
+		gs.PreviousSituationHashArr = append(gs.PreviousSituationHashArr, sh)
+	}
+	return u, nil
+}
+
+// 落子后更新提子数和劫的状态，mover 是刚下完这步棋的一方
+func (gs *GameState) afterMove(mover Player, m Move, c *BoardChange) {
+	if c != nil {
+		gs.Prisoners[mover] += len(c.Captured)
+		gs.Prisoners[mover.Other()] += len(c.SelfCaptured)
+	} else if m.IsPass && gs.Rules.PassStones {
+		gs.Prisoners[mover.Other()]++
+	}
+	gs.KoPoint = gs.BoardPosition.koPoint(c)
+}
+
+// 撤销 Play 执行的落子动作
+func (gs *GameState) Undo(u *UndoRecord) {
+	if u.change != nil {
+		gs.BoardPosition.Undo(u.change)
+	}
+	*gs = u.state
+}
+
+// 棋盘哈希加上轮到谁下，作为情境同形的键
+func situationHash(board_hash int64, next Player) int64 {
+	if next == White {
+		return board_hash ^ Zobrist.WhiteToMove.Lo
+	}
+	return board_hash
 }
 
 // 判断当前游戏状态是否违反了劫争规则
+// 按规则里的劫争规则判断：
+// 普通劫只看上一步留下的劫；全局同形看之前所有的棋盘；
+// 情境同形看之前所有的棋盘，并且要求落子后轮到同一方下
 func (gs *GameState) DoesMoveViolateKo(p Player, move Move) bool {
 	if !move.IsPlay {
 		return false
 	}
-	next_board := gs.BoardPosition.Copy()
-	next_board.PlaceStone(p, move.Pnt)
-
-	_, ex := containsInt64(gs.PreviousZobristHashStateArr, next_board.hash)
+	if gs.Rules.Ko == SimpleKo {
+		return gs.KoPoint != nil && *gs.KoPoint == move.Pnt
+	}
+	c, err := gs.BoardPosition.Play(p, move.Pnt)
+	if err != nil {
+		return false
+	}
+	var ex bool
+	if gs.Rules.Ko == SituationalSuperko {
+		_, ex = containsInt64(gs.PreviousSituationHashArr, situationHash(gs.BoardPosition.hash, p.Other()))
+	} else {
+		_, ex = containsInt64(gs.PreviousZobristHashStateArr, gs.BoardPosition.hash)
+	}
+	gs.BoardPosition.Undo(c)
 	return ex
 	/*
 		// 原先没有用 Zobrist的代码
@@ -98,42 +234,29 @@ func (gs *GameState) IsMoveSelfCapture(p Player, move Move) bool {
 	if !move.IsPlay { // 跳过和结束不判断
 		return false
 	}
-	nest_board := gs.BoardPosition.Copy()
-	nest_board.PlaceStone(p, move.Pnt)
-	newSG := nest_board.GetStoneGroup(move.Pnt)
-	if newSG == nil { // 棋子已经被提走了，肯定是自杀
-		return true
-	}
-	return newSG.NumLiberties() == 0 // 没被提走，气数为0， 这个分支正常走不到，只是做最后一步检查。
-}
-
-// 在给定游戏目前状态下，判断这个动作是否合法？
-func (gs *GameState) IsValidMove(move Move) bool {
-	if gs.IsOver() {
-		return false
-	}
-
-	if move.IsPass || move.IsResign { // 认输或者跳过
-		log.Println("认输或跳过")
-		return true
-	}
-
-	if gs.BoardPosition.Get(move.Pnt) != None { // 这个位置已经有棋子了
-		// log.Println("已经有棋子")
+	if !gs.BoardPosition.IsOnGrid(move.Pnt) || gs.BoardPosition.Get(move.Pnt) != None {
 		return false
 	}
+	return gs.BoardPosition.isSelfCapture(p, move.Pnt)
+}
 
-	if gs.IsMoveSelfCapture(gs.PlayerTurn, move) { // 出现了自吃， 填自己气的情况
-		// log.Println("出现了自吃")
+// 规则是否允许这步自杀：只有规则允许、并且会提走不止一颗己方棋子时才可以
+func (gs *GameState) isSuicideAllowed(p Player, move Move) bool {
+	if !gs.Rules.SuicideAllowed {
 		return false
 	}
-
-	if gs.DoesMoveViolateKo(gs.PlayerTurn, move) { // 出现了劫争
-		// log.Println("出现了劫争")
-		return false
+	for _, n := range move.Pnt.Neighbors() { // 有相邻的己方棋子，自杀的就不止一颗
+		if gs.BoardPosition.Get(n) == p {
+			return true
+		}
 	}
+	return false
+}
 
-	return true
+// 在给定游戏目前状态下，判断这个动作是否合法？
+// 想知道不合法的原因时用 CheckMove
+func (gs *GameState) IsValidMove(move Move) bool {
+	return gs.CheckMove(move) == nil
 }
 
 // Method determines if a GameState represents a finished game.
@@ -177,35 +300,63 @@ func updateInt64Arr(s []int64, e int64) (arr []int64, ex bool) {
 
 }
 
-// 游戏的赢家
-func (gs *GameState) Winner() Player {
+// 对局结果：认输的、按规则计分的，对局还没有结束时返回 nil
+func (gs *GameState) Winner() *GameResult {
 	if !gs.IsOver() {
-		return None
+		return nil
 	}
 
 	if gs.LastMove.IsResign {
-		return gs.PlayerTurn
+		return NewGameResult(ResultResign, gs.PlayerTurn)
 	}
 
-	game_result := gs.ComputeGameResult()
-	return game_result.Winner()
+	return gs.ComputeGameResult()
 }
 
+// 按规则的计分方法和贴目计算结果，终局计分用
+// 数子法：棋子 + 地盘；数目法：地盘 + 提子，日本规则下双活里的眼不算地盘
 func (gs *GameState) ComputeGameResult() *GameResult {
-	territory := gs.BoardPosition.EvaluateTerritory()
-	return &GameResult{
-		B:    territory.NumBlackStones + territory.NumBlackTerritory,
-		W:    territory.NumWhiteStones + territory.NumWhiteTerritory,
-		KOMI: 7.5,
+	return gs.scoreBoard(gs.BoardPosition, gs.Prisoners)
+}
+
+// 按规则给指定的棋盘和提子数计分，双活的眼不算地盘时才找双活
+func (gs *GameState) scoreBoard(board *Board, prisoners [3]int) *GameResult {
+	if gs.Rules.NoSekiEyes {
+		return gs.scoreTerritory(board.EvaluateTerritoryWithSeki(), prisoners)
+	}
+	return gs.scoreTerritory(board.EvaluateTerritory(), prisoners)
+}
+
+// 随机模拟结束时的赢家：认输的一方输，其他的按当前局面计分，不找双活
+func (gs *GameState) playoutWinner() Player {
+	if gs.LastMove != nil && gs.LastMove.IsResign {
+		return gs.PlayerTurn
+	}
+	return gs.scoreTerritory(gs.BoardPosition.EvaluateTerritory(), gs.Prisoners).Winner()
+}
+
+// 按规则给地盘和提子数计分
+func (gs *GameState) scoreTerritory(territory *Territory, prisoners [3]int) *GameResult {
+	if gs.Rules.Scoring == TerritoryScoring {
+		return NewScoreResult(
+			territory.NumBlackTerritory-territory.NumBlackSekiEyes+prisoners[Black],
+			territory.NumWhiteTerritory-territory.NumWhiteSekiEyes+prisoners[White],
+			gs.Rules.Komi,
+		)
 	}
+	return NewScoreResult(
+		territory.NumBlackStones+territory.NumBlackTerritory,
+		territory.NumWhiteStones+territory.NumWhiteTerritory,
+		gs.Rules.Komi,
+	)
 }
 
 // 获得所有合法的可下棋点
 func (gs *GameState) LegalMoves() []Move {
 	moves := []Move{}
 	var r, c uint16
-	for r = 1; r <= gs.BoardPosition.Width; r++ {
-		for c = 1; c <= gs.BoardPosition.Height; c++ {
+	for r = 1; r <= gs.BoardPosition.Height; r++ {
+		for c = 1; c <= gs.BoardPosition.Width; c++ {
 
 			move := NewPlay(Point{Row: r, Col: c})
 			if gs.IsValidMove(move) {
@@ -228,8 +379,8 @@ func CaptureDiff(gs *GameState) int {
 	black_stones := 0
 	white_stones := 0
 	var r, c uint16
-	for r = 1; r <= gs.BoardPosition.Width; r++ {
-		for c = 1; c <= gs.BoardPosition.Height; c++ {
+	for r = 1; r <= gs.BoardPosition.Height; r++ {
+		for c = 1; c <= gs.BoardPosition.Width; c++ {
 			p := gs.BoardPosition.Get(Point{Row: r, Col: c})
 			if p == White {
 				white_stones++
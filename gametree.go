@@ -0,0 +1,176 @@
+package aigo
+
+import (
+	"errors"
+)
+
+// 棋谱树
+// GameState 只能通过 PreviousState 往回找，一条线走到底，不能表示复盘时的变化。
+// 棋谱树的每个节点带一个 GameState，可以有多个子节点（变化，第一个是主线），
+// 还可以有注释、标记和摆上的棋子；GameRecord 里有一个指向当前节点的游标。
+// 节点有三种：落子节点（Move 不为 nil）、摆棋节点（Setup 不为 nil）和只有注释的节点，
+// 根节点可以是摆棋节点，例如让子棋。sgf 包负责和 SGF 的变化格式互相转换。
+
+// 标记的类型，对应 SGF 的 TR、SQ、CR、MA、SL、LB
+type MarkupKind int
+
+const (
+	MarkTriangle MarkupKind = iota // 三角
+	MarkSquare                     // 方块
+	MarkCircle                     // 圆圈
+	MarkCross                      // 叉
+	MarkSelected                   // 选中
+	MarkLabel                      // 文字标签
+)
+
+// 棋盘上的一个标记
+type Markup struct {
+	Kind  MarkupKind
+	Point Point
+	Label string // 文字标签的内容，其他标记为空
+}
+
+// 棋谱树的节点
+type GameNode struct {
+	State    *GameState          // 这个节点的局面
+	Player   Player              // 落子的一方，不是落子节点时为 None
+	Move     *Move               // 这个节点的落子，不是落子节点时为 nil
+	Setup    *Setup              // 这个节点摆上的棋子，不是摆棋节点时为 nil
+	Comment  string              // 注释
+	Markup   []Markup            // 标记
+	Extra    map[string][]string // 其他属性，比如 SGF 里的对局者、日期，原样保留
+	SameNode bool                // 和父节点在 SGF 里是同一个节点（比如同时有摆棋和落子），写出时合并回去
+	Parent   *GameNode           // 父节点，根节点为 nil
+	Children []*GameNode         // 变化，第一个是主线
+}
+
+// 棋谱：棋谱树和当前节点
+type GameRecord struct {
+	Root    *GameNode // 根节点
+	Current *GameNode // 当前节点
+}
+
+// 以 gs 为根节点的局面新建棋谱，gs 是摆棋得到的局面时（比如让子棋）根节点就是摆棋节点
+func NewGameRecord(gs *GameState) *GameRecord {
+	root := &GameNode{State: gs, Setup: gs.Setup}
+	return &GameRecord{Root: root, Current: root}
+}
+
+// 当前局面
+func (r *GameRecord) State() *GameState {
+	return r.Current.State
+}
+
+// 在当前节点上由轮到的一方落子，游标移到新节点
+// 已经有同样落子的变化时直接走到那个变化，不重复添加
+func (r *GameRecord) Play(m Move) (*GameNode, error) {
+	color := r.Current.State.PlayerTurn
+	for _, c := range r.Current.Children {
+		if c.Move != nil && c.Player == color && *c.Move == m {
+			r.Current = c
+			return c, nil
+		}
+	}
+	return r.AddMove(color, m)
+}
+
+// 在当前节点上添加 color 一方的落子作为新的变化，游标移到新节点
+// 不是轮到 color 时先换手，复盘时可以让一方连下两步
+func (r *GameRecord) AddMove(color Player, m Move) (*GameNode, error) {
+	if color != Black && color != White {
+		return nil, errors.New("落子的一方必须是黑棋或者白棋")
+	}
+	gs := r.Current.State
+	if gs.PlayerTurn != color {
+		gs, _ = gs.ApplySetup(Setup{}, color)
+	}
+	ngs, err := gs.ApplyMove(m)
+	if err != nil {
+		return nil, err
+	}
+	return r.addChild(&GameNode{State: ngs, Player: color, Move: ngs.LastMove}), nil
+}
+
+// 在当前节点上摆棋作为新的变化，next 是摆完之后轮到谁，游标移到新节点
+func (r *GameRecord) AddSetup(s Setup, next Player) (*GameNode, error) {
+	ngs, err := r.Current.State.ApplySetup(s, next)
+	if err != nil {
+		return nil, err
+	}
+	return r.addChild(&GameNode{State: ngs, Setup: ngs.Setup}), nil
+}
+
+// 在当前节点后面添加一个局面不变的节点，用来放注释和标记，游标移到新节点
+func (r *GameRecord) AddNode() *GameNode {
+	return r.addChild(&GameNode{State: r.Current.State})
+}
+
+func (r *GameRecord) addChild(n *GameNode) *GameNode {
+	n.Parent = r.Current
+	r.Current.Children = append(r.Current.Children, n)
+	r.Current = n
+	return n
+}
+
+// 游标移到主线的下一个节点，已经是最后一个节点时返回 false
+func (r *GameRecord) Next() bool {
+	return r.Variation(0)
+}
+
+// 游标移到第 k 个变化，没有这个变化时返回 false
+func (r *GameRecord) Variation(k int) bool {
+	if k < 0 || k >= len(r.Current.Children) {
+		return false
+	}
+	r.Current = r.Current.Children[k]
+	return true
+}
+
+// 游标移到上一个节点，已经是根节点时返回 false
+func (r *GameRecord) Previous() bool {
+	if r.Current.Parent == nil {
+		return false
+	}
+	r.Current = r.Current.Parent
+	return true
+}
+
+// 游标移到根节点
+func (r *GameRecord) Rewind() {
+	r.Current = r.Root
+}
+
+// 游标移到指定的节点，从这里可以开始新的变化
+func (r *GameRecord) GoTo(n *GameNode) {
+	r.Current = n
+}
+
+// 主线上的所有节点，从根节点开始
+func (r *GameRecord) MainLine() []*GameNode {
+	nodes := []*GameNode{}
+	for n := r.Root; n != nil; {
+		nodes = append(nodes, n)
+		if len(n.Children) == 0 {
+			break
+		}
+		n = n.Children[0]
+	}
+	return nodes
+}
+
+// 从根节点到这个节点经过的所有节点
+func (n *GameNode) Path() []*GameNode {
+	path := []*GameNode{}
+	for ; n != nil; n = n.Parent {
+		path = append(path, n)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// 添加标记
+func (n *GameNode) AddMarkup(kind MarkupKind, p Point, label string) {
+	n.Markup = append(n.Markup, Markup{Kind: kind, Point: p, Label: label})
+}
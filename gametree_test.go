@@ -0,0 +1,86 @@
+package aigo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGameRecordVariations(t *testing.T) {
+	r := NewGameRecord(NewGameOfSize(9, 9))
+	a, _ := r.Play(NewPlay(Point{Row: 5, Col: 5}))
+	b, _ := r.Play(NewPlay(Point{Row: 3, Col: 3}))
+	if r.Current != b || b.Player != White || b.Parent != a {
+		t.Fatalf("主线: %+v", b)
+	}
+
+	// 回到第一步，下另一个变化
+	r.GoTo(a)
+	c, err := r.Play(NewPlay(Point{Row: 7, Col: 7}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Children) != 2 || a.Children[1] != c {
+		t.Errorf("第一步应该有两个变化: %d", len(a.Children))
+	}
+	// 同样的落子走到已有的变化
+	r.GoTo(a)
+	if n, _ := r.Play(NewPlay(Point{Row: 3, Col: 3})); n != b || len(a.Children) != 2 {
+		t.Errorf("应该走到已有的变化")
+	}
+	if path := b.Path(); len(path) != 3 || path[0] != r.Root || path[2] != b {
+		t.Errorf("Path: %v", path)
+	}
+	if main := r.MainLine(); len(main) != 3 || main[2] != b {
+		t.Errorf("MainLine: %v", main)
+	}
+
+	// 游标移动
+	r.Rewind()
+	if r.Previous() || !r.Next() || r.Current != a || !r.Variation(1) || r.Current != c || r.Variation(0) {
+		t.Errorf("游标移动出错")
+	}
+	if !r.Previous() || r.State() != a.State {
+		t.Errorf("Previous 应该回到第一步")
+	}
+
+	// 不合法的落子不添加节点，游标不动
+	if _, err := r.Play(NewPlay(Point{Row: 5, Col: 5})); !errors.Is(err, ErrOccupied) || r.Current != a || len(a.Children) != 2 {
+		t.Errorf("不合法的落子: %v", err)
+	}
+}
+
+func TestGameRecordSetupAndComments(t *testing.T) {
+	r := NewGameRecord(NewGameOfSize(9, 9))
+	r.Root.Comment = "开局"
+	r.Play(NewPlay(Point{Row: 5, Col: 5}))
+
+	// 黑棋连下两步
+	n, err := r.AddMove(Black, NewPlay(Point{Row: 5, Col: 6}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Player != Black || n.State.PlayerTurn != White || n.State.BoardPosition.Get(Point{Row: 5, Col: 6}) != Black {
+		t.Errorf("连下两步: %+v", n)
+	}
+
+	s, err := r.AddSetup(Setup{White: []Point{{Row: 1, Col: 1}}}, Black)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Setup == nil || s.Move != nil || s.State.PlayerTurn != Black || s.State.BoardPosition.Get(Point{Row: 1, Col: 1}) != White {
+		t.Errorf("摆棋: %+v", s)
+	}
+	if _, err := r.AddSetup(Setup{Black: []Point{{Row: 1, Col: 1}}}, White); !errors.Is(err, ErrOccupied) || r.Current != s {
+		t.Errorf("摆在已有棋子的地方: %v", err)
+	}
+
+	c := r.AddNode()
+	c.Comment = "形势"
+	c.AddMarkup(MarkLabel, Point{Row: 5, Col: 5}, "A")
+	if c.State != s.State || c.Move != nil || c.Setup != nil || c.Markup[0].Label != "A" {
+		t.Errorf("注释节点: %+v", c)
+	}
+	if _, err := r.AddMove(None, NewPass()); err == nil {
+		t.Errorf("None 不能落子")
+	}
+}
@@ -0,0 +1,163 @@
+package gtp
+
+import (
+	"fmt"
+	"ghj1976/aigo"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 外部 GTP 引擎当作 IAgent 使用，可以和 GNU Go 之类的引擎对战、比较强弱，例如
+//
+//	agent, err := gtp.NewAgent(exec.Command("gnugo", "--mode", "gtp"), 10*time.Second)
+//
+// 每次 SelectMove 先把对局历史同步给引擎，再用 genmove 让引擎选点。
+// 同步用 boardsize、clear_board、komi、set_free_handicap 和 play 命令；
+// 和上次同步过的历史是同一条线时只补发新的落子，不是时从 clear_board 开始重新同步。
+type Agent struct {
+	Client *Client // 外部引擎
+
+	sent []string // 引擎上已经执行过的同步命令
+	err  error    // 最近一次出错的原因
+}
+
+// 启动以后等引擎应答第一条命令的时间，引擎加载可能比较慢
+const StartupTimeout = 30 * time.Second
+
+// 启动 GTP 引擎进程，timeout 是每条命令等待应答的时间，0 表示一直等
+// 启动时的 protocol_version 最多等 timeout 和 StartupTimeout 里长的那个
+func NewAgent(cmd *exec.Cmd, timeout time.Duration) (*Agent, error) {
+	c, err := NewClient(cmd, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if timeout > 0 && timeout < StartupTimeout {
+		c.Timeout = StartupTimeout
+	}
+	version, err := c.Send("protocol_version")
+	c.Timeout = timeout
+	if err == nil && version != "2" {
+		err = fmt.Errorf("gtp: unsupported protocol version %q", version)
+	}
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return &Agent{Client: c}, nil
+}
+
+// 引擎选择的下一步
+// 引擎出错、超时或者选了不合法的点时认输，原因用 Err 查看
+func (a *Agent) SelectMove(gs *aigo.GameState) aigo.Move {
+	move, err := a.selectMove(gs)
+	if err != nil {
+		log.Printf("GTP 引擎出错，认输: %v", err)
+		a.err = err
+		a.sent = nil // 引擎的状态不确定了，下次从头同步
+		return aigo.NewResign()
+	}
+	return move
+}
+
+func (a *Agent) selectMove(gs *aigo.GameState) (aigo.Move, error) {
+	if err := a.sync(gs); err != nil {
+		return aigo.Move{}, err
+	}
+	color := colorName(gs.PlayerTurn)
+	vertex, err := a.Client.Send("genmove " + color)
+	if err != nil {
+		return aigo.Move{}, err
+	}
+	if strings.EqualFold(vertex, "resign") {
+		return aigo.NewResign(), nil
+	}
+	move, err := parseVertex(vertex)
+	if err == nil {
+		err = gs.CheckMove(move)
+	}
+	if err != nil {
+		return aigo.Move{}, fmt.Errorf("gtp: engine played %q: %w", vertex, err)
+	}
+	// 引擎已经在自己的棋盘上下了这一步，下次同步不用再发
+	a.sent = append(a.sent, "play "+color+" "+formatVertex(move))
+	return move, nil
+}
+
+// 最近一次 SelectMove 出错的原因，没有出过错时为 nil
+func (a *Agent) Err() error {
+	return a.err
+}
+
+// 关闭引擎进程
+func (a *Agent) Close() error {
+	return a.Client.Close()
+}
+
+// 把对局历史同步给引擎
+func (a *Agent) sync(gs *aigo.GameState) error {
+	commands, err := syncCommands(gs)
+	if err != nil {
+		return err
+	}
+	start := len(a.sent)
+	if start > len(commands) || strings.Join(a.sent, "\n") != strings.Join(commands[:start], "\n") {
+		a.sent, start = nil, 0
+	}
+	for _, command := range commands[start:] {
+		if _, err := a.Client.Send(command); err != nil {
+			return err
+		}
+		a.sent = append(a.sent, command)
+	}
+	return nil
+}
+
+// 在空的引擎上重现 gs 需要的命令
+// 开局的让子用 set_free_handicap；其他摆棋一颗一颗地 play，换手不用发，GTP 允许一方连下
+func syncCommands(gs *aigo.GameState) ([]string, error) {
+	history := []*aigo.GameState{}
+	for s := gs; s != nil; s = s.PreviousState {
+		history = append([]*aigo.GameState{s}, history...)
+	}
+	board := gs.BoardPosition
+	if board.Width != board.Height {
+		return nil, fmt.Errorf("gtp: board %dx%d is not square", board.Width, board.Height)
+	}
+	commands := []string{
+		"boardsize " + strconv.Itoa(int(board.Width)),
+		"clear_board",
+		"komi " + strconv.FormatFloat(gs.Rules.Komi, 'f', -1, 64),
+	}
+	for k, s := range history[1:] {
+		prev := history[k]
+		switch {
+		case s.Setup != nil:
+			if len(s.Setup.Empty) > 0 {
+				return nil, fmt.Errorf("gtp: cannot remove stones %s from the engine", formatVertices(s.Setup.Empty))
+			}
+			if k == 0 && s.Handicap > 0 && len(s.Setup.White) == 0 && len(s.Setup.Black) >= 2 {
+				commands = append(commands, "set_free_handicap "+formatVertices(s.Setup.Black))
+				continue
+			}
+			for _, p := range s.Setup.Black {
+				commands = append(commands, "play b "+formatVertex(aigo.NewPlay(p)))
+			}
+			for _, p := range s.Setup.White {
+				commands = append(commands, "play w "+formatVertex(aigo.NewPlay(p)))
+			}
+		case s.LastMove != nil && !s.LastMove.IsResign:
+			commands = append(commands, "play "+colorName(prev.PlayerTurn)+" "+formatVertex(*s.LastMove))
+		}
+	}
+	return commands, nil
+}
+
+func colorName(color aigo.Player) string {
+	if color == aigo.White {
+		return "w"
+	}
+	return "b"
+}
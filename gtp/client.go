@@ -0,0 +1,187 @@
+package gtp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GTP 客户端：启动外部的 GTP 引擎进程（比如 GNU Go），通过标准输入输出发命令、收应答
+
+var (
+	ErrTimeout = errors.New("gtp: engine did not respond in time") // 超时没有应答
+	ErrClosed  = errors.New("gtp: engine has exited")              // 引擎进程已经退出或者已经关闭
+)
+
+// 引擎对命令应答了失败（? 开头）
+type EngineError struct {
+	Command string // 失败的命令
+	Message string // 引擎给出的错误信息
+}
+
+func (e *EngineError) Error() string {
+	return fmt.Sprintf("gtp: %s: %s", e.Command, e.Message)
+}
+
+// 一条应答
+type response struct {
+	id   int    // 应答的编号，引擎没有带编号时为 -1
+	ok   bool   // = 开头是成功，? 开头是失败
+	text string // 应答的内容，多行的用 \n 连接
+}
+
+// Close 默认等引擎退出的时间
+const DefaultQuitTimeout = 5 * time.Second
+
+// 外部 GTP 引擎
+type Client struct {
+	Timeout     time.Duration // 每条命令等待应答的时间，0 表示一直等
+	QuitTimeout time.Duration // Close 发出 quit 以后等引擎退出的时间，超时杀掉进程，跟 Timeout 无关
+
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan response // 读应答的 goroutine 发来的应答，引擎退出时关闭
+	id        int           // 上一条命令的编号
+	closed    bool
+}
+
+// 启动引擎进程，cmd 还没有启动，调用方可以事先设置 Stderr、Dir 等
+func NewClient(cmd *exec.Cmd, timeout time.Duration) (*Client, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	c := &Client{Timeout: timeout, QuitTimeout: DefaultQuitTimeout, cmd: cmd, stdin: stdin, responses: make(chan response, 16)}
+	go c.read(stdout)
+	return c, nil
+}
+
+// 读应答，每条应答以空行结束
+func (c *Client) read(stdout io.Reader) {
+	defer close(c.responses)
+	scanner := bufio.NewScanner(stdout)
+	lines := []string{}
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line != "" {
+			lines = append(lines, line)
+			continue
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		if r, ok := parseResponse(lines); ok {
+			c.responses <- r
+		}
+		lines = lines[:0]
+	}
+}
+
+// 第一行是 =编号 内容 或者 ?编号 错误信息，后面的行是内容的继续
+func parseResponse(lines []string) (response, bool) {
+	first := lines[0]
+	if first[0] != '=' && first[0] != '?' {
+		return response{}, false
+	}
+	r := response{id: -1, ok: first[0] == '='}
+	rest := first[1:]
+	k := 0
+	for k < len(rest) && '0' <= rest[k] && rest[k] <= '9' {
+		k++
+	}
+	if k > 0 {
+		r.id, _ = strconv.Atoi(rest[:k])
+	}
+	lines = append([]string{strings.TrimSpace(rest[k:])}, lines[1:]...)
+	r.text = strings.Join(lines, "\n")
+	return r, true
+}
+
+// 发送一条命令，等待应答的内容
+// 引擎应答失败时返回 *EngineError；超时返回 ErrTimeout，之后迟到的应答会被丢掉
+func (c *Client) Send(command string) (string, error) {
+	if c.closed {
+		return "", ErrClosed
+	}
+	c.id++
+	id := c.id
+	if _, err := fmt.Fprintf(c.stdin, "%d %s\n", id, command); err != nil {
+		return "", fmt.Errorf("%s: %w", command, ErrClosed)
+	}
+	var timeout <-chan time.Time
+	if c.Timeout > 0 {
+		timer := time.NewTimer(c.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for {
+		select {
+		case r, ok := <-c.responses:
+			if !ok {
+				return "", fmt.Errorf("%s: %w", command, ErrClosed)
+			}
+			if r.id >= 0 && r.id != id { // 之前超时的命令迟到的应答
+				continue
+			}
+			if !r.ok {
+				return "", &EngineError{Command: command, Message: r.text}
+			}
+			return r.text, nil
+		case <-timeout:
+			return "", fmt.Errorf("%s: %w", command, ErrTimeout)
+		}
+	}
+}
+
+// 发送 quit 让引擎退出，QuitTimeout 之内没有退出时杀掉进程
+// 引擎正常退出时返回 nil，可以重复调用
+func (c *Client) Close() error {
+	if c.closed {
+		return nil
+	}
+	deadline := time.Now().Add(c.QuitTimeout)
+	timeout := c.Timeout
+	c.Timeout = c.QuitTimeout
+	_, quitErr := c.Send("quit")
+	c.Timeout = timeout
+	c.closed = true
+	c.stdin.Close()
+
+	// 读完所有输出以后才能 Wait
+	done := make(chan struct{})
+	go func() {
+		for range c.responses {
+		}
+		close(done)
+	}()
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	killed := false
+	select {
+	case <-done:
+	case <-timer.C:
+		c.cmd.Process.Kill()
+		killed = true
+		<-done
+	}
+	err := c.cmd.Wait()
+	if killed {
+		return fmt.Errorf("gtp: engine killed after quit: %w", ErrTimeout)
+	}
+	if quitErr != nil && err == nil {
+		return quitErr
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+package gtp
+
+import (
+	"errors"
+	"ghj1976/aigo"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 假的 GTP 引擎进程：测试程序用 -test.run=TestHelperProcess 启动自己，
+// 环境变量 GTP_HELPER_MODE 决定 genmove 时的行为
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_GTP_HELPER") != "1" {
+		return
+	}
+	e := NewEngine(helperBot{os.Getenv("GTP_HELPER_MODE")}, "fake", "1.0")
+	if os.Getenv("GTP_HELPER_MODE") == "noquit" { // 不理会 quit 的引擎
+		e.commands["quit"] = func(args []string) (string, error) {
+			time.Sleep(time.Hour)
+			return "", nil
+		}
+	}
+	e.Run(os.Stdin, os.Stdout)
+	os.Exit(0)
+}
+
+type helperBot struct {
+	mode string
+}
+
+func (b helperBot) SelectMove(gs *aigo.GameState) aigo.Move {
+	switch b.mode {
+	case "slow": // 想很久
+		time.Sleep(10 * time.Second)
+	case "exit": // 进程崩溃
+		os.Exit(3)
+	case "invalid": // 引擎应答失败
+		return aigo.Move{}
+	}
+	return firstLegalBot{}.SelectMove(gs)
+}
+
+func helperCommand(mode string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess")
+	cmd.Env = append(os.Environ(), "GO_WANT_GTP_HELPER=1", "GTP_HELPER_MODE="+mode)
+	return cmd
+}
+
+func startAgent(t *testing.T, mode string, timeout time.Duration) *Agent {
+	a, err := NewAgent(helperCommand(mode), timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+// 引擎的棋盘、贴目跟 gs 一样
+func checkSynced(t *testing.T, a *Agent, gs *aigo.GameState) {
+	t.Helper()
+	board, err := a.Client.Send("showboard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "\n" + strings.TrimRight(strings.ReplaceAll(gs.BoardPosition.PrintBoard(), "\r\n", "\n"), "\n")
+	if board != want {
+		t.Fatalf("引擎的棋盘:\n%s\n应该是:\n%s", board, want)
+	}
+	score, err := a.Client.Send("final_score")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, _ := gs.ComputeGameResultWithDeadStones(aigo.NewDeadStoneEstimatorWithSeed(200, 1))
+	if want := result.SGF(); score != want {
+		t.Errorf("引擎的计分 %s, 应该是 %s", score, want)
+	}
+}
+
+func TestAgentSync(t *testing.T) {
+	a := startAgent(t, "", 10*time.Second)
+	defer a.Close()
+
+	rules := aigo.ChineseRules // 假引擎用中国规则计分
+	rules.Komi = 3.5
+	gs, _ := aigo.NewGameWithRules(5, 5, rules).ApplyMove(aigo.NewPlay(aigo.Point{Row: 3, Col: 3}))
+	gs, _ = gs.ApplyMove(a.SelectMove(gs))
+	if a.Err() != nil || gs.BoardPosition.Get(aigo.Point{Row: 5, Col: 1}) != aigo.White {
+		t.Fatalf("引擎应该下 A5: %v\n%v", a.Err(), gs)
+	}
+	checkSynced(t, a, gs)
+	sent := len(a.sent)
+
+	// 同一条线上只补发新的落子
+	branch := gs.PreviousState
+	gs, _ = gs.ApplyMove(aigo.NewPass())
+	move := a.SelectMove(gs)
+	if len(a.sent) != sent+2 || !move.IsPlay {
+		t.Errorf("应该只补发一步: %v", a.sent)
+	}
+	gs, _ = gs.ApplyMove(move)
+	checkSynced(t, a, gs)
+
+	// 换到另一个变化，从头同步
+	branch, _ = branch.ApplyMove(aigo.NewPlay(aigo.Point{Row: 1, Col: 1}))
+	move = a.SelectMove(branch)
+	branch, _ = branch.ApplyMove(move)
+	checkSynced(t, a, branch)
+
+	// 让子棋，连下两步
+	gs, err := aigo.NewHandicapGame(9, 2, aigo.ChineseRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs, _ = gs.ApplySetup(aigo.Setup{}, aigo.Black)
+	gs, _ = gs.ApplyMove(aigo.NewPlay(aigo.Point{Row: 5, Col: 5}))
+	gs, _ = gs.ApplyMove(a.SelectMove(gs))
+	checkSynced(t, a, gs)
+	if !strings.HasPrefix(a.sent[3], "set_free_handicap ") || a.Err() != nil {
+		t.Errorf("同步的命令: %v %v", a.sent, a.Err())
+	}
+
+	if err := a.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if !a.Client.cmd.ProcessState.Success() {
+		t.Errorf("引擎没有正常退出: %v", a.Client.cmd.ProcessState)
+	}
+	if _, err := a.Client.Send("name"); !errors.Is(err, ErrClosed) {
+		t.Errorf("关闭以后: %v", err)
+	}
+}
+
+func TestAgentErrors(t *testing.T) {
+	gs := aigo.NewGameOfSize(5, 5)
+
+	a := startAgent(t, "invalid", 10*time.Second)
+	var engineErr *EngineError
+	if m := a.SelectMove(gs); !m.IsResign || !errors.As(a.Err(), &engineErr) || engineErr.Command != "genmove b" {
+		t.Errorf("引擎应答失败: %v %v", m, a.Err())
+	}
+	// 出错以后引擎还能用
+	if name, err := a.Client.Send("name"); err != nil || name != "fake" {
+		t.Errorf("name: %q %v", name, err)
+	}
+	if err := a.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	a = startAgent(t, "slow", 200*time.Millisecond)
+	if m := a.SelectMove(gs); !m.IsResign || !errors.Is(a.Err(), ErrTimeout) {
+		t.Errorf("超时: %v %v", m, a.Err())
+	}
+	a.Client.QuitTimeout = 200 * time.Millisecond
+	if err := a.Close(); !errors.Is(err, ErrTimeout) {
+		t.Errorf("想很久的引擎要杀掉: %v", err)
+	}
+
+	// 一直等应答，Close 也不会一直等下去
+	a = startAgent(t, "noquit", 0)
+	a.Client.QuitTimeout = 200 * time.Millisecond
+	start := time.Now()
+	if err := a.Close(); !errors.Is(err, ErrTimeout) || time.Since(start) > 5*time.Second {
+		t.Errorf("不理会 quit 的引擎要杀掉: %v %v", err, time.Since(start))
+	}
+
+	a = startAgent(t, "exit", 10*time.Second)
+	if m := a.SelectMove(gs); !m.IsResign || !errors.Is(a.Err(), ErrClosed) {
+		t.Errorf("引擎崩溃: %v %v", m, a.Err())
+	}
+	if err := a.Close(); err == nil {
+		t.Errorf("崩溃的引擎退出码不是 0")
+	}
+}
+
+func TestSyncCommands(t *testing.T) {
+	gs := aigo.NewGameOfSize(9, 9)
+	gs, _ = gs.ApplySetup(aigo.Setup{Black: []aigo.Point{{Row: 1, Col: 1}}, White: []aigo.Point{{Row: 9, Col: 9}}}, aigo.White)
+	gs, _ = gs.ApplyMove(aigo.NewPass())
+	gs, _ = gs.ApplyMove(aigo.NewPlay(aigo.Point{Row: 3, Col: 4}))
+	commands, err := syncCommands(gs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "boardsize 9,clear_board,komi 7.5,play b A1,play w J9,play w pass,play b D3"
+	if got := strings.Join(commands, ","); got != want {
+		t.Errorf("同步命令:\n%s\n应该是:\n%s", got, want)
+	}
+	if _, err := syncCommands(aigo.NewGameOfSize(9, 7)); err == nil {
+		t.Errorf("GTP 只支持正方形棋盘")
+	}
+	gs, _ = gs.ApplySetup(aigo.Setup{Empty: []aigo.Point{{Row: 1, Col: 1}}}, aigo.White)
+	if _, err := syncCommands(gs); err == nil {
+		t.Errorf("GTP 不能拿掉棋子")
+	}
+}
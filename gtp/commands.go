@@ -0,0 +1,310 @@
+package gtp
+
+import (
+	"errors"
+	"fmt"
+	"ghj1976/aigo"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GTP 规定的错误信息
+var (
+	errUnknownCommand   = errors.New("unknown command")
+	errSyntax           = errors.New("syntax error")
+	errIllegalMove      = errors.New("illegal move")
+	errCannotUndo       = errors.New("cannot undo")
+	errUnacceptableSize = errors.New("unacceptable size")
+	errBoardNotEmpty    = errors.New("board not empty")
+	errInvalidStones    = errors.New("invalid number of stones")
+	errBadVertexList    = errors.New("bad vertex list")
+)
+
+// 颜色：b、black、w、white，大小写都可以
+func parseColor(s string) (aigo.Player, error) {
+	switch strings.ToLower(s) {
+	case "b", "black":
+		return aigo.Black, nil
+	case "w", "white":
+		return aigo.White, nil
+	}
+	return aigo.None, errSyntax
+}
+
+// 坐标：pass，或者列字母（没有 I）加行号，比如 D4，大小写都可以
+// 格式不对返回 errSyntax；格式对但是不在棋盘上的点交给 CheckMove 判断
+func parseVertex(s string) (aigo.Move, error) {
+	s = strings.ToUpper(s)
+	if s == "PASS" {
+		return aigo.NewPass(), nil
+	}
+	if len(s) < 2 || strings.IndexByte(aigo.COLS, s[0]) < 0 {
+		return aigo.Move{}, errSyntax
+	}
+	row, err := strconv.Atoi(s[1:])
+	if err != nil || row < 1 || row > aigo.MaxBoardSize {
+		return aigo.Move{}, errSyntax
+	}
+	return aigo.NewPlay(aigo.Point{Row: uint16(row), Col: uint16(strings.IndexByte(aigo.COLS, s[0]) + 1)}), nil
+}
+
+// 应答里的坐标：pass、resign 或者 D4
+func formatVertex(m aigo.Move) string {
+	switch {
+	case m.IsPass:
+		return "pass"
+	case m.IsResign:
+		return "resign"
+	}
+	return m.StringChessRecord()
+}
+
+func formatVertices(points []aigo.Point) string {
+	vertices := make([]string, len(points))
+	for k, p := range points {
+		vertices[k] = formatVertex(aigo.NewPlay(p))
+	}
+	return strings.Join(vertices, " ")
+}
+
+func (e *Engine) boardSize(args []string) (string, error) {
+	if len(args) < 1 {
+		return "", errSyntax
+	}
+	size, err := strconv.Atoi(args[0])
+	if err != nil {
+		return "", errSyntax
+	}
+	if size < 1 || size > aigo.MaxBoardSize {
+		return "", errUnacceptableSize
+	}
+	e.size = uint16(size)
+	e.Game = aigo.NewGameWithRules(e.size, e.size, e.Rules)
+	return "", nil
+}
+
+func (e *Engine) clearBoard(args []string) (string, error) {
+	e.Game = aigo.NewGameWithRules(e.size, e.size, e.Rules)
+	return "", nil
+}
+
+// 贴目改到整个对局历史上，悔棋以后也不变
+func (e *Engine) komi(args []string) (string, error) {
+	if len(args) < 1 {
+		return "", errSyntax
+	}
+	komi, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return "", errSyntax
+	}
+	e.Rules.Komi = komi
+	for gs := e.Game; gs != nil; gs = gs.PreviousState {
+		gs.Rules.Komi = komi
+	}
+	return "", nil
+}
+
+func (e *Engine) play(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", errSyntax
+	}
+	color, err := parseColor(args[0])
+	if err != nil {
+		return "", err
+	}
+	move, err := parseVertex(args[1])
+	if err != nil {
+		return "", err
+	}
+	gs, err := e.applyMove(color, move)
+	if err != nil {
+		return "", errIllegalMove
+	}
+	e.Game = gs
+	return "", nil
+}
+
+// color 一方下 move，只有不在棋盘上、有子、自杀和劫争的落子不合法
+// 不合法时返回 CheckMove 的错误
+func (e *Engine) applyMove(color aigo.Player, move aigo.Move) (*aigo.GameState, error) {
+	return e.turn(color).ApplyMove(move)
+}
+
+// 轮到 color 下的局面
+// GTP 允许一方连下，也没有对局结束的状态，双方跳过以后还可以接着下（比如收拾死子），
+// 不是轮到 color 或者对局已经结束时插入一个空的摆棋换手
+func (e *Engine) turn(color aigo.Player) *aigo.GameState {
+	gs := e.Game
+	if gs.PlayerTurn != color || gs.IsOver() {
+		gs, _ = gs.ApplySetup(aigo.Setup{}, color)
+	}
+	return gs
+}
+
+func (e *Engine) genMove(args []string) (string, error) {
+	if len(args) < 1 {
+		return "", errSyntax
+	}
+	color, err := parseColor(args[0])
+	if err != nil {
+		return "", err
+	}
+	gs := e.turn(color)
+	move := e.Agent.SelectMove(gs)
+	ngs, err := gs.ApplyMove(move)
+	if err != nil {
+		return "", fmt.Errorf("engine chose an illegal move %s: %v", formatVertex(move), err)
+	}
+	e.Game = ngs
+	return formatVertex(move), nil
+}
+
+// 撤销上一步落子，连同为了一方连下插入的换手；让子不能撤销
+func (e *Engine) undo(args []string) (string, error) {
+	gs := e.Game
+	if gs.LastMove == nil || gs.PreviousState == nil {
+		return "", errCannotUndo
+	}
+	gs = gs.PreviousState
+	for gs.Setup != nil && len(gs.Setup.Black) == 0 && len(gs.Setup.White) == 0 && len(gs.Setup.Empty) == 0 && gs.PreviousState != nil {
+		gs = gs.PreviousState
+	}
+	e.Game = gs
+	return "", nil
+}
+
+// 应答不能有空行，PrintBoard 的 \r\n 换成 \n
+func (e *Engine) showBoard(args []string) (string, error) {
+	board := strings.ReplaceAll(e.Game.BoardPosition.PrintBoard(), "\r\n", "\n")
+	return "\n" + strings.TrimRight(board, "\n"), nil
+}
+
+// 认输结束的是认输的结果，其他的估计死子、提走以后按当前局面计分，格式同 SGF 的 RE 属性
+// 死子估计用固定的随机数种子，同样的局面得到同样的结果
+func (e *Engine) finalScore(args []string) (string, error) {
+	if result := e.Game.Winner(); result != nil && result.Kind == aigo.ResultResign {
+		return result.SGF(), nil
+	}
+	result, _ := e.Game.ComputeGameResultWithDeadStones(aigo.NewDeadStoneEstimatorWithSeed(e.DeadStonePlayouts, 1))
+	return result.SGF(), nil
+}
+
+func (e *Engine) timeSettings(args []string) (string, error) {
+	values, err := parseInts(args, 3)
+	if err != nil {
+		return "", err
+	}
+	e.Time = TimeSettings{
+		MainTime:      time.Duration(values[0]) * time.Second,
+		ByoYomiTime:   time.Duration(values[1]) * time.Second,
+		ByoYomiStones: values[2],
+	}
+	return "", nil
+}
+
+func (e *Engine) timeLeft(args []string) (string, error) {
+	if len(args) < 3 {
+		return "", errSyntax
+	}
+	color, err := parseColor(args[0])
+	if err != nil {
+		return "", err
+	}
+	values, err := parseInts(args[1:], 2)
+	if err != nil {
+		return "", err
+	}
+	e.TimeLeft[color] = TimeLeft{Time: time.Duration(values[0]) * time.Second, Stones: values[1]}
+	return "", nil
+}
+
+// 前 n 个参数都是非负整数
+func parseInts(args []string, n int) ([]int, error) {
+	if len(args) < n {
+		return nil, errSyntax
+	}
+	values := make([]int, n)
+	for k := range values {
+		v, err := strconv.Atoi(args[k])
+		if err != nil || v < 0 {
+			return nil, errSyntax
+		}
+		values[k] = v
+	}
+	return values, nil
+}
+
+func (e *Engine) fixedHandicap(args []string) (string, error) {
+	stones, err := e.handicapStones(args)
+	if err != nil {
+		return "", err
+	}
+	points, err := aigo.HandicapPoints(e.size, stones)
+	if err != nil {
+		return "", errInvalidStones
+	}
+	return formatVertices(points), e.setHandicap(points)
+}
+
+func (e *Engine) placeFreeHandicap(args []string) (string, error) {
+	stones, err := e.handicapStones(args)
+	if err != nil {
+		return "", err
+	}
+	var points []aigo.Point
+	if e.Placer != nil {
+		points = e.Placer.PlaceHandicap(e.Game, stones)
+	} else if points, err = aigo.HandicapPoints(e.size, stones); err != nil {
+		return "", errInvalidStones
+	}
+	if len(points) != stones {
+		return "", errInvalidStones
+	}
+	return formatVertices(points), e.setHandicap(points)
+}
+
+func (e *Engine) setFreeHandicap(args []string) (string, error) {
+	if _, err := e.handicapStones([]string{strconv.Itoa(len(args))}); err != nil {
+		return "", err
+	}
+	points := make([]aigo.Point, 0, len(args))
+	for _, v := range args {
+		move, err := parseVertex(v)
+		if err != nil || !move.IsPlay {
+			return "", errBadVertexList
+		}
+		points = append(points, move.Pnt)
+	}
+	return "", e.setHandicap(points)
+}
+
+// 让子数的参数，只能在空棋盘上让子，至少 2 子，至少要留一个空点
+func (e *Engine) handicapStones(args []string) (int, error) {
+	if len(args) < 1 {
+		return 0, errSyntax
+	}
+	stones, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, errSyntax
+	}
+	if e.Game.LastMove != nil || e.Game.PreviousState != nil || !e.Game.BoardPosition.Equal(aigo.NewBoard(e.size, e.size)) {
+		return 0, errBoardNotEmpty
+	}
+	if stones < 2 || stones >= int(e.size)*int(e.size) {
+		return 0, errInvalidStones
+	}
+	return stones, nil
+}
+
+// 在空棋盘上摆好让子，轮到白棋下；贴目不变，由控制端用 komi 命令设置
+func (e *Engine) setHandicap(points []aigo.Point) error {
+	gs := aigo.NewGameWithRules(e.size, e.size, e.Rules)
+	gs.Handicap = len(points)
+	setup, err := gs.ApplySetup(aigo.Setup{Black: points}, aigo.White)
+	if err != nil {
+		return errBadVertexList
+	}
+	e.Game = setup
+	return nil
+}
@@ -0,0 +1,175 @@
+package gtp
+
+import (
+	"bufio"
+	"ghj1976/aigo"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GTP（Go Text Protocol 第 2 版）引擎
+// 把任意 aigo.IAgent 包装成 GTP 引擎，从输入读命令，把应答写到输出，
+// Sabaki 之类的界面、gogui-twogtp 之类的对战工具都可以直接使用这里的机器人。
+// https://www.lysator.liu.se/~gunnar/gtp/gtp2-spec-draft2/gtp2-spec.html
+
+// 用时设置，time_settings 命令设置；目前的机器人不按时间控制思考，只是记录下来
+type TimeSettings struct {
+	MainTime      time.Duration // 基本用时
+	ByoYomiTime   time.Duration // 读秒时间，0 表示没有读秒
+	ByoYomiStones int           // 读秒时间内要下的步数，0 表示没有时间限制
+}
+
+// 剩余时间，time_left 命令设置
+type TimeLeft struct {
+	Time   time.Duration // 剩余时间
+	Stones int           // 读秒阶段还要下的步数，基本用时阶段为 0
+}
+
+// GTP 引擎
+type Engine struct {
+	Name    string              // name 命令的应答
+	Version string              // version 命令的应答
+	Agent   aigo.IAgent         // genmove 使用的机器人
+	Rules   aigo.Ruleset        // 新对局使用的规则，komi 命令修改其中的贴目
+	Placer  aigo.HandicapPlacer // place_free_handicap 选择让子位置，为 nil 时摆在固定让子的星位上
+
+	DeadStonePlayouts int // final_score 估计死子时模拟对局的次数
+
+	Time     TimeSettings // 用时设置
+	TimeLeft [3]TimeLeft  // 以 Player 为下标，各方的剩余时间
+
+	Game *aigo.GameState // 当前局面
+
+	size     uint16
+	commands map[string]func(args []string) (string, error)
+}
+
+// 构造 GTP 引擎，默认是 19 路棋盘、中国规则，估计死子模拟 200 盘
+func NewEngine(agent aigo.IAgent, name, version string) *Engine {
+	e := &Engine{Name: name, Version: version, Agent: agent, Rules: aigo.ChineseRules, DeadStonePlayouts: 200, size: 19}
+	e.Game = aigo.NewGameWithRules(e.size, e.size, e.Rules)
+	e.commands = map[string]func(args []string) (string, error){
+		"protocol_version":    e.protocolVersion,
+		"name":                e.name,
+		"version":             e.version,
+		"known_command":       e.knownCommand,
+		"list_commands":       e.listCommands,
+		"quit":                e.quit,
+		"boardsize":           e.boardSize,
+		"clear_board":         e.clearBoard,
+		"komi":                e.komi,
+		"play":                e.play,
+		"genmove":             e.genMove,
+		"undo":                e.undo,
+		"showboard":           e.showBoard,
+		"final_score":         e.finalScore,
+		"time_settings":       e.timeSettings,
+		"time_left":           e.timeLeft,
+		"fixed_handicap":      e.fixedHandicap,
+		"place_free_handicap": e.placeFreeHandicap,
+		"set_free_handicap":   e.setFreeHandicap,
+	}
+	return e
+}
+
+// 从 r 读命令，把应答写到 w，直到 quit 命令或者输入结束
+func (e *Engine) Run(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		id, name, args, ok := parseCommand(scanner.Text())
+		if !ok {
+			continue
+		}
+		result, err := e.Execute(name, args...)
+		response := "=" + id + " " + result
+		if err != nil {
+			response = "?" + id + " " + err.Error()
+		}
+		if _, err := io.WriteString(w, response+"\n\n"); err != nil {
+			return err
+		}
+		if name == "quit" {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// 执行一条命令，返回应答的内容；失败时错误的内容就是 GTP 的错误信息
+func (e *Engine) Execute(name string, args ...string) (string, error) {
+	cmd, ok := e.commands[name]
+	if !ok {
+		return "", errUnknownCommand
+	}
+	return cmd(args)
+}
+
+// 预处理一行命令：去掉注释和控制字符，制表符换成空格，拆出可选的数字编号、命令名和参数
+// 空行和只有注释的行返回 ok 为 false
+func parseCommand(line string) (id, name string, args []string, ok bool) {
+	if k := strings.IndexByte(line, '#'); k >= 0 {
+		line = line[:k]
+	}
+	line = strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		}
+		if r < 32 || r == 127 {
+			return -1
+		}
+		return r
+	}, line)
+	fields := strings.Fields(line)
+	if len(fields) > 0 && isNumber(fields[0]) {
+		id, fields = fields[0], fields[1:]
+	}
+	if len(fields) == 0 {
+		return "", "", nil, false
+	}
+	return id, fields[0], fields[1:], true
+}
+
+func isNumber(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func (e *Engine) protocolVersion(args []string) (string, error) {
+	return "2", nil
+}
+
+func (e *Engine) name(args []string) (string, error) {
+	return e.Name, nil
+}
+
+func (e *Engine) version(args []string) (string, error) {
+	return e.Version, nil
+}
+
+func (e *Engine) knownCommand(args []string) (string, error) {
+	if len(args) < 1 {
+		return "", errSyntax
+	}
+	_, ok := e.commands[args[0]]
+	return strconv.FormatBool(ok), nil
+}
+
+func (e *Engine) listCommands(args []string) (string, error) {
+	names := make([]string, 0, len(e.commands))
+	for name := range e.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, "\n"), nil
+}
+
+func (e *Engine) quit(args []string) (string, error) {
+	return "", nil
+}
@@ -0,0 +1,175 @@
+package gtp
+
+import (
+	"bytes"
+	"ghj1976/aigo"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 总是下第一个合法的点，没有时跳过
+type firstLegalBot struct{}
+
+func (firstLegalBot) SelectMove(gs *aigo.GameState) aigo.Move {
+	for row := gs.BoardPosition.Height; row >= 1; row-- {
+		for col := uint16(1); col <= gs.BoardPosition.Width; col++ {
+			m := aigo.NewPlay(aigo.Point{Row: row, Col: col})
+			if gs.IsValidMove(m) {
+				return m
+			}
+		}
+	}
+	return aigo.NewPass()
+}
+
+// 执行命令，检查应答
+func expect(t *testing.T, e *Engine, want string, name string, args ...string) {
+	t.Helper()
+	got, err := e.Execute(name, args...)
+	if err != nil {
+		got = "?" + err.Error()
+	}
+	if got != want {
+		t.Errorf("%s %v: %q, 应该是 %q", name, args, got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	e := NewEngine(firstLegalBot{}, "test", "1.0")
+	in := "protocol_version\n" +
+		"# 注释行\n" +
+		"\n" +
+		"1 name # 行尾注释\n" +
+		"2\tboardsize\t7\n" +
+		"3 foo\n" +
+		"4 genmove b\n" +
+		"quit\n" +
+		"version\n"
+	out := &bytes.Buffer{}
+	if err := e.Run(strings.NewReader(in), out); err != nil {
+		t.Fatal(err)
+	}
+	want := "= 2\n\n=1 test\n\n=2 \n\n?3 unknown command\n\n=4 A7\n\n= \n\n"
+	if out.String() != want {
+		t.Errorf("应答:\n%q\n应该是:\n%q", out.String(), want)
+	}
+}
+
+func TestPlayAndUndo(t *testing.T) {
+	e := NewEngine(firstLegalBot{}, "test", "1.0")
+	expect(t, e, "?unacceptable size", "boardsize", "26")
+	expect(t, e, "?syntax error", "boardsize", "x")
+	expect(t, e, "", "boardsize", "5")
+	expect(t, e, "", "play", "black", "c3")
+	expect(t, e, "?illegal move", "play", "w", "C3")
+	expect(t, e, "?illegal move", "play", "w", "F1")
+	expect(t, e, "?syntax error", "play", "w", "I3")
+	expect(t, e, "?syntax error", "play", "x", "A1")
+	expect(t, e, "?syntax error", "play", "w")
+	// 黑棋连下两步
+	expect(t, e, "", "play", "B", "B2")
+	if e.Game.PlayerTurn != aigo.White || e.Game.BoardPosition.Get(aigo.Point{Row: 2, Col: 2}) != aigo.Black {
+		t.Errorf("连下两步:\n%v", e.Game)
+	}
+	expect(t, e, "", "play", "w", "pass")
+	expect(t, e, "", "undo")
+	expect(t, e, "", "undo")
+	if e.Game.PlayerTurn != aigo.White || e.Game.LastMove == nil || e.Game.LastMove.Pnt != (aigo.Point{Row: 3, Col: 3}) {
+		t.Errorf("悔棋以后应该回到黑棋下 C3 之后:\n%v", e.Game)
+	}
+	expect(t, e, "", "undo")
+	expect(t, e, "?cannot undo", "undo")
+	expect(t, e, "\n05 .....\n04 .....\n03 .....\n02 .....\n01 .....\n   ABCDE", "showboard")
+	expect(t, e, "true", "known_command", "fixed_handicap")
+	expect(t, e, "false", "known_command", "loadsgf")
+}
+
+func TestGenMove(t *testing.T) {
+	e := NewEngine(firstLegalBot{}, "test", "1.0")
+	expect(t, e, "", "boardsize", "5")
+	expect(t, e, "A5", "genmove", "b")
+	// 轮到白棋，要黑棋下
+	expect(t, e, "B5", "genmove", "black")
+	expect(t, e, "C5", "genmove", "w")
+	expect(t, e, "", "play", "b", "pass")
+	expect(t, e, "", "play", "w", "pass")
+	if !e.Game.IsOver() {
+		t.Fatal("双方跳过应该结束")
+	}
+	// GTP 没有对局结束的状态，双方跳过以后还可以接着下
+	expect(t, e, "D5", "genmove", "b")
+	expect(t, e, "", "play", "w", "pass")
+	expect(t, e, "", "play", "b", "pass")
+	expect(t, e, "", "play", "b", "E1")
+	expect(t, e, "?illegal move", "play", "w", "E1")
+	expect(t, e, "", "undo")
+	if !e.Game.IsOver() {
+		t.Errorf("悔棋以后应该回到双方跳过:\n%v", e.Game)
+	}
+}
+
+func TestFinalScore(t *testing.T) {
+	e := NewEngine(firstLegalBot{}, "test", "1.0")
+	expect(t, e, "", "boardsize", "5")
+	expect(t, e, "", "play", "b", "c3")
+	expect(t, e, "B+17.5", "final_score")
+	expect(t, e, "", "komi", "0")
+	expect(t, e, "B+25", "final_score")
+	// 悔棋以后贴目也不变
+	expect(t, e, "", "undo")
+	expect(t, e, "0", "final_score")
+	expect(t, e, "?syntax error", "komi", "six")
+	// 新对局用新的贴目
+	expect(t, e, "", "clear_board")
+	if e.Game.Rules.Komi != 0 || e.Game.Rules.Name != "Chinese" {
+		t.Errorf("规则: %v %v", e.Game.Rules, e.Game.Rules.Komi)
+	}
+	// 黑地里白棋的死子提走以后再计分
+	for row := 1; row <= 5; row++ {
+		expect(t, e, "", "play", "b", "C"+strconv.Itoa(row))
+		expect(t, e, "", "play", "w", "D"+strconv.Itoa(row))
+	}
+	expect(t, e, "", "play", "w", "A1")
+	expect(t, e, "B+5", "final_score")
+}
+
+func TestHandicap(t *testing.T) {
+	e := NewEngine(firstLegalBot{}, "test", "1.0")
+	expect(t, e, "", "boardsize", "9")
+	expect(t, e, "C3 G7", "fixed_handicap", "2")
+	if e.Game.PlayerTurn != aigo.White || e.Game.Handicap != 2 || e.Game.Rules.Komi != aigo.ChineseRules.Komi {
+		t.Errorf("固定让子:\n%v", e.Game)
+	}
+	expect(t, e, "?cannot undo", "undo")
+	expect(t, e, "?board not empty", "fixed_handicap", "2")
+	expect(t, e, "", "clear_board")
+	expect(t, e, "?invalid number of stones", "fixed_handicap", "10")
+	expect(t, e, "?invalid number of stones", "set_free_handicap", "A1")
+	expect(t, e, "?bad vertex list", "set_free_handicap", "A1", "A1")
+	expect(t, e, "", "set_free_handicap", "A1", "B2", "c3")
+	if e.Game.Handicap != 3 || e.Game.BoardPosition.Get(aigo.Point{Row: 3, Col: 3}) != aigo.Black {
+		t.Errorf("自由让子:\n%v", e.Game)
+	}
+
+	expect(t, e, "", "clear_board")
+	expect(t, e, "C3 G7 C7 G3", "place_free_handicap", "4")
+	e.Placer = aigo.HandicapPlacerFunc(func(gs *aigo.GameState, stones int) []aigo.Point {
+		return []aigo.Point{{Row: 1, Col: 1}, {Row: 9, Col: 9}}
+	})
+	expect(t, e, "", "clear_board")
+	expect(t, e, "A1 J9", "place_free_handicap", "2")
+	expect(t, e, "", "clear_board")
+	expect(t, e, "?invalid number of stones", "place_free_handicap", "3")
+}
+
+func TestTimeSettings(t *testing.T) {
+	e := NewEngine(firstLegalBot{}, "test", "1.0")
+	expect(t, e, "", "time_settings", "600", "30", "5")
+	expect(t, e, "", "time_left", "white", "25", "3")
+	expect(t, e, "?syntax error", "time_left", "white", "-1", "0")
+	if e.Time.MainTime != 10*time.Minute || e.Time.ByoYomiStones != 5 || e.TimeLeft[aigo.White] != (TimeLeft{25 * time.Second, 3}) {
+		t.Errorf("用时: %+v %+v", e.Time, e.TimeLeft)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"ghj1976/aigo"
+	"ghj1976/aigo/gtp"
+	"log"
+	"math/rand"
+	"os"
+	"time"
+)
+
+// GTP 引擎，通过标准输入输出和界面、对战工具通讯，例如
+//
+//	gogui-twogtp -black "gtpserver -bot mcts" -white "gtpserver -bot random" -size 9 -games 10
+//
+// 日志写到标准错误，标准输出只有 GTP 应答
+func main() {
+	botName := flag.String("bot", "mcts", "机器人：random、fastrandom、alphabeta、pruned、mcts")
+	rounds := flag.Int("rounds", 500, "mcts 每步模拟的次数")
+	temperature := flag.Float64("temperature", 1.4, "mcts 的温度")
+	depth := flag.Int("depth", 2, "alphabeta、pruned 的搜索深度")
+	eval := flag.String("eval", "influence", "alphabeta、pruned 的评估函数：capture、influence")
+	rulesName := flag.String("rules", "Chinese", "规则：Chinese、Japanese、AGA、Tromp-Taylor、New Zealand")
+	flag.Parse()
+
+	rand.Seed(time.Now().UnixNano())
+
+	evalFn := aigo.InfluenceEval
+	if *eval == "capture" {
+		evalFn = aigo.CaptureDiff
+	}
+	var bot aigo.IAgent
+	switch *botName {
+	case "random":
+		bot = aigo.RandomBot{EyeAnalysis: true}
+	case "fastrandom":
+		bot = aigo.NewFastRandomBot()
+	case "alphabeta":
+		bot = aigo.NewAlphaBetaAgent(*depth, evalFn)
+	case "pruned":
+		bot = aigo.NewDepthPrunedAgent(*depth, evalFn)
+	case "mcts":
+		bot = aigo.NewMCTSAgent(*rounds, *temperature)
+	default:
+		log.Fatalf("不认识的机器人 %q", *botName)
+	}
+
+	engine := gtp.NewEngine(bot, "aigo "+*botName, "0.1")
+	found := false
+	for _, rules := range []aigo.Ruleset{aigo.ChineseRules, aigo.JapaneseRules, aigo.AGARules, aigo.TrompTaylorRules, aigo.NewZealandRules} {
+		if rules.Name == *rulesName {
+			engine.Rules, found = rules, true
+		}
+	}
+	if !found {
+		log.Fatalf("不认识的规则 %q", *rulesName)
+	}
+	engine.Execute("clear_board")
+
+	if err := engine.Run(os.Stdin, os.Stdout); err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -0,0 +1,158 @@
+package aigo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 摆在棋盘上的棋子，不是轮流下出来的，例如让子、棋谱里的 AB/AW
+type Setup struct {
+	Black []Point // 摆上的黑子
+	White []Point // 摆上的白子
+	Empty []Point // 拿掉棋子的点，例如棋谱里的 AE
+}
+
+// 在当前局面上摆棋，返回新的 GameState，next 是摆完之后轮到谁下
+// 先拿掉 Empty 上的棋子，再摆上黑子、白子
+// 摆棋的局面也记录到哈希历史里，之后的劫争判断会把它算进去
+func (gs *GameState) ApplySetup(s Setup, next Player) (*GameState, error) {
+	board := gs.BoardPosition.Copy()
+	if len(s.Empty) > 0 {
+		var err error
+		if board, err = gs.BoardPosition.withoutStones(s.Empty); err != nil {
+			return gs, err
+		}
+	}
+	for _, list := range []struct {
+		color  Player
+		points []Point
+	}{{Black, s.Black}, {White, s.White}} {
+		for _, p := range list.points {
+			if err := board.PlaceStone(list.color, p); err != nil {
+				return gs, fmt.Errorf("摆棋 %v %v: %w", list.color, p, err)
+			}
+		}
+	}
+	ngs := NewGameState(board, next, gs, nil)
+	ngs.Setup = &s
+	return ngs, nil
+}
+
+// 拿掉 points 上的棋子以后的新棋盘，原来的棋盘不变
+// 拿掉一颗子可能把棋链分开，所以在空棋盘上重新摆一遍剩下的棋子
+func (b *Board) withoutStones(points []Point) (*Board, error) {
+	removed := make(map[int32]bool)
+	for _, p := range points {
+		if !b.IsOnGrid(p) {
+			return nil, fmt.Errorf("拿掉棋子 %v: %w", p, ErrOffBoard)
+		}
+		removed[b.index(p)] = true
+	}
+	nb := NewBoard(b.Width, b.Height)
+	for i, c := range b.grid {
+		if c != None && !removed[int32(i)] {
+			if err := nb.PlaceStone(c, b.point(int32(i))); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nb, nil
+}
+
+// 让子的处理者，自由放置让子时由玩家或机器人选择让子的位置
+type HandicapPlacer interface {
+	PlaceHandicap(gs *GameState, stones int) []Point
+}
+
+// 把普通函数当作 HandicapPlacer 使用，例如从命令行读入让子位置
+type HandicapPlacerFunc func(gs *GameState, stones int) []Point
+
+func (f HandicapPlacerFunc) PlaceHandicap(gs *GameState, stones int) []Point {
+	return f(gs, stones)
+}
+
+// 固定让子的星位
+// 按 GTP 协议 fixed_handicap 的顺序：先两个对角，再另两个角，然后边上的星位，单数时加上天元
+// 边长为奇数且不小于 9 的棋盘最多让 9 子，7x7 和偶数边长的棋盘最多让 4 子
+func HandicapPoints(size uint16, stones int) ([]Point, error) {
+	max_stones := 9
+	if size%2 == 0 || size < 9 {
+		max_stones = 4
+	}
+	if size < 7 || stones < 2 || stones > max_stones {
+		return nil, fmt.Errorf("%dx%d 的棋盘不能固定让 %d 子", size, size, stones)
+	}
+	low := uint16(4) // 星位离边的距离
+	if size < 13 {
+		low = 3
+	}
+	high := size + 1 - low
+	mid := (size + 1) / 2
+
+	corners := []Point{
+		{Row: low, Col: low}, {Row: high, Col: high}, {Row: high, Col: low}, {Row: low, Col: high},
+	}
+	center := Point{Row: mid, Col: mid}
+	sides := []Point{
+		{Row: mid, Col: low}, {Row: mid, Col: high}, {Row: low, Col: mid}, {Row: high, Col: mid},
+	}
+
+	points := []Point{}
+	if stones <= 4 {
+		return append(points, corners[:stones]...), nil
+	}
+	points = append(points, corners...)
+	switch stones {
+	case 5:
+		points = append(points, center)
+	case 6:
+		points = append(points, sides[:2]...)
+	case 7:
+		points = append(points, sides[:2]...)
+		points = append(points, center)
+	case 8:
+		points = append(points, sides...)
+	case 9:
+		points = append(points, sides...)
+		points = append(points, center)
+	}
+	return points, nil
+}
+
+// 固定让子的新对局，让子摆在星位上，白棋先下，贴目按规则调整
+func NewHandicapGame(size uint16, stones int, rules Ruleset) (*GameState, error) {
+	points, err := HandicapPoints(size, stones)
+	if err != nil {
+		return nil, err
+	}
+	return NewHandicapGameWithStones(size, size, points, rules)
+}
+
+// 自由放置让子的新对局，由 placer 选择让子的位置
+func NewFreeHandicapGame(w, h uint16, stones int, rules Ruleset, placer HandicapPlacer) (*GameState, error) {
+	if stones < 2 || stones >= int(w)*int(h) {
+		return nil, fmt.Errorf("%dx%d 的棋盘不能让 %d 子", w, h, stones)
+	}
+	game := NewGameWithRules(w, h, rules)
+	points := placer.PlaceHandicap(game, stones)
+	if len(points) != stones {
+		return nil, fmt.Errorf("需要 %d 个让子位置，实际给了 %d 个", stones, len(points))
+	}
+	return NewHandicapGameWithStones(w, h, points, rules)
+}
+
+// 在指定位置摆上让子的新对局
+// 对局历史的第一个状态是空棋盘，第二个状态摆好了让子，轮到白棋下
+func NewHandicapGameWithStones(w, h uint16, points []Point, rules Ruleset) (*GameState, error) {
+	if len(points) < 2 {
+		return nil, errors.New("让子棋至少要让 2 子")
+	}
+	rules.Komi = rules.HandicapKomi(len(points))
+	game := NewGameWithRules(w, h, rules)
+	game.Handicap = len(points)
+	setup, err := game.ApplySetup(Setup{Black: points}, White)
+	if err != nil {
+		return nil, err
+	}
+	return setup, nil
+}
@@ -0,0 +1,134 @@
+package aigo
+
+import (
+	"testing"
+)
+
+// 19 路棋盘的固定让子位置，与 GTP 协议一致
+func TestHandicapPoints19(t *testing.T) {
+	want := map[int]string{
+		2: "D4 Q16",
+		3: "D4 Q16 D16",
+		4: "D4 Q16 D16 Q4",
+		5: "D4 Q16 D16 Q4 K10",
+		6: "D4 Q16 D16 Q4 D10 Q10",
+		7: "D4 Q16 D16 Q4 D10 Q10 K10",
+		8: "D4 Q16 D16 Q4 D10 Q10 K4 K16",
+		9: "D4 Q16 D16 Q4 D10 Q10 K4 K16 K10",
+	}
+	for n, s := range want {
+		points, err := HandicapPoints(19, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		for i, p := range points {
+			if i > 0 {
+				got += " "
+			}
+			got += p.String()
+		}
+		if got != s {
+			t.Errorf("让%d子: %s，期望 %s", n, got, s)
+		}
+	}
+}
+
+func TestHandicapPointsSizes(t *testing.T) {
+	for _, size := range []uint16{9, 13, 19} {
+		for n := 2; n <= 9; n++ {
+			points, err := HandicapPoints(size, n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(points) != n {
+				t.Fatalf("%d路让%d子: 得到%d个位置", size, n, len(points))
+			}
+			seen := map[Point]bool{}
+			for _, p := range points {
+				if seen[p] || p.Row < 1 || p.Col < 1 || p.Row > size || p.Col > size {
+					t.Fatalf("%d路让%d子: 位置 %v 不对", size, n, points)
+				}
+				seen[p] = true
+			}
+		}
+	}
+	if points, _ := HandicapPoints(9, 2); points[0] != (Point{Row: 3, Col: 3}) {
+		t.Errorf("9路的星位应该在三线 %v", points)
+	}
+	if _, err := HandicapPoints(19, 10); err == nil {
+		t.Error("不能固定让 10 子")
+	}
+	if _, err := HandicapPoints(8, 5); err == nil {
+		t.Error("偶数路棋盘最多固定让 4 子")
+	}
+}
+
+func TestHandicapGame(t *testing.T) {
+	game, err := NewHandicapGame(19, 4, ChineseRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.PlayerTurn != White {
+		t.Error("让子棋白棋先下")
+	}
+	if game.Handicap != 4 || game.Rules.Komi != 4.5 {
+		t.Errorf("中国规则让4子，贴目 %.1f", game.Rules.Komi)
+	}
+	if game.Setup == nil || len(game.Setup.Black) != 4 || game.PreviousState == nil {
+		t.Fatal("让子应该记录在对局历史里")
+	}
+	if len(game.PreviousZobristHashStateArr) != 2 || game.PreviousZobristHashStateArr[1] != game.BoardPosition.GetZobristHash() {
+		t.Error("让子后的局面应该记录在哈希历史里")
+	}
+	if game.IsValidMove(NewPlay(Point{Row: 4, Col: 4})) {
+		t.Error("星位上已经有让子了")
+	}
+
+	for _, c := range []struct {
+		rules Ruleset
+		komi  float64
+	}{{AGARules, 3.5}, {JapaneseRules, 0.5}} {
+		g, err := NewHandicapGame(9, 4, c.rules)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.Rules.Komi != c.komi {
+			t.Errorf("%v 规则让4子，贴目 %.1f，期望 %.1f", c.rules, g.Rules.Komi, c.komi)
+		}
+	}
+
+	// 双方都跳过，数子时让子算黑棋的
+	game, _ = game.ApplyMove(NewPass())
+	game, _ = game.ApplyMove(NewPass())
+	if !game.IsOver() {
+		t.Fatal("双方跳过应该结束")
+	}
+	result := game.ComputeGameResult()
+	if result.B != 361 || result.W != 0 {
+		t.Errorf("结果 %v", result)
+	}
+}
+
+func TestFreeHandicapGame(t *testing.T) {
+	placer := HandicapPlacerFunc(func(gs *GameState, stones int) []Point {
+		points := []Point{}
+		for i := 1; i <= stones; i++ {
+			points = append(points, Point{Row: uint16(i), Col: uint16(i)})
+		}
+		return points
+	})
+	game, err := NewFreeHandicapGame(9, 9, 3, JapaneseRules, placer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.PlayerTurn != White || game.BoardPosition.Get(Point{Row: 3, Col: 3}) != Black {
+		t.Error("自由让子位置不对")
+	}
+	bad := HandicapPlacerFunc(func(gs *GameState, stones int) []Point {
+		return []Point{{Row: 1, Col: 1}, {Row: 1, Col: 1}}
+	})
+	if _, err := NewFreeHandicapGame(9, 9, 2, JapaneseRules, bad); err == nil {
+		t.Error("重复的让子位置应该报错")
+	}
+}
@@ -0,0 +1,163 @@
+package aigo
+
+// 势力估计，Bouzy 的 5/21 算法（借用数学形态学的膨胀、腐蚀）
+// EvaluateTerritory 只数完全被一方包围的空点，只能在终局用；
+// 这里对局中间也能估计每个交叉点归谁。
+// 黑子的值是 +128，白子是 -128，空点是 0，先膨胀 5 次再腐蚀 21 次，
+// 最后值为正的点归黑棋，为负的归白棋，为 0 的是中立点。
+// 不判断死活，被围住的死子也算在原来一方。
+
+// 势力估计的结果
+type InfluenceEstimate struct {
+	Influence map[Point]int    // 每个交叉点最后的值，正的偏向黑棋，负的偏向白棋
+	Ownership map[Point]Player // 每个交叉点归谁，中立点为 None
+	Result    *GameResult      // 按规则估计的得分，数子法数归属的所有点，数目法数归属的空点加提子
+}
+
+// 势力估计器
+type InfluenceEstimator struct {
+	Dilations int // 膨胀的次数
+	Erosions  int // 腐蚀的次数
+}
+
+// 构造 Bouzy 5/21 势力估计器：膨胀 5 次，腐蚀 21 次
+func NewInfluenceEstimator() *InfluenceEstimator {
+	return &InfluenceEstimator{Dilations: 5, Erosions: 21}
+}
+
+// 估计当前局面每个交叉点的归属和得分
+func (e *InfluenceEstimator) Estimate(gs *GameState) *InfluenceEstimate {
+	b := gs.BoardPosition
+	values := e.influence(b)
+	est := &InfluenceEstimate{
+		Influence: make(map[Point]int, len(values)),
+		Ownership: make(map[Point]Player, len(values)),
+	}
+	for i, v := range values {
+		p := b.point(int32(i))
+		est.Influence[p] = v
+		est.Ownership[p] = influenceOwner(v)
+	}
+	est.Result = gs.scoreTerritory(influenceTerritory(b, values), gs.Prisoners)
+	return est
+}
+
+// 值为正的归黑棋，为负的归白棋
+func influenceOwner(v int) Player {
+	if v > 0 {
+		return Black
+	}
+	if v < 0 {
+		return White
+	}
+	return None
+}
+
+// 按势力统计双方的棋子和地盘，棋子不管势力都算原来一方的
+func influenceTerritory(b *Board, values []int) *Territory {
+	territory := &Territory{}
+	for i, v := range values {
+		switch owner := influenceOwner(v); {
+		case b.grid[i] == Black:
+			territory.NumBlackStones++
+		case b.grid[i] == White:
+			territory.NumWhiteStones++
+		case owner == Black:
+			territory.NumBlackTerritory++
+		case owner == White:
+			territory.NumWhiteTerritory++
+		default:
+			territory.NumDame++
+			territory.DamePoints = append(territory.DamePoints, b.point(int32(i)))
+		}
+	}
+	return territory
+}
+
+// 按 Bouzy 算法计算每个交叉点的值，下标同 Board
+func (e *InfluenceEstimator) influence(b *Board) []int {
+	values := make([]int, len(b.grid))
+	for i, c := range b.grid {
+		switch c {
+		case Black:
+			values[i] = 128
+		case White:
+			values[i] = -128
+		}
+	}
+	next := make([]int, len(values))
+	for k := 0; k < e.Dilations; k++ {
+		for i, v := range values {
+			next[i] = v + b.dilation(values, int32(i))
+		}
+		values, next = next, values
+	}
+	for k := 0; k < e.Erosions; k++ {
+		for i, v := range values {
+			next[i] = v + b.erosion(values, int32(i))
+		}
+		values, next = next, values
+	}
+	return values
+}
+
+// 膨胀：没有对方势力的邻居时，每个有己方势力的邻居让值增加 1
+func (b *Board) dilation(values []int, i int32) int {
+	v := values[i]
+	pos, neg := 0, 0
+	for _, n := range b.adj[4*i : 4*i+4] {
+		if n < 0 {
+			continue
+		}
+		if values[n] > 0 {
+			pos++
+		} else if values[n] < 0 {
+			neg++
+		}
+	}
+	if v >= 0 && neg == 0 {
+		return pos
+	}
+	if v <= 0 && pos == 0 {
+		return -neg
+	}
+	return 0
+}
+
+// 腐蚀：每个不是己方势力的邻居让值减少 1，减到 0 为止；棋盘外不算
+func (b *Board) erosion(values []int, i int32) int {
+	v := values[i]
+	if v == 0 {
+		return 0
+	}
+	d := 0
+	for _, n := range b.adj[4*i : 4*i+4] {
+		if n < 0 {
+			continue
+		}
+		if v > 0 && values[n] <= 0 {
+			d--
+		} else if v < 0 && values[n] >= 0 {
+			d++
+		}
+	}
+	if v > 0 && v+d < 0 {
+		return -v
+	}
+	if v < 0 && v+d > 0 {
+		return -v
+	}
+	return d
+}
+
+// 用 Bouzy 5/21 势力估计作为棋局评估函数，可以给 NewAlphaBetaAgent、NewDepthPrunedAgent 使用
+// 返回估计的得分差（不含贴目），下一回合轮到谁，就是对谁的评估结果，同 CaptureDiff
+func InfluenceEval(gs *GameState) int {
+	b := gs.BoardPosition
+	r := gs.scoreTerritory(influenceTerritory(b, NewInfluenceEstimator().influence(b)), gs.Prisoners)
+	diff := r.B - r.W
+	if gs.PlayerTurn == Black {
+		return diff
+	}
+	return -1 * diff
+}
@@ -0,0 +1,84 @@
+package aigo
+
+import (
+	"testing"
+)
+
+func TestInfluenceEmptyBoard(t *testing.T) {
+	est := NewInfluenceEstimator().Estimate(NewGameOfSize(9, 9))
+	for p, owner := range est.Ownership {
+		if owner != None || est.Influence[p] != 0 {
+			t.Fatalf("空棋盘 %v 不应该有归属", p)
+		}
+	}
+	if est.Result.B != 0 || est.Result.W != 0 {
+		t.Errorf("空棋盘的得分: %+v", est.Result)
+	}
+}
+
+func TestInfluenceSingleStone(t *testing.T) {
+	game := NewGameOfSize(9, 9)
+	game.BoardPosition.PlaceStone(Black, Point{Row: 5, Col: 5})
+	est := NewInfluenceEstimator().Estimate(game)
+	// 5 次膨胀的势力正好被 21 次腐蚀抵消，孤零零的一颗子不形成地盘
+	if est.Ownership[Point{Row: 5, Col: 5}] != Black || est.Ownership[Point{Row: 5, Col: 6}] != None {
+		t.Errorf("一颗子没有地盘: %v %v", est.Ownership[Point{Row: 5, Col: 5}], est.Ownership[Point{Row: 5, Col: 6}])
+	}
+	// 势力是对称的
+	for _, s := range Symmetries(9, 9) {
+		for p, v := range est.Influence {
+			if est.Influence[s.Point(p, 9, 9)] != v {
+				t.Fatalf("%v 变换以后 %v 的势力不一样", s, p)
+			}
+		}
+	}
+	if est.Result.W != 0 || est.Result.B != 1 {
+		t.Errorf("只有一颗黑子: %+v", est.Result)
+	}
+}
+
+func TestInfluenceMidGame(t *testing.T) {
+	game := gameFromDiagram(t, ChineseRules, Black,
+		".......",
+		"..X.O..",
+		"..X.O..",
+		"..X.O..",
+		"..X.O..",
+		"..X.O..",
+		".......",
+	)
+	est := NewInfluenceEstimator().Estimate(game)
+	if est.Ownership[Point{Row: 4, Col: 1}] != Black || est.Ownership[Point{Row: 4, Col: 7}] != White {
+		t.Errorf("两边应该各归一方: %v %v", est.Ownership[Point{Row: 4, Col: 1}], est.Ownership[Point{Row: 4, Col: 7}])
+	}
+	if est.Ownership[Point{Row: 4, Col: 4}] != None {
+		t.Errorf("中间是中立点: %v", est.Ownership[Point{Row: 4, Col: 4}])
+	}
+	if est.Result.B != est.Result.W {
+		t.Errorf("双方势力一样: %+v", est.Result)
+	}
+	// EvaluateTerritory 在对局中间数不出地盘
+	if territory := game.BoardPosition.EvaluateTerritory(); territory.NumBlackTerritory != 0 {
+		t.Errorf("EvaluateTerritory: %+v", territory)
+	}
+
+	// 黑棋多一块地，轮到谁就是对谁的评估
+	game.BoardPosition.PlaceStone(Black, Point{Row: 1, Col: 6})
+	game.BoardPosition.PlaceStone(Black, Point{Row: 2, Col: 6})
+	game.BoardPosition.PlaceStone(Black, Point{Row: 2, Col: 7})
+	black := InfluenceEval(game)
+	game.PlayerTurn = White
+	if white := InfluenceEval(game); black <= 0 || white != -black {
+		t.Errorf("评估结果: %d %d", black, white)
+	}
+}
+
+func TestInfluenceEvalAgents(t *testing.T) {
+	game := NewGameOfSize(5, 5)
+	for _, bot := range []IAgent{NewAlphaBetaAgent(1, InfluenceEval), NewDepthPrunedAgent(1, InfluenceEval)} {
+		move := bot.SelectMove(game)
+		if !game.IsValidMove(move) {
+			t.Errorf("%T 选了不合法的动作 %v", bot, move)
+		}
+	}
+}
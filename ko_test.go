@@ -0,0 +1,166 @@
+package aigo
+
+import (
+	"testing"
+)
+
+// 按棋盘图构造对局，图的第一行是最上面一行，X 黑 O 白 . 空
+func gameFromDiagram(t *testing.T, rules Ruleset, next Player, rows ...string) *GameState {
+	h, w := uint16(len(rows)), uint16(len(rows[0]))
+	game := NewGameWithRules(w, h, rules)
+	for i, line := range rows {
+		for j, c := range line {
+			p := Point{Row: h - uint16(i), Col: uint16(j) + 1}
+			var err error
+			switch c {
+			case 'X':
+				err = game.BoardPosition.PlaceStone(Black, p)
+			case 'O':
+				err = game.BoardPosition.PlaceStone(White, p)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	game.PlayerTurn = next
+	game.PreviousZobristHashStateArr = []int64{game.BoardPosition.GetZobristHash()}
+	game.PreviousSituationHashArr = []int64{situationHash(game.BoardPosition.GetZobristHash(), next)}
+	return game
+}
+
+// 依次落子，只有最后一步可能不合法，返回最后一步是否合法
+func lastMoveLegal(t *testing.T, game *GameState, moves []Move) bool {
+	for i, m := range moves {
+		if !game.IsValidMove(m) {
+			if i != len(moves)-1 {
+				t.Fatalf("%v: 第%d步 %v 不应该违规", game.Rules, i+1, m)
+			}
+			return false
+		}
+		var err error
+		game, err = game.ApplyMove(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return true
+}
+
+func play(row, col uint16) Move {
+	return NewPlay(Point{Row: row, Col: col})
+}
+
+// 三劫循环：六手之后回到同样的局面
+// 普通劫规则下每一步都合法；同形规则下第六手重现了开始的局面
+func TestTripleKo(t *testing.T) {
+	moves := []Move{play(2, 3), play(2, 7), play(2, 13), play(2, 2), play(2, 8), play(2, 12)}
+	cases := map[KoRule]bool{SimpleKo: true, PositionalSuperko: false, SituationalSuperko: false}
+	for ko, want := range cases {
+		rules := ChineseRules
+		rules.Ko = ko
+		game := gameFromDiagram(t, rules, Black,
+			".XO...XO...XO.",
+			"XO.O.X.XO.XO.O",
+			".XO...XO...XO.",
+		)
+		if got := lastMoveLegal(t, game, moves); got != want {
+			t.Errorf("%v: 第六手合法性 %v，期望 %v", ko, got, want)
+		}
+	}
+}
+
+// 双劫加一次跳过：五手之后回到同样的棋盘，但轮到另一方下
+// 全局同形禁止，情境同形和普通劫允许
+func TestSuperkoAfterPass(t *testing.T) {
+	moves := []Move{play(2, 3), play(2, 7), NewPass(), play(2, 2), play(2, 8)}
+	cases := map[KoRule]bool{SimpleKo: true, PositionalSuperko: false, SituationalSuperko: true}
+	for ko, want := range cases {
+		rules := ChineseRules
+		rules.Ko = ko
+		game := gameFromDiagram(t, rules, Black,
+			".XO...XO.",
+			"XO.O.X.XO",
+			".XO...XO.",
+		)
+		if got := lastMoveLegal(t, game, moves); got != want {
+			t.Errorf("%v: 第五手合法性 %v，期望 %v", ko, got, want)
+		}
+	}
+}
+
+// 送二还一：黑棋送两子，白棋提掉，黑棋再提回一子，棋盘回到开始的样子，但轮到白棋下
+func TestSendingTwoReturningOne(t *testing.T) {
+	moves := []Move{play(1, 3), play(1, 1), play(1, 2)}
+	cases := map[KoRule]bool{SimpleKo: true, PositionalSuperko: false, SituationalSuperko: true}
+	for ko, want := range cases {
+		rules := ChineseRules
+		rules.Ko = ko
+		game := gameFromDiagram(t, rules, Black,
+			".....",
+			".....",
+			".....",
+			"XOO..",
+			".X.O.",
+		)
+		if got := lastMoveLegal(t, game, moves); got != want {
+			t.Errorf("%v: 还一的合法性 %v，期望 %v", ko, got, want)
+		}
+	}
+}
+
+// 长生：黑提两子、白提两子、黑扑、白粘，四手一个循环，轮到同一方下
+// 两种同形规则都禁止第四手，普通劫规则下可以一直循环
+func TestEternalLife(t *testing.T) {
+	moves := []Move{play(1, 2), play(1, 3), play(1, 1), play(1, 4)}
+	cases := map[KoRule]bool{SimpleKo: true, PositionalSuperko: false, SituationalSuperko: false}
+	for ko, want := range cases {
+		rules := ChineseRules
+		rules.Ko = ko
+		game := gameFromDiagram(t, rules, Black,
+			"X...",
+			"OOXX",
+			"X.OO",
+		)
+		if got := lastMoveLegal(t, game, moves); got != want {
+			t.Errorf("%v: 第四手合法性 %v，期望 %v", ko, got, want)
+		}
+	}
+	// 普通劫规则下循环两轮，局面完全重复
+	game := gameFromDiagram(t, JapaneseRules, Black, "X...", "OOXX", "X.OO")
+	start := game.ZobristHash()
+	if !lastMoveLegal(t, game, append(moves, moves...)) {
+		t.Fatal("普通劫规则下应该可以一直循环")
+	}
+	for _, m := range moves {
+		game, _ = game.ApplyMove(m)
+	}
+	if game.ZobristHash() != start {
+		t.Error("一轮循环之后应该回到同样的局面")
+	}
+}
+
+// 原地落子和撤销也要维护情境同形的历史
+func TestSituationalSuperkoPlayUndo(t *testing.T) {
+	rules := ChineseRules
+	rules.Ko = SituationalSuperko
+	game := gameFromDiagram(t, rules, Black, "X...", "OOXX", "X.OO")
+	moves := []Move{play(1, 2), play(1, 3), play(1, 1)}
+	undos := []*UndoRecord{}
+	for _, m := range moves {
+		u, err := game.Play(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		undos = append(undos, u)
+	}
+	if game.IsValidMove(play(1, 4)) {
+		t.Error("原地落子后第四手应该违反情境同形")
+	}
+	for i := len(undos) - 1; i >= 0; i-- {
+		game.Undo(undos[i])
+	}
+	if len(game.PreviousSituationHashArr) != 1 {
+		t.Errorf("撤销后历史长度 %d", len(game.PreviousSituationHashArr))
+	}
+}
@@ -0,0 +1,188 @@
+package aigo
+
+import (
+	"errors"
+)
+
+// 征子读棋
+// 被征的棋链只有一口气时由它先走（长出或者提子），有两口气时由征子的一方先打吃。
+// 读棋直接在棋盘上 Play / Undo，读完以后棋盘恢复原样，不复制棋盘，
+// 所以读棋期间不能有别的地方同时使用这个棋盘。
+// 不考虑劫争。
+
+// 征子读棋的结果
+type LadderResult struct {
+	Captured bool    // 征子成立，棋链最终会被提走
+	Moves    []Point // 读出来的主要变化，双方交替落子
+	Breaker  *Point  // 征子不成立时，逃跑途中连上的己方棋子或者提掉的对方棋子，只是长出气来时为 nil
+}
+
+// 读征子用到的状态
+type ladderReader struct {
+	b         *Board
+	color     Player // 被征子的一方
+	max_depth int    // 最多读的步数，超过了就认为逃掉了
+}
+
+// 读 p 处棋链的征子，棋链必须只有一口或者两口气
+func (b *Board) ReadLadder(p Point) (*LadderResult, error) {
+	if !b.IsOnGrid(p) {
+		return nil, ErrOffBoard
+	}
+	i := b.index(p)
+	if b.grid[i] == None {
+		return nil, errors.New("there is no stone at the given point")
+	}
+	r := &ladderReader{b: b, color: b.grid[i], max_depth: len(b.grid)}
+	switch b.libs[b.head[i]] {
+	case 1:
+		return r.defend(i, 0), nil
+	case 2:
+		return r.attack(i, 0), nil
+	}
+	return nil, errors.New("the chain has more than two liberties")
+}
+
+// p 处的棋链是否会被征吃，不能读征子的棋链返回 false
+// 给随机走子策略、着法排序之类的地方用
+func (b *Board) IsLadderCaptured(p Point) bool {
+	r, err := b.ReadLadder(p)
+	return err == nil && r.Captured
+}
+
+// 逃跑的一方走，棋链 s 只有一口气
+// 有一种走法能逃掉就算逃掉，都逃不掉时返回抵抗最久的变化
+func (r *ladderReader) defend(s int32, depth int) *LadderResult {
+	b := r.b
+	if depth >= r.max_depth {
+		return &LadderResult{}
+	}
+	h := b.head[s]
+	var best *LadderResult
+	for _, m := range r.defenses(h) {
+		p := b.point(m)
+		if b.isSelfCapture(r.color, p) {
+			continue
+		}
+		breaker := r.breakerAt(h, m)
+		c, _ := b.Play(r.color, p)
+		var line *LadderResult
+		switch libs := b.libs[b.head[s]]; {
+		case libs >= 3:
+			line = &LadderResult{}
+		case libs == 2:
+			line = r.attack(s, depth+1)
+		default: // 自己长进了只有一口气的地方
+			line = &LadderResult{Captured: true}
+		}
+		b.Undo(c)
+
+		line.Moves = append([]Point{p}, line.Moves...)
+		if !line.Captured {
+			if line.Breaker == nil {
+				line.Breaker = breaker
+			}
+			return line
+		}
+		if best == nil || len(line.Moves) > len(best.Moves) {
+			best = line
+		}
+	}
+	if best == nil { // 没有可以走的地方
+		best = &LadderResult{Captured: true}
+	}
+	return best
+}
+
+// 征子的一方走，棋链 s 有两口气，依次试着从两边打吃
+func (r *ladderReader) attack(s int32, depth int) *LadderResult {
+	b := r.b
+	if depth >= r.max_depth {
+		return &LadderResult{}
+	}
+	attacker := r.color.Other()
+	var best *LadderResult
+	for _, m := range r.liberties(b.head[s]) {
+		p := b.point(m)
+		if b.isSelfCapture(attacker, p) {
+			continue
+		}
+		c, _ := b.Play(attacker, p)
+		var line *LadderResult
+		if b.libs[b.head[s]] == 1 {
+			line = r.defend(s, depth+1)
+		} else { // 打吃的时候提掉了逃跑方的棋子，反而长了气
+			line = &LadderResult{}
+		}
+		b.Undo(c)
+
+		line.Moves = append([]Point{p}, line.Moves...)
+		if line.Captured {
+			return line
+		}
+		if best == nil || len(line.Moves) > len(best.Moves) {
+			best = line
+		}
+	}
+	if best == nil {
+		best = &LadderResult{}
+	}
+	return best
+}
+
+// 逃跑方可以走的地方：棋链的气，以及能提掉相邻的只有一口气的对方棋链的点
+func (r *ladderReader) defenses(h int32) []int32 {
+	b := r.b
+	moves := r.liberties(h)
+	s := h
+	for {
+		for _, n := range b.adj[4*s : 4*s+4] {
+			if n >= 0 && b.grid[n] == r.color.Other() && b.libs[b.head[n]] == 1 {
+				for _, e := range r.liberties(b.head[n]) {
+					moves = appendUniqueInt32(moves, e)
+				}
+			}
+		}
+		s = b.next[s]
+		if s == h {
+			break
+		}
+	}
+	return moves
+}
+
+// 链头为 h 的棋链的所有气
+func (r *ladderReader) liberties(h int32) []int32 {
+	b := r.b
+	libs := make([]int32, 0, b.libs[h])
+	gen := b.nextMark()
+	s := h
+	for {
+		for _, n := range b.adj[4*s : 4*s+4] {
+			if n >= 0 && b.grid[n] == None && b.mark[n] != gen {
+				b.mark[n] = gen
+				libs = append(libs, n)
+			}
+		}
+		s = b.next[s]
+		if s == h {
+			break
+		}
+	}
+	return libs
+}
+
+// 逃跑方在 m 落子时会连上的己方其他棋子，或者会提掉的对方棋子，没有时返回 nil
+func (r *ladderReader) breakerAt(h, m int32) *Point {
+	b := r.b
+	for _, n := range b.adj[4*m : 4*m+4] {
+		if n < 0 {
+			continue
+		}
+		if (b.grid[n] == r.color && b.head[n] != h) || (b.grid[n] == r.color.Other() && b.libs[b.head[n]] == 1) {
+			p := b.point(n)
+			return &p
+		}
+	}
+	return nil
+}
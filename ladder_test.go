@@ -0,0 +1,90 @@
+package aigo
+
+import (
+	"testing"
+)
+
+// 中间一颗黑子，白棋从两边都可以征，breakers 分别是第 8 行和第 2 行
+func ladderBoard(t *testing.T, row8, row2 string) *Board {
+	return gameFromDiagram(t, ChineseRules, Black,
+		".........",
+		row8,
+		".........",
+		"....O....",
+		"...OX....",
+		".....O...",
+		".........",
+		row2,
+		".........",
+	).BoardPosition
+}
+
+func TestLadderCaptured(t *testing.T) {
+	b := ladderBoard(t, ".........", ".........")
+	before, hash := b.Copy(), b.GetZobristHash128()
+	r, err := b.ReadLadder(Point{Row: 5, Col: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Captured || r.Breaker != nil {
+		t.Errorf("空棋盘上征子应该成立: %+v", r)
+	}
+	if len(r.Moves) < 10 || r.Moves[0] != (Point{Row: 5, Col: 6}) {
+		t.Errorf("征子的变化不对: %v", r.Moves)
+	}
+	if !b.Equal(before) || b.GetZobristHash128() != hash {
+		t.Error("读完征子棋盘应该恢复原样")
+	}
+	if !b.IsLadderCaptured(Point{Row: 5, Col: 5}) {
+		t.Error("IsLadderCaptured 应该返回 true")
+	}
+}
+
+func TestLadderBreaker(t *testing.T) {
+	// 只有一边有引征，白棋往另一边征
+	b := ladderBoard(t, ".........", "..X......")
+	if r, _ := b.ReadLadder(Point{Row: 5, Col: 5}); !r.Captured || r.Moves[0] != (Point{Row: 4, Col: 5}) {
+		t.Errorf("应该往右上征: %+v", r)
+	}
+
+	// 两边都有引征，征子不成立
+	b = ladderBoard(t, "......X..", "..X......")
+	r, _ := b.ReadLadder(Point{Row: 5, Col: 5})
+	if r.Captured || r.Breaker == nil || b.Get(*r.Breaker) != Black {
+		t.Errorf("征子应该不成立: %+v", r)
+	}
+	if b.IsLadderCaptured(Point{Row: 5, Col: 5}) {
+		t.Error("IsLadderCaptured 应该返回 false")
+	}
+}
+
+func TestLadderEscapeByCapture(t *testing.T) {
+	// 黑棋被打吃，但是可以提掉旁边只有一口气的白子
+	b := gameFromDiagram(t, ChineseRules, Black,
+		".....",
+		".....",
+		".OOX.",
+		".XXO.",
+		".OOX.",
+	).BoardPosition
+	r, err := b.ReadLadder(Point{Row: 2, Col: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Captured || r.Breaker == nil || b.Get(*r.Breaker) != White {
+		t.Errorf("提子以后应该逃掉: %+v", r)
+	}
+}
+
+func TestLadderErrors(t *testing.T) {
+	b := ladderBoard(t, ".........", ".........")
+	if _, err := b.ReadLadder(Point{Row: 1, Col: 1}); err == nil {
+		t.Error("空点不能读征子")
+	}
+	if _, err := b.ReadLadder(Point{Row: 6, Col: 5}); err == nil {
+		t.Error("三口气以上的棋链不能读征子")
+	}
+	if _, err := b.ReadLadder(Point{Row: 10, Col: 1}); err == nil {
+		t.Error("棋盘外不能读征子")
+	}
+}
@@ -5,46 +5,83 @@ import (
 	"math/rand"
 )
 
+// 搜索树的节点
+// 节点很多，棋盘用不可变的 PersistentBoard 保存，和父节点共用没有变化的部分；
+// 除了根节点，game_state 的 BoardPosition 为 nil，需要完整的局面时用 state 生成
 type MCTSNode struct {
 	game_state      *GameState
+	board           *PersistentBoard
 	parent          *MCTSNode
 	move            *Move
-	win_count       map[Player]int
+	win_count       map[Player]float64 // 各方赢的次数，和棋双方各算半次
 	num_rollouts    int
 	children        []*MCTSNode
 	unvisited_moves []Move
 }
 
 func NewMCTSNode(gs *GameState, parent *MCTSNode, move *Move) *MCTSNode {
+	return newMCTSNode(gs, gs.BoardPosition.Persistent(), parent, move)
+}
+
+// board 是 gs 的棋盘对应的不可变棋盘
+// 有父节点时不保存 gs 的棋盘，gs 的上一个局面换成父节点的局面
+func newMCTSNode(gs *GameState, board *PersistentBoard, parent *MCTSNode, move *Move) *MCTSNode {
 	node := &MCTSNode{}
 	node.game_state = gs
+	node.board = board
 	node.parent = parent
 	node.move = move
-	node.win_count = make(map[Player]int)
+	node.win_count = make(map[Player]float64)
 	node.win_count[Black] = 0
 	node.win_count[White] = 0
 	node.num_rollouts = 0
 	node.children = make([]*MCTSNode, 0)
 	node.unvisited_moves = gs.LegalMoves()
+	if parent != nil {
+		state := *gs
+		state.BoardPosition = nil
+		state.PreviousState = parent.game_state
+		node.game_state = &state
+	}
 	return node
 }
 
+// 节点的完整局面，不是根节点时从不可变棋盘重新生成一个 Board
+func (node *MCTSNode) state() *GameState {
+	if node.game_state.BoardPosition != nil {
+		return node.game_state
+	}
+	gs := *node.game_state
+	gs.BoardPosition = node.board.Board()
+	return &gs
+}
+
 // 向树中添加新的子节点
 func (node *MCTSNode) add_random_child() *MCTSNode {
 	index := rand.Intn(len(node.unvisited_moves))
 	new_move := node.unvisited_moves[index]
-	new_game_state, err1 := node.game_state.ApplyMove(new_move)
+	gs := node.state()
+	new_game_state, err1 := gs.ApplyMove(new_move)
 	if err1 != nil {
 		log.Fatal(err1)
 	}
-	new_node := NewMCTSNode(new_game_state, node, &new_move)
+	new_board := node.board
+	if new_move.IsPlay {
+		new_board, _ = node.board.Play(gs.PlayerTurn, new_move.Pnt)
+	}
+	new_node := newMCTSNode(new_game_state, new_board, node, &new_move)
 	node.children = append(node.children, new_node)
 	return new_node
 }
 
-// 更新推演统计信息
+// 更新推演统计信息，winner 为 None 表示和棋，双方各算赢了半次
 func (node *MCTSNode) record_win(winner Player) {
-	node.win_count[winner]++
+	if winner == None {
+		node.win_count[Black] += 0.5
+		node.win_count[White] += 0.5
+	} else {
+		node.win_count[winner]++
+	}
 	node.num_rollouts++
 }
 
@@ -60,5 +97,5 @@ func (node *MCTSNode) is_terminal() bool {
 
 // 返回某一方在推演中获胜的比率。
 func (node *MCTSNode) winning_frac(player Player) float64 {
-	return float64(node.win_count[player]) / float64(node.num_rollouts)
+	return node.win_count[player] / float64(node.num_rollouts)
 }
@@ -0,0 +1,95 @@
+package aigo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 动作不合法的原因，可以用 errors.Is 判断
+var (
+	ErrOffBoard    = errors.New("given point is not within the board")
+	ErrOccupied    = errors.New("given point on the board is already occupied")
+	ErrSuicide     = errors.New("move is suicide")
+	ErrKo          = errors.New("move violates the ko rule")
+	ErrGameOver    = errors.New("game is already over")
+	ErrWrongTurn   = errors.New("it is not this player's turn")
+	ErrInvalidMove = errors.New("move is neither a play, a pass nor a resign")
+)
+
+// 违反劫争规则的详细信息，errors.Is(err, ErrKo) 为 true
+type KoError struct {
+	Rule         KoRule // 违反的劫争规则
+	Move         Move   // 不合法的动作
+	RepeatedMove int    // 重复的是第几步之后的局面，0 表示开局的局面；历史里找不到时为 -1
+}
+
+func (e *KoError) Error() string {
+	return fmt.Sprintf("move %v violates %v, repeating the position after move %d", e.Move.StringChessRecord(), e.Rule, e.RepeatedMove)
+}
+
+func (e *KoError) Unwrap() error {
+	return ErrKo
+}
+
+// 检查当前轮到的一方能不能下这个动作，合法时返回 nil
+// 不合法时返回上面的错误之一，劫争返回 *KoError
+func (gs *GameState) CheckMove(move Move) error {
+	if gs.IsOver() {
+		return ErrGameOver
+	}
+	if move.IsPass || move.IsResign { // 认输或者跳过
+		return nil
+	}
+	if !move.IsPlay {
+		return ErrInvalidMove
+	}
+	if !gs.BoardPosition.IsOnGrid(move.Pnt) { // 不在棋盘上
+		return ErrOffBoard
+	}
+	if gs.BoardPosition.Get(move.Pnt) != None { // 这个位置已经有棋子了
+		return ErrOccupied
+	}
+	if gs.IsMoveSelfCapture(gs.PlayerTurn, move) && !gs.isSuicideAllowed(gs.PlayerTurn, move) { // 出现了自吃， 填自己气的情况
+		return ErrSuicide
+	}
+	if gs.DoesMoveViolateKo(gs.PlayerTurn, move) { // 出现了劫争
+		return &KoError{Rule: gs.Rules.Ko, Move: move, RepeatedMove: gs.repeatedMove(move)}
+	}
+	return nil
+}
+
+// 检查 player 能不能下这个动作，不是轮到他时返回 ErrWrongTurn
+// GTP 之类指定了颜色的落子使用
+func (gs *GameState) CheckMoveBy(player Player, move Move) error {
+	if player != gs.PlayerTurn {
+		return ErrWrongTurn
+	}
+	return gs.CheckMove(move)
+}
+
+// 违反劫争规则的动作重复的是第几步之后的局面
+// 沿着 PreviousState 往回找，需要历史是 ApplyMove 得到的，原地 Play 的历史共用棋盘，找不到时返回 -1
+// MCTS 搜索树里的历史局面不保存棋盘，跳过
+func (gs *GameState) repeatedMove(move Move) int {
+	c, err := gs.BoardPosition.Play(gs.PlayerTurn, move.Pnt)
+	if err != nil {
+		return -1
+	}
+	hash := gs.BoardPosition.hash
+	gs.BoardPosition.Undo(c)
+
+	history := []*GameState{}
+	for e := gs; e != nil; e = e.PreviousState {
+		history = append(history, e)
+	}
+	for k, e := range history {
+		if e.BoardPosition == nil || e.BoardPosition.hash != hash {
+			continue
+		}
+		if gs.Rules.Ko == SituationalSuperko && e.PlayerTurn != gs.PlayerTurn.Other() {
+			continue
+		}
+		return len(history) - 1 - k
+	}
+	return -1
+}
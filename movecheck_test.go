@@ -0,0 +1,88 @@
+package aigo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckMove(t *testing.T) {
+	game := gameFromDiagram(t, ChineseRules, White,
+		"X.X..",
+		".X...",
+		".....",
+		".....",
+		".....",
+	)
+	cases := []struct {
+		move Move
+		want error
+	}{
+		{NewPlay(Point{Row: 6, Col: 1}), ErrOffBoard},
+		{NewPlay(Point{Row: 5, Col: 1}), ErrOccupied},
+		{NewPlay(Point{Row: 5, Col: 2}), ErrSuicide},
+		{Move{}, ErrInvalidMove},
+		{NewPlay(Point{Row: 3, Col: 3}), nil},
+		{NewPass(), nil},
+		{NewResign(), nil},
+	}
+	for _, c := range cases {
+		if err := game.CheckMove(c.move); !errors.Is(err, c.want) {
+			t.Errorf("%v: 期望 %v，实际 %v", c.move, c.want, err)
+		}
+		if game.IsValidMove(c.move) != (c.want == nil) {
+			t.Errorf("%v: IsValidMove 和 CheckMove 不一致", c.move)
+		}
+	}
+
+	if err := game.CheckMoveBy(Black, NewPlay(Point{Row: 3, Col: 3})); err != ErrWrongTurn {
+		t.Errorf("轮到白棋下: %v", err)
+	}
+	if err := game.CheckMoveBy(White, NewPlay(Point{Row: 5, Col: 1})); err != ErrOccupied {
+		t.Errorf("轮到白棋，但是有子: %v", err)
+	}
+
+	// ApplyMove 也做同样的检查
+	next, err := game.ApplyMove(NewPlay(Point{Row: 5, Col: 2}))
+	if err != ErrSuicide || next != game {
+		t.Errorf("ApplyMove 应该拒绝自杀: %v", err)
+	}
+	if err := game.BoardPosition.PlaceStone(Black, Point{Row: 5, Col: 1}); err != ErrOccupied || err.Error() != "given point on the board is already occupied" {
+		t.Errorf("PlaceStone 的错误不对: %v", err)
+	}
+
+	game, _ = game.ApplyMove(NewPass())
+	game, _ = game.ApplyMove(NewPass())
+	if err := game.CheckMove(NewPass()); err != ErrGameOver {
+		t.Errorf("两次跳过以后对局结束: %v", err)
+	}
+	if _, err := game.ApplyMove(NewPlay(Point{Row: 3, Col: 3})); err != ErrGameOver {
+		t.Errorf("对局结束以后不能再下: %v", err)
+	}
+}
+
+func TestCheckMoveKo(t *testing.T) {
+	for _, rules := range []Ruleset{JapaneseRules, ChineseRules, AGARules} {
+		game := gameFromDiagram(t, rules, Black,
+			".....",
+			".....",
+			".XO..",
+			"XO.O.",
+			".XO..",
+		)
+		game, err := game.ApplyMove(NewPlay(Point{Row: 2, Col: 3})) // 提劫
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = game.CheckMove(NewPlay(Point{Row: 2, Col: 2})) // 马上提回
+		var ko *KoError
+		if !errors.Is(err, ErrKo) || !errors.As(err, &ko) {
+			t.Fatalf("%v: 应该违反劫争规则: %v", rules, err)
+		}
+		if ko.Rule != rules.Ko || ko.RepeatedMove != 0 || ko.Move.Pnt != (Point{Row: 2, Col: 2}) {
+			t.Errorf("%v: 劫争的信息不对: %+v", rules, ko)
+		}
+		if ko.Error() == "" {
+			t.Error("错误信息是空的")
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package aigo
+
+// 一步动作的结果
+// 界面显示提子动画、按提子数计分、评估函数判断吃子，都需要知道这一步到底发生了什么
+type MoveResult struct {
+	Player       Player        // 下这一步的一方
+	Move         Move          // 这一步动作
+	Captured     []Point       // 被提走的对方棋子
+	SelfCaptured []Point       // 自杀时被提走的己方棋子，包括落下的这颗
+	Merged       []*StoneGroup // 落子连上的己方棋链，是落子之前的样子；没有连上时为空
+	Group        *StoneGroup   // 落子以后这颗棋子所在的棋链，自杀时为 nil
+	Atari        []*StoneGroup // 落子以后新被打吃、只剩一口气的棋链，包括自己送吃的棋链
+	Prisoners    [3]int        // 这一步以后双方的提子总数，同 GameState.Prisoners
+}
+
+// 执行动作，同时返回这一步的结果，规则同 ApplyMove
+func (gs *GameState) ApplyMoveResult(m Move) (*GameState, *MoveResult, error) {
+	var merged []*StoneGroup
+	if m.IsPlay && gs.BoardPosition.IsOnGrid(m.Pnt) {
+		merged = gs.BoardPosition.adjacentGroups(m.Pnt, gs.PlayerTurn)
+	}
+	ngs, change, err := gs.applyMove(m)
+	if err != nil {
+		return gs, nil, err
+	}
+	r := &MoveResult{
+		Player:    gs.PlayerTurn,
+		Move:      m,
+		Prisoners: ngs.Prisoners,
+	}
+	if change == nil {
+		return ngs, r, nil
+	}
+	r.Captured = change.Captured
+	r.SelfCaptured = change.SelfCaptured
+	r.Merged = merged
+
+	before, after := gs.BoardPosition, ngs.BoardPosition
+	if len(change.SelfCaptured) == 0 {
+		r.Group = after.GetStoneGroup(m.Pnt)
+		if r.Group.NumLiberties() == 1 {
+			r.Atari = append(r.Atari, r.Group)
+		}
+	}
+	// 相邻的对方棋链原来不止一口气，现在只剩一口气
+	for _, sg := range after.adjacentGroups(m.Pnt, gs.PlayerTurn.Other()) {
+		if sg.NumLiberties() == 1 && before.LibertyCount(sg.Stones[0]) > 1 {
+			r.Atari = append(r.Atari, sg)
+		}
+	}
+	return ngs, r, nil
+}
+
+// p 点周围 color 颜色的棋链，同一条棋链只返回一次
+func (b *Board) adjacentGroups(p Point, color Player) []*StoneGroup {
+	groups := []*StoneGroup{}
+	i := b.index(p)
+	var seen [4]int32
+	num_seen := 0
+	for _, n := range b.adj[4*i : 4*i+4] {
+		if n < 0 || b.grid[n] != color {
+			continue
+		}
+		h := b.head[n]
+		dup := false
+		for _, e := range seen[:num_seen] {
+			if e == h {
+				dup = true
+				break
+			}
+		}
+		if dup {
+			continue
+		}
+		seen[num_seen] = h
+		num_seen++
+		groups = append(groups, b.stoneGroupAt(h))
+	}
+	return groups
+}
@@ -0,0 +1,92 @@
+package aigo
+
+import (
+	"testing"
+)
+
+func TestMoveResultCapture(t *testing.T) {
+	game := gameFromDiagram(t, ChineseRules, Black,
+		".....",
+		"..X..",
+		".XO..",
+		"..X..",
+		".....",
+	)
+	game, r, err := game.ApplyMoveResult(NewPlay(Point{Row: 3, Col: 4}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Player != Black || len(r.Captured) != 1 || r.Captured[0] != (Point{Row: 3, Col: 3}) {
+		t.Errorf("应该提掉 (3,3) 的白子: %+v", r)
+	}
+	if r.Prisoners[Black] != 1 || game.Prisoners[Black] != 1 {
+		t.Errorf("黑棋应该有一个提子: %v", r.Prisoners)
+	}
+	if len(r.Merged) != 0 || len(r.Group.Stones) != 1 || len(r.Atari) != 0 {
+		t.Errorf("单独一颗子，没有连上棋链: %+v", r)
+	}
+}
+
+func TestMoveResultMergeAndAtari(t *testing.T) {
+	game := gameFromDiagram(t, ChineseRules, Black,
+		".....",
+		".....",
+		"XX.XX",
+		"OOOOX",
+		"XX.X.",
+	)
+	// 连上左右两块，同时打吃下面的白棋
+	_, r, err := game.ApplyMoveResult(NewPlay(Point{Row: 3, Col: 3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Merged) != 2 || len(r.Group.Stones) != 6 {
+		t.Errorf("应该连上两块棋: %+v", r)
+	}
+	if len(r.Atari) != 1 || r.Atari[0].Color != White || len(r.Atari[0].Stones) != 4 {
+		t.Errorf("应该打吃白棋: %v", r.Atari)
+	}
+
+	// 白棋自己送吃
+	game = gameFromDiagram(t, ChineseRules, White,
+		".....",
+		".....",
+		".....",
+		"X.X..",
+		".X...",
+	)
+	_, r, _ = game.ApplyMoveResult(NewPlay(Point{Row: 2, Col: 2}))
+	if len(r.Merged) != 0 || len(r.Atari) != 1 || r.Atari[0] != r.Group {
+		t.Errorf("白棋自己送吃: %+v", r)
+	}
+}
+
+func TestMoveResultPassAndSuicide(t *testing.T) {
+	game := NewGameWithRules(5, 5, AGARules)
+	_, r, err := game.ApplyMoveResult(NewPass())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Prisoners[White] != 1 || r.Captured != nil || r.Group != nil {
+		t.Errorf("AGA 规则跳过交一颗子: %+v", r)
+	}
+
+	game = gameFromDiagram(t, TrompTaylorRules, Black,
+		"X.O..",
+		"OO...",
+		".....",
+		".....",
+		".....",
+	)
+	_, r, err = game.ApplyMoveResult(NewPlay(Point{Row: 5, Col: 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.SelfCaptured) != 2 || r.Group != nil || r.Prisoners[White] != 2 || len(r.Merged) != 1 {
+		t.Errorf("自杀的结果不对: %+v", r)
+	}
+
+	if _, _, err := game.ApplyMoveResult(NewPlay(Point{Row: 5, Col: 1})); err == nil {
+		t.Error("有子的地方不能下")
+	}
+}
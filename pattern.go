@@ -0,0 +1,275 @@
+package aigo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 3x3 模式
+// 一个交叉点周围一圈 8 个点，从左边开始顺时针依次是：左、左上、上、右上、右、右下、下、左下，
+// 偶数位置正好是 Point.Neighbors() 的四个相邻点。
+// 每个点用 2 位表示：0 空，1 黑，2 白，3 棋盘外，第 k 个点在第 2k、2k+1 位，一共 16 位。
+// 中间的点不编码，模式一般用在空点上，判断在这里落子好不好。
+// 棋盘在每次棋子变化时增量更新周围 8 个点的模式，查询只要取数组。
+type Pattern3x3 uint16
+
+// 棋盘外的点在模式里的编码
+const patternOffBoard = 3
+
+// 周围一圈 8 个点，顺序同 Pattern3x3
+func (p Point) ring() [8]Point {
+	return [8]Point{
+		{p.Row, p.Col - 1},
+		{p.Row + 1, p.Col - 1},
+		{p.Row + 1, p.Col},
+		{p.Row + 1, p.Col + 1},
+		{p.Row, p.Col + 1},
+		{p.Row - 1, p.Col + 1},
+		{p.Row - 1, p.Col},
+		{p.Row - 1, p.Col - 1},
+	}
+}
+
+// 设置交叉点的颜色，同时更新周围 8 个点的 3x3 模式
+// 点 i 在邻居 n 的一圈里，正好在 n 在 i 的一圈里的对面
+func (b *Board) setColor(i int32, color Player) {
+	b.grid[i] = color
+	for k, n := range b.ring[8*i : 8*i+8] {
+		if n >= 0 {
+			shift := 2 * uint((k+4)%8)
+			b.pat[n] = b.pat[n]&^(3<<shift) | uint16(color)<<shift
+		}
+	}
+}
+
+// 交叉点 p 周围的 3x3 模式，p 必须在棋盘上
+func (b *Board) Pattern(p Point) Pattern3x3 {
+	return Pattern3x3(b.pat[b.index(p)])
+}
+
+// 不用增量结果，直接从棋盘上计算 p 周围的 3x3 模式
+func (b *Board) computePattern(p Point) Pattern3x3 {
+	var pt Pattern3x3
+	for k, e := range p.ring() {
+		c := Player(patternOffBoard)
+		if b.IsOnGrid(e) {
+			c = b.Get(e)
+		}
+		pt |= Pattern3x3(c) << (2 * k)
+	}
+	return pt
+}
+
+// 第 k 个点的内容，3 表示棋盘外
+func (pt Pattern3x3) At(k int) Player {
+	return Player(pt>>(2*k)) & 3
+}
+
+// 顺时针旋转 90 度，一圈上的点都往后移两位
+func (pt Pattern3x3) Rotate() Pattern3x3 {
+	return pt<<4 | pt>>12
+}
+
+// 左右翻转，第 k 个点换到 4-k 的位置
+func (pt Pattern3x3) Mirror() Pattern3x3 {
+	var r Pattern3x3
+	for k := 0; k < 8; k++ {
+		r |= Pattern3x3(pt.At(k)) << (2 * ((12 - k) % 8))
+	}
+	return r
+}
+
+// 黑白互换，空点和棋盘外不变
+func (pt Pattern3x3) SwapColors() Pattern3x3 {
+	var r Pattern3x3
+	for k := 0; k < 8; k++ {
+		c := pt.At(k)
+		if c == Black || c == White {
+			c = c.Other()
+		}
+		r |= Pattern3x3(c) << (2 * k)
+	}
+	return r
+}
+
+// 8 种对称变换的结果，前 4 个是依次旋转，后 4 个是翻转以后再依次旋转
+func (pt Pattern3x3) Symmetries() [8]Pattern3x3 {
+	var r [8]Pattern3x3
+	m := pt.Mirror()
+	for k := 0; k < 4; k++ {
+		r[k], r[k+4] = pt, m
+		pt, m = pt.Rotate(), m.Rotate()
+	}
+	return r
+}
+
+// 规范形式：8 种对称变换以及黑白互换以后最小的编码
+// 对称或者颜色相反的模式规范形式相同
+func (pt Pattern3x3) Canonical() Pattern3x3 {
+	min := pt
+	for _, q := range [2]Pattern3x3{pt, pt.SwapColors()} {
+		for _, e := range q.Symmetries() {
+			if e < min {
+				min = e
+			}
+		}
+	}
+	return min
+}
+
+// 画成三行，中间的点用 * 表示，X 黑 O 白 . 空 | 棋盘外
+func (pt Pattern3x3) String() string {
+	// 从左上角开始一行一行，对应的是一圈里的第几个点
+	order := [9]int{1, 2, 3, 0, -1, 4, 7, 6, 5}
+	s := strings.Builder{}
+	for i, k := range order {
+		if i > 0 && i%3 == 0 {
+			s.WriteByte('\n')
+		}
+		if k < 0 {
+			s.WriteByte('*')
+			continue
+		}
+		s.WriteByte(".XO|"[pt.At(k)])
+	}
+	return s.String()
+}
+
+// 解析文本写的模式，三行从上往下，每行 3 个字符，可以写成一个字符串用空格或者换行隔开
+// X 黑（在模式表里表示要下的一方），O 白（对方），. 空，| 棋盘外，? 任意（空、黑、白），
+// 中间的点写 * 或者 . 都可以。
+// 有 ? 时会展开成所有符合的模式
+func ParsePattern3x3(s string) ([]Pattern3x3, error) {
+	rows := strings.Fields(s)
+	if len(rows) != 3 || len(rows[0]) != 3 || len(rows[1]) != 3 || len(rows[2]) != 3 {
+		return nil, fmt.Errorf("pattern %q must be 3 rows of 3 characters", s)
+	}
+	if c := rows[1][1]; c != '*' && c != '.' {
+		return nil, fmt.Errorf("pattern %q must have an empty center", s)
+	}
+	order := [9]int{1, 2, 3, 0, -1, 4, 7, 6, 5}
+	patterns := []Pattern3x3{0}
+	for i, k := range order {
+		if k < 0 {
+			continue
+		}
+		var colors []Player
+		switch c := rows[i/3][i%3]; c {
+		case '.':
+			colors = []Player{None}
+		case 'X':
+			colors = []Player{Black}
+		case 'O':
+			colors = []Player{White}
+		case '|':
+			colors = []Player{patternOffBoard}
+		case '?':
+			colors = []Player{None, Black, White}
+		default:
+			return nil, fmt.Errorf("pattern %q has unknown character %q", s, c)
+		}
+		next := make([]Pattern3x3, 0, len(patterns)*len(colors))
+		for _, pt := range patterns {
+			for _, c := range colors {
+				next = append(next, pt|Pattern3x3(c)<<(2*k))
+			}
+		}
+		patterns = next
+	}
+	return patterns, nil
+}
+
+// 3x3 模式表，给每个模式一个权重，用于重型随机走子、着法先验概率等
+// 表里的模式是按要下的一方是黑棋写的，白棋下的时候先黑白互换再查
+// 加入模式时把 8 种对称变换都存进去，查询时只要取数组
+type PatternTable struct {
+	weights [1 << 16]float64
+	known   [1 << 16]bool
+}
+
+// 构造空的模式表
+func NewPatternTable() *PatternTable {
+	return &PatternTable{}
+}
+
+// 加入一个模式以及它的对称变换，已经有的会被覆盖
+func (t *PatternTable) Add(pt Pattern3x3, weight float64) {
+	for _, e := range pt.Symmetries() {
+		t.weights[e] = weight
+		t.known[e] = true
+	}
+}
+
+// 模式的权重，表里没有时返回 false
+func (t *PatternTable) Get(pt Pattern3x3) (float64, bool) {
+	return t.weights[pt], t.known[pt]
+}
+
+// 轮到 turn 下的时候，棋盘上 p 点的模式的权重
+func (t *PatternTable) Lookup(b *Board, p Point, turn Player) (float64, bool) {
+	pt := b.Pattern(p)
+	if turn == White {
+		pt = pt.SwapColors()
+	}
+	return t.Get(pt)
+}
+
+// 模式表里的模式个数，对称变换都算在内
+func (t *PatternTable) Len() int {
+	n := 0
+	for _, e := range t.known {
+		if e {
+			n++
+		}
+	}
+	return n
+}
+
+// 从文本读取模式表
+// 每行一个模式：三行模式用空格隔开，后面跟着权重，例如 "XO. .*. ??? 1.5"
+// 空行和 # 开头的注释行跳过
+func LoadPatternTable(r io.Reader) (*PatternTable, error) {
+	t := NewPatternTable()
+	scanner := bufio.NewScanner(r)
+	line_no := 0
+	for scanner.Scan() {
+		line_no++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: want 3 pattern rows and a weight", line_no)
+		}
+		weight, err := strconv.ParseFloat(fields[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line_no, err)
+		}
+		patterns, err := ParsePattern3x3(strings.Join(fields[:3], " "))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line_no, err)
+		}
+		for _, pt := range patterns {
+			t.Add(pt, weight)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// 从文件读取模式表
+func LoadPatternTableFile(path string) (*PatternTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadPatternTable(f)
+}
@@ -0,0 +1,114 @@
+package aigo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPatternIncremental(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, size := range [][2]uint16{{9, 9}, {7, 11}} {
+		b := NewBoard(size[0], size[1])
+		changes := []*BoardChange{}
+		turn := Black
+		for step := 0; step < 300; step++ {
+			if len(changes) > 0 && rnd.Intn(4) == 0 {
+				b.Undo(changes[len(changes)-1])
+				changes = changes[:len(changes)-1]
+			} else {
+				p := Point{Row: uint16(rnd.Intn(int(b.Height))) + 1, Col: uint16(rnd.Intn(int(b.Width))) + 1}
+				if b.Get(p) != None {
+					continue
+				}
+				c, _ := b.Play(turn, p)
+				changes = append(changes, c)
+				turn = turn.Other()
+			}
+			c := b.Copy()
+			for row := uint16(1); row <= b.Height; row++ {
+				for col := uint16(1); col <= b.Width; col++ {
+					p := Point{Row: row, Col: col}
+					if b.Pattern(p) != b.computePattern(p) || c.Pattern(p) != b.Pattern(p) {
+						t.Fatalf("第%d步 %v 的模式不对:\n%v\n应该是:\n%v", step, p, b.Pattern(p), b.computePattern(p))
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPatternEdge(t *testing.T) {
+	b := NewBoard(9, 9)
+	b.PlaceStone(Black, Point{Row: 2, Col: 1})
+	b.PlaceStone(White, Point{Row: 1, Col: 2})
+	if s := b.Pattern(Point{Row: 1, Col: 1}).String(); s != "|X.\n|*O\n|||" {
+		t.Errorf("角上的模式不对:\n%s", s)
+	}
+}
+
+func TestPatternSymmetries(t *testing.T) {
+	pts, err := ParsePattern3x3("XO. .*| ..|")
+	if err != nil || len(pts) != 1 {
+		t.Fatal(pts, err)
+	}
+	pt := pts[0]
+	if pt.String() != "XO.\n.*|\n..|" {
+		t.Errorf("解析以后画出来不一样:\n%v", pt)
+	}
+	if pt.Rotate().Rotate().Rotate().Rotate() != pt || pt.Mirror().Mirror() != pt || pt.SwapColors().SwapColors() != pt {
+		t.Error("变换应该能还原")
+	}
+	if pt.Rotate().String() != "..X\n.*O\n||." {
+		t.Errorf("旋转不对:\n%v", pt.Rotate())
+	}
+	if pt.Mirror().String() != ".OX\n|*.\n|.." {
+		t.Errorf("翻转不对:\n%v", pt.Mirror())
+	}
+	seen := map[Pattern3x3]bool{}
+	for _, e := range pt.Symmetries() {
+		seen[e] = true
+		if e.Canonical() != pt.Canonical() || e.SwapColors().Canonical() != pt.Canonical() {
+			t.Errorf("对称的模式规范形式应该相同:\n%v", e)
+		}
+	}
+	if len(seen) != 8 {
+		t.Errorf("应该有 8 种不同的对称变换: %d", len(seen))
+	}
+
+	if pts, _ := ParsePattern3x3("??? .*. ..."); len(pts) != 27 {
+		t.Errorf("三个 ? 应该展开成 27 个模式: %d", len(pts))
+	}
+	for _, s := range []string{"XO. .X. ...", "XO .*. ...", "XOA .*. ..."} {
+		if _, err := ParsePattern3x3(s); err == nil {
+			t.Errorf("%q 应该解析失败", s)
+		}
+	}
+}
+
+func TestPatternTable(t *testing.T) {
+	table, err := LoadPatternTableFile("testdata/patterns3x3.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Len() == 0 {
+		t.Fatal("模式表是空的")
+	}
+
+	// 黑棋 (5,4) 旁边，在 (4,5) 可以把白棋 (5,5) 和 (4,4) 切开
+	b := NewBoard(9, 9)
+	b.PlaceStone(White, Point{Row: 5, Col: 5})
+	b.PlaceStone(White, Point{Row: 4, Col: 4})
+	b.PlaceStone(Black, Point{Row: 5, Col: 4})
+	if w, ok := table.Lookup(b, Point{Row: 4, Col: 5}, Black); !ok || w != 2.0 {
+		t.Errorf("黑棋在 (4,5) 切断的权重应该是 2: %v %v", w, ok)
+	}
+	if w, ok := table.Lookup(b, Point{Row: 4, Col: 5}, White); ok && w == 2.0 {
+		t.Error("白棋在 (4,5) 不是切断")
+	}
+	if w, ok := table.Lookup(b, Point{Row: 1, Col: 5}, Black); !ok || w != 0.2 {
+		t.Errorf("一路空点的权重应该是 0.2: %v %v", w, ok)
+	}
+	if _, ok := table.Lookup(b, Point{Row: 7, Col: 7}, Black); ok {
+		t.Error("中间的空点不在表里")
+	}
+}
@@ -2,7 +2,6 @@ package aigo
 
 import (
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 )
@@ -24,12 +23,14 @@ func (p Point) Neighbors() [4]Point {
 }
 
 // 把手工输入的 B17 这样的转换成 2,17
-// 人机对弈场景使用
+// 人机对弈场景使用，列号不是 COLS 里的字母、行号不是正整数时返回 nil
 func PointFromCoords(coords string) *Point {
-	col := uint16(strings.Index(COLS, string(coords[0])) + 1)
+	if len(coords) < 2 || strings.IndexByte(COLS, coords[0]) < 0 {
+		return nil
+	}
+	col := uint16(strings.IndexByte(COLS, coords[0]) + 1)
 	row, e := strconv.Atoi(coords[1:])
-	if e != nil {
-		log.Panicln(e)
+	if e != nil || row < 1 || row > MaxBoardSize {
 		return nil
 	}
 	return &Point{Row: uint16(row), Col: col}
@@ -8,3 +8,24 @@ func TestPointString(t *testing.T) {
 		t.Fatalf("期望,实际%s", p1.String())
 	}
 }
+
+func TestPointFromCoords(t *testing.T) {
+	for _, size := range [][2]uint16{{7, 11}, {21, 21}, {25, 25}} {
+		for row := uint16(1); row <= size[1]; row++ {
+			for col := uint16(1); col <= size[0]; col++ {
+				p := Point{Row: row, Col: col}
+				if q := PointFromCoords(p.String()); q == nil || *q != p {
+					t.Fatalf("%v 转换回来是 %v", p.String(), q)
+				}
+			}
+		}
+	}
+	if p := PointFromCoords("Z25"); p == nil || *p != (Point{Row: 25, Col: 25}) {
+		t.Errorf("Z25 应该是第 25 列: %v", p)
+	}
+	for _, s := range []string{"", "I5", "5", "DX", "A1x", "A0", "A-3", "B99"} {
+		if p := PointFromCoords(s); p != nil {
+			t.Errorf("%q 不是合法的坐标: %v", s, p)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package aigo
+
+// 计分方法
+type ScoringMethod byte
+
+const (
+	AreaScoring      ScoringMethod = iota // 数子法：棋盘上的棋子 + 围住的空点
+	TerritoryScoring                      // 数目法：围住的空点 + 提掉对方的棋子
+)
+
+func (s ScoringMethod) String() string {
+	if s == TerritoryScoring {
+		return "Territory"
+	}
+	return "Area"
+}
+
+// 劫争规则
+type KoRule byte
+
+const (
+	PositionalSuperko  KoRule = iota // 全局同形：不能重现之前出现过的棋盘
+	SimpleKo                         // 只禁止马上提回单劫
+	SituationalSuperko               // 情境同形：不能重现之前出现过的、并且同一方接着下的局面
+)
+
+func (k KoRule) String() string {
+	switch k {
+	case SimpleKo:
+		return "Simple ko"
+	case SituationalSuperko:
+		return "Situational superko"
+	default:
+		return "Positional superko"
+	}
+}
+
+// 让子棋白棋得到的补偿
+type HandicapCompensation byte
+
+const (
+	NoCompensation      HandicapCompensation = iota // 不补偿
+	CompensateN                                     // 让 n 子补偿白棋 n 目
+	CompensateNMinusOne                             // 让 n 子补偿白棋 n-1 目
+)
+
+// 围棋规则
+// 由 GameState 携带，判断落子是否合法、计算胜负时使用
+type Ruleset struct {
+	Name           string        // 规则名称
+	Scoring        ScoringMethod // 计分方法
+	Komi           float64       // 贴目，黑棋先行，结束时加给白棋
+	SuicideAllowed bool          // 是否允许多子自杀，单子自杀总是不允许的
+	Ko             KoRule        // 劫争规则
+	PassStones     bool          // AGA 规则：每跳过一次，交给对方一颗子作为提子
+	NoSekiEyes     bool          // 日本规则：双活里的眼不算地盘
+
+	HandicapCompensation HandicapCompensation // 让子棋给白棋的补偿
+}
+
+var (
+	// 中国规则：数子，贴 3 又 3/4 子，禁止自杀，全局同形，让 n 子补偿白棋 n 目
+	ChineseRules = Ruleset{Name: "Chinese", Scoring: AreaScoring, Komi: 7.5, Ko: PositionalSuperko, HandicapCompensation: CompensateN}
+	// 日本规则：数目，贴 6 目半，禁止自杀，只有普通劫，双活的眼不算地盘
+	JapaneseRules = Ruleset{Name: "Japanese", Scoring: TerritoryScoring, Komi: 6.5, Ko: SimpleKo, NoSekiEyes: true}
+	// AGA 规则：数目加上跳过交出的子，结果与数子法相同（要求白棋最后跳过），贴 7 目半，情境同形，让 n 子补偿 n-1 目
+	AGARules = Ruleset{Name: "AGA", Scoring: TerritoryScoring, Komi: 7.5, Ko: SituationalSuperko, PassStones: true, HandicapCompensation: CompensateNMinusOne}
+	// Tromp-Taylor 规则：数子，允许多子自杀，全局同形
+	TrompTaylorRules = Ruleset{Name: "Tromp-Taylor", Scoring: AreaScoring, Komi: 7.5, SuicideAllowed: true, Ko: PositionalSuperko}
+	// 新西兰规则：数子，贴 7 子，允许多子自杀，情境同形
+	NewZealandRules = Ruleset{Name: "New Zealand", Scoring: AreaScoring, Komi: 7, SuicideAllowed: true, Ko: SituationalSuperko}
+)
+
+func (r Ruleset) String() string {
+	return r.Name
+}
+
+// 让 n 子时的贴目
+// 让子棋只贴半目，再按规则给白棋补偿；n 小于 2 时不是让子棋，贴目不变
+func (r Ruleset) HandicapKomi(n int) float64 {
+	if n < 2 {
+		return r.Komi
+	}
+	switch r.HandicapCompensation {
+	case CompensateN:
+		return 0.5 + float64(n)
+	case CompensateNMinusOne:
+		return 0.5 + float64(n-1)
+	default:
+		return 0.5
+	}
+}
@@ -0,0 +1,126 @@
+package aigo
+
+import (
+	"testing"
+)
+
+// 按顺序执行一串动作，nil 表示跳过
+func playSequence(t *testing.T, game *GameState, points []*Point) *GameState {
+	var err error
+	for _, p := range points {
+		move := NewPass()
+		if p != nil {
+			move = NewPlay(*p)
+		}
+		game, err = game.ApplyMove(move)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return game
+}
+
+// 5x5 棋盘上的一盘棋：黑棋占 C 列，白棋占 D 列
+// 白棋在黑空里点 A1 被提，双方各跳过两次，白棋最后跳过
+// 终局：黑 7 子 8 目，提 1 子；白 6 子 4 目
+func scoringGame(t *testing.T, rules Ruleset) *GameState {
+	game := NewGameWithRules(5, 5, rules)
+	pt := func(row, col uint16) *Point { return &Point{Row: row, Col: col} }
+	return playSequence(t, game, []*Point{
+		pt(1, 3), pt(1, 4), pt(2, 3), pt(2, 4), pt(3, 3), pt(3, 4), pt(4, 3), pt(4, 4), pt(5, 3), pt(5, 4),
+		nil, pt(1, 1), pt(1, 2), nil, pt(2, 1), pt(1, 5), nil, nil,
+	})
+}
+
+func TestRulesetScoring(t *testing.T) {
+	cases := []struct {
+		rules  Ruleset
+		b, w   int
+		winner Player
+		margin float64
+	}{
+		{ChineseRules, 15, 10, White, 2.5},
+		{TrompTaylorRules, 15, 10, White, 2.5},
+		{NewZealandRules, 15, 10, White, 2},
+		{JapaneseRules, 9, 4, White, 1.5},
+		{AGARules, 11, 6, White, 2.5}, // 跳过交出的子让数目法与数子法结果相同
+	}
+	for _, c := range cases {
+		game := scoringGame(t, c.rules)
+		if !game.IsOver() {
+			t.Fatalf("%v: 对局应该结束了", c.rules)
+		}
+		result := game.ComputeGameResult()
+		if result.B != c.b || result.W != c.w || result.KOMI != c.rules.Komi {
+			t.Errorf("%v: 得分 B%d W%d 贴%.1f，期望 B%d W%d", c.rules, result.B, result.W, result.KOMI, c.b, c.w)
+		}
+		if game.Winner().Winner() != c.winner || result.WinningMargin() != c.margin {
+			t.Errorf("%v: %v，期望 %v 胜 %.1f", c.rules, result, c.winner, c.margin)
+		}
+	}
+}
+
+func TestRulesetPrisoners(t *testing.T) {
+	game := scoringGame(t, AGARules)
+	if game.Prisoners[Black] != 3 || game.Prisoners[White] != 2 {
+		t.Errorf("AGA 提子数 %v", game.Prisoners)
+	}
+	game = scoringGame(t, JapaneseRules)
+	if game.Prisoners[Black] != 1 || game.Prisoners[White] != 0 {
+		t.Errorf("日本规则提子数 %v", game.Prisoners)
+	}
+}
+
+// 多子自杀只在允许自杀的规则下合法，单子自杀总是不合法
+func TestRulesetSuicide(t *testing.T) {
+	pt := func(row, col uint16) *Point { return &Point{Row: row, Col: col} }
+	for _, rules := range []Ruleset{ChineseRules, JapaneseRules, AGARules, TrompTaylorRules, NewZealandRules} {
+		// 白棋 A2 B2 B1 围住角上，黑棋 A1 已经在里面
+		game := playSequence(t, NewGameWithRules(5, 5, rules), []*Point{
+			pt(1, 1), pt(2, 1), nil, pt(2, 2), nil, pt(1, 3),
+		})
+		// 黑棋下 B1 是两子自杀
+		multi := NewPlay(Point{Row: 1, Col: 2})
+		if game.IsValidMove(multi) != rules.SuicideAllowed {
+			t.Errorf("%v: 多子自杀的合法性不对", rules)
+		}
+		if rules.SuicideAllowed {
+			next, err := game.ApplyMove(multi)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next.BoardPosition.Get(Point{Row: 1, Col: 1}) != None || next.BoardPosition.Get(Point{Row: 1, Col: 2}) != None {
+				t.Errorf("%v: 自杀的棋子应该被提走", rules)
+			}
+			if next.Prisoners[White] != 2 {
+				t.Errorf("%v: 自杀的棋子应该算白棋的提子 %v", rules, next.Prisoners)
+			}
+		}
+
+		// 白棋 E4 D5 围住右上角，黑棋下在角上是单子自杀
+		game = playSequence(t, NewGameWithRules(5, 5, rules), []*Point{
+			nil, pt(4, 5), nil, pt(5, 4),
+		})
+		if game.IsValidMove(NewPlay(Point{Row: 5, Col: 5})) {
+			t.Errorf("%v: 单子自杀不合法", rules)
+		}
+	}
+}
+
+// 普通劫和全局同形都不能马上提回
+func TestRulesetKo(t *testing.T) {
+	pt := func(row, col uint16) *Point { return &Point{Row: row, Col: col} }
+	for _, rules := range []Ruleset{ChineseRules, JapaneseRules} {
+		game := playSequence(t, NewGameWithRules(5, 5, rules), []*Point{
+			pt(2, 1), pt(2, 2), pt(1, 2), pt(1, 3), pt(5, 5), pt(1, 1),
+		})
+		if game.IsValidMove(NewPlay(Point{Row: 1, Col: 2})) {
+			t.Errorf("%v: 不能马上提劫", rules)
+		}
+		// 找劫材之后可以提回
+		game = playSequence(t, game, []*Point{pt(4, 5), pt(5, 4)})
+		if !game.IsValidMove(NewPlay(Point{Row: 1, Col: 2})) {
+			t.Errorf("%v: 找过劫材之后应该可以提劫", rules)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package aigo
+
+// 双活判断
+// 假设死子都已经提走了。对局结束时还空着的中性点里，
+// 双方谁下都是自己送吃（或者自杀）的点是双活的公气，和公气相邻的棋链就是双活的棋链。
+// 数子法和 AGA 规则里双活的眼照样算地盘，日本规则里不算（Ruleset.NoSekiEyes）。
+
+// 找出双活的棋链、公气和眼，记到 territory 里
+func (b *Board) findSeki(territory *Territory) {
+	// 先找出所有空的区域，判断公气要 Play / Undo，会重新标记
+	type region struct {
+		points       []int32
+		borders      []int32 // 包围区域的棋子，Play / Undo 以后链头可能变，所以不记链头
+		black, white bool    // 是否和黑棋、白棋相邻
+	}
+	regions := []*region{}
+	gen := b.nextMark()
+	for i, c := range b.grid {
+		if c != None || b.mark[i] == gen {
+			continue
+		}
+		r := &region{points: []int32{int32(i)}}
+		b.mark[i] = gen
+		for k := 0; k < len(r.points); k++ {
+			for _, n := range b.adj[4*r.points[k] : 4*r.points[k]+4] {
+				switch {
+				case n < 0:
+				case b.grid[n] == None:
+					if b.mark[n] != gen {
+						b.mark[n] = gen
+						r.points = append(r.points, n)
+					}
+				default:
+					r.borders = appendUniqueInt32(r.borders, n)
+					r.black = r.black || b.grid[n] == Black
+					r.white = r.white || b.grid[n] == White
+				}
+			}
+		}
+		regions = append(regions, r)
+	}
+
+	stones := []int32{}
+	for _, r := range regions {
+		if !r.black || !r.white {
+			continue
+		}
+		for _, e := range r.points {
+			if !b.isSharedLiberty(e) {
+				continue
+			}
+			territory.SharedLiberties = append(territory.SharedLiberties, b.point(e))
+			for _, n := range b.adj[4*e : 4*e+4] {
+				if n >= 0 && b.grid[n] != None {
+					stones = append(stones, n)
+				}
+			}
+		}
+	}
+	if len(stones) == 0 {
+		return
+	}
+	seki := map[int32]bool{}
+	for _, e := range stones {
+		seki[b.head[e]] = true
+	}
+
+	// 只被双活的棋链包围的区域是双活的眼
+	for _, r := range regions {
+		if r.black == r.white {
+			continue
+		}
+		eye := true
+		for _, e := range r.borders {
+			if !seki[b.head[e]] {
+				eye = false
+				break
+			}
+		}
+		if !eye {
+			continue
+		}
+		if r.black {
+			territory.NumBlackSekiEyes += len(r.points)
+		} else {
+			territory.NumWhiteSekiEyes += len(r.points)
+		}
+	}
+	for i, c := range b.grid {
+		if c != None && b.head[i] == int32(i) && seki[int32(i)] {
+			territory.SekiGroups = append(territory.SekiGroups, b.stoneGroupAt(int32(i)))
+		}
+	}
+}
+
+// 空点 i 是否是双活的公气：和双方的棋子都相邻，双方下在这里都会自己送吃或者自杀
+func (b *Board) isSharedLiberty(i int32) bool {
+	black, white := false, false
+	for _, n := range b.adj[4*i : 4*i+4] {
+		if n >= 0 {
+			black = black || b.grid[n] == Black
+			white = white || b.grid[n] == White
+		}
+	}
+	if !black || !white {
+		return false
+	}
+	p := b.point(i)
+	for _, color := range []Player{Black, White} {
+		if !b.isSelfCapture(color, p) && !b.IsSelfAtari(color, p) {
+			return false
+		}
+	}
+	return true
+}
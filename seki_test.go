@@ -0,0 +1,74 @@
+package aigo
+
+import (
+	"testing"
+)
+
+// 左下角双方各有一只眼、一口公气的双活
+func sekiGame(t *testing.T, rules Ruleset) *GameState {
+	return gameFromDiagram(t, rules, Black,
+		"..OXX",
+		"..OX.",
+		"..OXX",
+		"..OX.",
+		"OOOXX",
+		"XXXOO",
+		".X.O.",
+	)
+}
+
+func TestSekiDetection(t *testing.T) {
+	territory := sekiGame(t, JapaneseRules).BoardPosition.EvaluateTerritoryWithSeki()
+	if !PointSliceEqualBCE(territory.SharedLiberties, []Point{{Row: 1, Col: 3}}) {
+		t.Errorf("公气不对: %v", territory.SharedLiberties)
+	}
+	if len(territory.SekiGroups) != 2 {
+		t.Fatalf("应该有两条双活的棋链: %v", territory.SekiGroups)
+	}
+	for _, sg := range territory.SekiGroups {
+		if len(sg.Stones) != 4 && len(sg.Stones) != 3 {
+			t.Errorf("双活的棋链不对: %v", sg)
+		}
+	}
+	if territory.NumBlackSekiEyes != 1 || territory.NumWhiteSekiEyes != 1 {
+		t.Errorf("双活的眼: %d %d", territory.NumBlackSekiEyes, territory.NumWhiteSekiEyes)
+	}
+	if territory.NumBlackTerritory != 3 || territory.NumWhiteTerritory != 9 || territory.NumDame != 1 {
+		t.Errorf("地盘: %+v", territory)
+	}
+
+	// 普通的单官不是公气
+	game := gameFromDiagram(t, JapaneseRules, Black,
+		"..X.O..",
+		"..X.O..",
+		"..X.O..",
+	)
+	territory = game.BoardPosition.EvaluateTerritoryWithSeki()
+	if len(territory.SharedLiberties) != 0 || len(territory.SekiGroups) != 0 {
+		t.Errorf("没有双活: %+v", territory)
+	}
+
+	// EvaluateTerritory 不找双活
+	territory = sekiGame(t, JapaneseRules).BoardPosition.EvaluateTerritory()
+	if len(territory.SekiGroups) != 0 || territory.NumBlackSekiEyes != 0 || territory.NumBlackTerritory != 3 {
+		t.Errorf("EvaluateTerritory: %+v", territory)
+	}
+}
+
+func TestSekiScoring(t *testing.T) {
+	// 数目法：双活的眼不算地盘
+	result := sekiGame(t, JapaneseRules).ComputeGameResult()
+	if result.B != 2 || result.W != 8 {
+		t.Errorf("日本规则: %+v", result)
+	}
+	// AGA 规则和数子法一样，双活的眼算地盘
+	result = sekiGame(t, AGARules).ComputeGameResult()
+	if result.B != 3 || result.W != 9 {
+		t.Errorf("AGA 规则: %+v", result)
+	}
+	// 数子法：棋子加上眼，公气是中性点
+	result = sekiGame(t, ChineseRules).ComputeGameResult()
+	if result.B != 15 || result.W != 19 {
+		t.Errorf("中国规则: %+v", result)
+	}
+}
@@ -0,0 +1,428 @@
+package sgf
+
+import (
+	"fmt"
+	"ghj1976/aigo"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SGF 棋谱和 GameState 之间的转换
+// SGF 的坐标是两个字母，第一个是列，第二个是行，都从 a 开始，行从上往下数；
+// aigo 的行从下往上数，所以 19 路棋盘的 dp 就是 PrintMove 里的 D4。
+// B[] 是跳过，19 路以内的棋盘 B[tt] 也是跳过。
+
+// 从 SGF 读出的一盘棋
+type Record struct {
+	States      []*aigo.GameState // 从空棋盘开始的每个局面，摆棋（AB/AW/AE/PL）和落子都各是一个局面
+	Result      *aigo.GameResult  // RE 属性，没有时为 nil
+	BlackPlayer string            // PB 属性
+	WhitePlayer string            // PW 属性
+}
+
+// 最后的局面
+func (r *Record) Last() *aigo.GameState {
+	return r.States[len(r.States)-1]
+}
+
+// 读入棋谱集合里的所有对局，只取每盘棋的主线
+func ReadRecords(r io.Reader) ([]*Record, error) {
+	trees, err := Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*Record, 0, len(trees))
+	for k, t := range trees {
+		record, err := t.Record()
+		if err != nil {
+			return nil, fmt.Errorf("sgf: game %d: %w", k+1, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// 主线上的所有节点：自己的节点，然后一直沿着第一个分支往下
+func (t *GameTree) MainLine() []*Node {
+	nodes := []*Node{}
+	for ; t != nil; t = firstChild(t) {
+		nodes = append(nodes, t.Nodes...)
+	}
+	return nodes
+}
+
+func firstChild(t *GameTree) *GameTree {
+	if len(t.Children) == 0 {
+		return nil
+	}
+	return t.Children[0]
+}
+
+// 按主线重放对局
+// 根节点的 SZ、KM、RU、HA 决定棋盘和规则，没有 RU 时用中国规则，KM 覆盖规则里的贴目
+func (t *GameTree) Record() (*Record, error) {
+	nodes := t.MainLine()
+	root := nodes[0]
+	gs, err := newGame(root)
+	if err != nil {
+		return nil, err
+	}
+	w, h := gs.BoardPosition.Width, gs.BoardPosition.Height
+
+	record := &Record{States: []*aigo.GameState{gs}, BlackPlayer: root.Get("PB"), WhitePlayer: root.Get("PW")}
+	for k, n := range nodes {
+		if hasSetup(n) {
+			setup, err := readSetup(n, w, h)
+			if err != nil {
+				return nil, fmt.Errorf("sgf: node %d: %w", k, err)
+			}
+			next := nextPlayer(nodes[k:], gs.PlayerTurn)
+			if gs, err = gs.ApplySetup(setup, next); err != nil {
+				return nil, fmt.Errorf("sgf: node %d: %w", k, err)
+			}
+			record.States = append(record.States, gs)
+		}
+		for _, color := range []aigo.Player{aigo.Black, aigo.White} {
+			if !n.Has(colorID(color)) {
+				continue
+			}
+			if gs.PlayerTurn != color { // 连着下了两步，或者让子后黑棋先下，先换一下轮到谁
+				gs, _ = gs.ApplySetup(aigo.Setup{}, color)
+				record.States = append(record.States, gs)
+			}
+			move, err := readMove(n, color, w, h)
+			if err != nil {
+				return nil, fmt.Errorf("sgf: node %d: %w", k, err)
+			}
+			if gs, err = gs.ApplyMove(move); err != nil {
+				return nil, fmt.Errorf("sgf: node %d: %s: %w", k, aigo.PrintMove(color, move), err)
+			}
+			record.States = append(record.States, gs)
+		}
+	}
+
+	if root.Has("RE") {
+		if record.Result, err = aigo.ParseGameResult(root.Get("RE")); err != nil {
+			// 有的棋谱在 RE 里写说明文字，当作结果未知
+			record.Result = aigo.NewGameResult(aigo.ResultUnknown, aigo.None)
+		}
+		// 认输的一方正好轮到、对局还没有结束时补上认输，这样最后局面的 Winner 和 RE 一致
+		if record.Result.Kind == aigo.ResultResign && gs.PlayerTurn == record.Result.Win.Other() && !gs.IsOver() {
+			gs, _ = gs.ApplyMove(aigo.NewResign())
+			record.States = append(record.States, gs)
+		}
+	}
+	return record, nil
+}
+
+// 按根节点的 GM、SZ、RU、KM、HA 属性开始一盘新棋
+func newGame(root *Node) (*aigo.GameState, error) {
+	if gm := root.Get("GM"); gm != "" && gm != "1" {
+		return nil, fmt.Errorf("sgf: GM[%s] is not a game of Go", gm)
+	}
+	w, h, err := parseSize(root.Get("SZ"))
+	if err != nil {
+		return nil, err
+	}
+	rules := rulesFromSGF(root.Get("RU"))
+	if km := strings.TrimSpace(root.Get("KM")); km != "" {
+		komi, err := strconv.ParseFloat(km, 64)
+		if err != nil {
+			return nil, fmt.Errorf("sgf: invalid komi KM[%s]", km)
+		}
+		rules.Komi = komi
+	}
+	gs := aigo.NewGameWithRules(w, h, rules)
+	if ha := strings.TrimSpace(root.Get("HA")); ha != "" {
+		n, err := strconv.Atoi(ha)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("sgf: invalid handicap HA[%s]", ha)
+		}
+		gs.Handicap = n
+	}
+	return gs, nil
+}
+
+// 节点里有没有摆棋的属性
+func hasSetup(n *Node) bool {
+	return n.Has("AB") || n.Has("AW") || n.Has("AE") || n.Has("PL")
+}
+
+// 节点里摆上和拿掉（AE）的棋子
+func readSetup(n *Node, w, h uint16) (aigo.Setup, error) {
+	var setup aigo.Setup
+	var err error
+	if setup.Empty, err = decodePointList(n.Values("AE"), w, h); err != nil {
+		return setup, err
+	}
+	if setup.Black, err = decodePointList(n.Values("AB"), w, h); err != nil {
+		return setup, err
+	}
+	if setup.White, err = decodePointList(n.Values("AW"), w, h); err != nil {
+		return setup, err
+	}
+	return setup, nil
+}
+
+// 节点里 color 一方的落子
+func readMove(n *Node, color aigo.Player, w, h uint16) (aigo.Move, error) {
+	p, err := DecodePoint(n.Get(colorID(color)), w, h)
+	if err != nil {
+		return aigo.Move{}, err
+	}
+	if p == nil {
+		return aigo.NewPass(), nil
+	}
+	return aigo.NewPlay(*p), nil
+}
+
+// 摆棋以后轮到谁：有 PL 看 PL，否则看后面第一步棋是谁下的，都没有就不变
+func nextPlayer(nodes []*Node, turn aigo.Player) aigo.Player {
+	switch strings.ToUpper(strings.TrimSpace(nodes[0].Get("PL"))) {
+	case "B", "1":
+		return aigo.Black
+	case "W", "2":
+		return aigo.White
+	}
+	for _, n := range nodes {
+		if n.Has("B") {
+			return aigo.Black
+		}
+		if n.Has("W") {
+			return aigo.White
+		}
+	}
+	return turn
+}
+
+// SZ[19] 或者 SZ[19:13]，没有时是 19 路；SGF 允许到 52 路，这里最大只能到 aigo.MaxBoardSize 路
+func parseSize(sz string) (uint16, uint16, error) {
+	sz = strings.TrimSpace(sz)
+	if sz == "" {
+		return 19, 19, nil
+	}
+	ws, hs := sz, sz
+	if k := strings.IndexByte(sz, ':'); k >= 0 {
+		ws, hs = sz[:k], sz[k+1:]
+	}
+	w, err1 := strconv.Atoi(strings.TrimSpace(ws))
+	h, err2 := strconv.Atoi(strings.TrimSpace(hs))
+	if err1 != nil || err2 != nil || w < 1 || h < 1 || w > aigo.MaxBoardSize || h > aigo.MaxBoardSize {
+		return 0, 0, fmt.Errorf("sgf: invalid board size SZ[%s]", sz)
+	}
+	return uint16(w), uint16(h), nil
+}
+
+func formatSize(w, h uint16) string {
+	if w == h {
+		return strconv.Itoa(int(w))
+	}
+	return fmt.Sprintf("%d:%d", w, h)
+}
+
+// RU 属性对应的规则，不认识的规则名用中国规则
+func rulesFromSGF(name string) aigo.Ruleset {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "japanese", "jp":
+		return aigo.JapaneseRules
+	case "aga":
+		return aigo.AGARules
+	case "tromp-taylor", "tt":
+		return aigo.TrompTaylorRules
+	case "nz", "new zealand":
+		return aigo.NewZealandRules
+	}
+	return aigo.ChineseRules
+}
+
+// 写入 RU 属性的规则名，新西兰规则在 SGF 里写作 NZ
+func rulesToSGF(rules aigo.Ruleset) string {
+	if rules.Name == aigo.NewZealandRules.Name {
+		return "NZ"
+	}
+	return rules.Name
+}
+
+func colorID(color aigo.Player) string {
+	if color == aigo.White {
+		return "W"
+	}
+	return "B"
+}
+
+// 坐标的字母：a-z 是 0-25，A-Z 是 26-51
+func coordIndex(c byte) int {
+	switch {
+	case 'a' <= c && c <= 'z':
+		return int(c - 'a')
+	case 'A' <= c && c <= 'Z':
+		return int(c-'A') + 26
+	}
+	return -1
+}
+
+func coordLetter(i int) byte {
+	if i < 26 {
+		return byte('a' + i)
+	}
+	return byte('A' + i - 26)
+}
+
+// 把 SGF 坐标转换成棋盘上的点，跳过（空值，或者 19 路以内的 tt）返回 nil
+func DecodePoint(s string, w, h uint16) (*aigo.Point, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || (s == "tt" && w <= 19 && h <= 19) {
+		return nil, nil
+	}
+	if len(s) != 2 {
+		return nil, fmt.Errorf("invalid point %q", s)
+	}
+	col, row := coordIndex(s[0]), coordIndex(s[1])
+	if col < 0 || row < 0 || col >= int(w) || row >= int(h) {
+		return nil, fmt.Errorf("point %q is off the %dx%d board", s, w, h)
+	}
+	return &aigo.Point{Row: h - uint16(row), Col: uint16(col) + 1}, nil
+}
+
+// 棋盘上的点转换成 SGF 坐标，h 是棋盘的行数
+func EncodePoint(p aigo.Point, h uint16) string {
+	return string([]byte{coordLetter(int(p.Col) - 1), coordLetter(int(h - p.Row))})
+}
+
+// AB、AW 的点列表，可以用 aa:cc 表示一个矩形里的所有点
+func decodePointList(values []string, w, h uint16) ([]aigo.Point, error) {
+	points := []aigo.Point{}
+	for _, v := range values {
+		from, to := v, v
+		if k := strings.IndexByte(v, ':'); k >= 0 {
+			from, to = v[:k], v[k+1:]
+		}
+		a, err := DecodePoint(from, w, h)
+		if err != nil {
+			return nil, err
+		}
+		b, err := DecodePoint(to, w, h)
+		if err != nil {
+			return nil, err
+		}
+		if a == nil || b == nil {
+			return nil, fmt.Errorf("invalid point %q", v)
+		}
+		for row := min16(a.Row, b.Row); row <= max16(a.Row, b.Row); row++ {
+			for col := min16(a.Col, b.Col); col <= max16(a.Col, b.Col); col++ {
+				points = append(points, aigo.Point{Row: row, Col: col})
+			}
+		}
+	}
+	return points, nil
+}
+
+func min16(a, b uint16) uint16 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max16(a, b uint16) uint16 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// 把 gs 和它之前的整个对局历史转换成 SGF
+// 根节点写棋盘大小、贴目、规则和让子数；摆棋的局面写成 AB/AW/PL，落子写成 B/W；
+// 认输不写成节点，对局结束时写 RE 属性
+func FromGameState(gs *aigo.GameState) *GameTree {
+	history := []*aigo.GameState{}
+	for s := gs; s != nil; s = s.PreviousState {
+		history = append(history, s)
+	}
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+
+	first := history[0]
+	w, h := first.BoardPosition.Width, first.BoardPosition.Height
+	root := &Node{}
+	root.Set("GM", "1")
+	root.Set("FF", "4")
+	root.Set("CA", "UTF-8")
+	root.Set("AP", "aigo")
+	root.Set("SZ", formatSize(w, h))
+	root.Set("KM", strconv.FormatFloat(first.Rules.Komi, 'f', -1, 64))
+	root.Set("RU", rulesToSGF(first.Rules))
+	if first.Handicap > 0 {
+		root.Set("HA", strconv.Itoa(first.Handicap))
+	}
+	if result := gs.Winner(); result != nil {
+		root.Set("RE", result.SGF())
+	}
+
+	t := &GameTree{Nodes: []*Node{root}}
+	for k, s := range history[1:] {
+		prev := history[k]
+		switch {
+		case s.Setup != nil:
+			n := &Node{}
+			if k == 0 { // 开局的摆棋（一般是让子）写在根节点里
+				n = root
+			}
+			writeSetup(n, s.Setup, s.PlayerTurn, h)
+			if n != root {
+				t.Nodes = append(t.Nodes, n)
+			}
+		case s.LastMove == nil || s.LastMove.IsResign:
+		case s.LastMove.IsPass:
+			n := &Node{}
+			n.Set(colorID(prev.PlayerTurn), "")
+			t.Nodes = append(t.Nodes, n)
+		default:
+			n := &Node{}
+			n.Set(colorID(prev.PlayerTurn), EncodePoint(s.LastMove.Pnt, h))
+			t.Nodes = append(t.Nodes, n)
+		}
+	}
+	return t
+}
+
+func encodePoints(points []aigo.Point, h uint16) []string {
+	values := make([]string, len(points))
+	for k, p := range points {
+		values[k] = EncodePoint(p, h)
+	}
+	return values
+}
+
+// 转换成 SGF，写上对局双方；有 Result 时用它作为 RE，不管最后的局面是否结束
+func (r *Record) GameTree() *GameTree {
+	t := FromGameState(r.Last())
+	root := t.Nodes[0]
+	if r.BlackPlayer != "" {
+		root.Set("PB", r.BlackPlayer)
+	}
+	if r.WhitePlayer != "" {
+		root.Set("PW", r.WhitePlayer)
+	}
+	if r.Result != nil {
+		root.Set("RE", r.Result.SGF())
+	}
+	return t
+}
+
+// 把 gs 的对局历史写成 SGF
+func WriteGame(w io.Writer, gs *aigo.GameState) error {
+	return Write(w, FromGameState(gs))
+}
+
+// 把多盘棋写成一个棋谱集合
+func WriteRecords(w io.Writer, records ...*Record) error {
+	for _, r := range records {
+		if err := Write(w, r.GameTree()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
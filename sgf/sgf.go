@@ -0,0 +1,287 @@
+package sgf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SGF（Smart Game Format，FF[4]）棋谱的读写
+// 这个文件只管 SGF 的语法：棋谱集合、树、节点、属性，不关心属性的含义；
+// 围棋相关的解释（棋盘大小、贴目、让子、落子、结果）在 game.go 里。
+// https://www.red-bean.com/sgf/
+
+// 属性，例如 B[pd]、AB[dd][pp]
+type Property struct {
+	ID     string   // 属性名，只有大写字母
+	Values []string // 属性值，已经去掉了转义
+}
+
+// 节点，分号开头的一组属性，保持文件里的顺序
+type Node struct {
+	Properties []Property
+}
+
+// 树：一串节点，后面跟着若干个变化分支，第一个分支是主线
+type GameTree struct {
+	Nodes    []*Node
+	Children []*GameTree
+}
+
+// 属性的第一个值，没有这个属性时返回空字符串
+func (n *Node) Get(id string) string {
+	if values := n.Values(id); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// 属性的所有值，没有这个属性时返回 nil
+func (n *Node) Values(id string) []string {
+	for _, p := range n.Properties {
+		if p.ID == id {
+			return p.Values
+		}
+	}
+	return nil
+}
+
+// 是否有这个属性
+func (n *Node) Has(id string) bool {
+	return n.Values(id) != nil
+}
+
+// 设置属性的值，已经有这个属性时替换掉，保持原来的位置
+func (n *Node) Set(id string, values ...string) {
+	for k := range n.Properties {
+		if n.Properties[k].ID == id {
+			n.Properties[k].Values = values
+			return
+		}
+	}
+	n.Properties = append(n.Properties, Property{ID: id, Values: values})
+}
+
+// 删除属性
+func (n *Node) Delete(id string) {
+	for k := range n.Properties {
+		if n.Properties[k].ID == id {
+			n.Properties = append(n.Properties[:k], n.Properties[k+1:]...)
+			return
+		}
+	}
+}
+
+// 读入一个棋谱集合，一个文件里可以有多盘棋
+func Parse(r io.Reader) ([]*GameTree, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseString(string(data))
+}
+
+// 从字符串读入棋谱集合
+func ParseString(s string) ([]*GameTree, error) {
+	p := &parser{s: s}
+	trees := []*GameTree{}
+	for {
+		// 第一个 ( 之前、各盘棋之间可能有别的文字，跳过
+		k := strings.IndexByte(p.s[p.pos:], '(')
+		if k < 0 {
+			break
+		}
+		p.pos += k
+		t, err := p.tree()
+		if err != nil {
+			return nil, err
+		}
+		trees = append(trees, t)
+	}
+	if len(trees) == 0 {
+		return nil, fmt.Errorf("sgf: no game tree found")
+	}
+	return trees, nil
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("sgf: offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n\v\f", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// 当前位置的字符，到结尾时返回 0
+func (p *parser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+// GameTree = "(" Sequence { GameTree } ")"
+func (p *parser) tree() (*GameTree, error) {
+	if p.peek() != '(' {
+		return nil, p.errorf("expected '('")
+	}
+	p.pos++
+	t := &GameTree{}
+	for p.peek() == ';' {
+		p.pos++
+		n, err := p.node()
+		if err != nil {
+			return nil, err
+		}
+		t.Nodes = append(t.Nodes, n)
+	}
+	if len(t.Nodes) == 0 {
+		return nil, p.errorf("game tree without nodes")
+	}
+	for p.peek() == '(' {
+		c, err := p.tree()
+		if err != nil {
+			return nil, err
+		}
+		t.Children = append(t.Children, c)
+	}
+	if p.peek() != ')' {
+		return nil, p.errorf("expected ')'")
+	}
+	p.pos++
+	return t, nil
+}
+
+// Node = ";" { Property }
+func (p *parser) node() (*Node, error) {
+	n := &Node{}
+	for {
+		c := p.peek()
+		if !isLetter(c) {
+			return n, nil
+		}
+		// FF[3] 的属性名里可以有小写字母，忽略掉，比如 CoPyright 就是 CP
+		id := []byte{}
+		for p.pos < len(p.s) && isLetter(p.s[p.pos]) {
+			if c := p.s[p.pos]; 'A' <= c && c <= 'Z' {
+				id = append(id, c)
+			}
+			p.pos++
+		}
+		if len(id) == 0 {
+			return nil, p.errorf("property name without upper case letters")
+		}
+		prop := Property{ID: string(id)}
+		for p.peek() == '[' {
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			prop.Values = append(prop.Values, v)
+		}
+		if len(prop.Values) == 0 {
+			return nil, p.errorf("property %s without value", prop.ID)
+		}
+		if old := n.Values(prop.ID); old != nil { // 重复的属性合并到一起
+			prop.Values = append(old, prop.Values...)
+		}
+		n.Set(prop.ID, prop.Values...)
+	}
+}
+
+// "[" 值 "]"，处理转义：\ 后面的字符照原样保留，\ 加换行是软换行，去掉
+func (p *parser) value() (string, error) {
+	p.pos++ // [
+	buf := strings.Builder{}
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case ']':
+			return buf.String(), nil
+		case '\\':
+			if p.pos >= len(p.s) {
+				break
+			}
+			c = p.s[p.pos]
+			p.pos++
+			if c == '\n' || c == '\r' {
+				// \r\n 和 \n\r 也是一个换行
+				if p.pos < len(p.s) && (p.s[p.pos] == '\n' || p.s[p.pos] == '\r') && p.s[p.pos] != c {
+					p.pos++
+				}
+				continue
+			}
+			buf.WriteByte(c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated property value")
+}
+
+func isLetter(c byte) bool {
+	return ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z')
+}
+
+// 写出一个棋谱集合
+func Write(w io.Writer, trees ...*GameTree) error {
+	for _, t := range trees {
+		if _, err := io.WriteString(w, t.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 序列化成 SGF 文本，每个节点一行
+func (t *GameTree) String() string {
+	buf := strings.Builder{}
+	t.write(&buf)
+	buf.WriteString("\n")
+	return buf.String()
+}
+
+func (t *GameTree) write(buf *strings.Builder) {
+	buf.WriteString("(")
+	for k, n := range t.Nodes {
+		if k > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(n.String())
+	}
+	for _, c := range t.Children {
+		buf.WriteString("\n")
+		c.write(buf)
+	}
+	buf.WriteString(")")
+}
+
+// 序列化一个节点，属性值里的 \ 和 ] 要转义
+func (n *Node) String() string {
+	buf := strings.Builder{}
+	buf.WriteString(";")
+	for _, p := range n.Properties {
+		buf.WriteString(p.ID)
+		for _, v := range p.Values {
+			buf.WriteString("[")
+			buf.WriteString(escape(v))
+			buf.WriteString("]")
+		}
+	}
+	return buf.String()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `]`, `\]`)
+
+func escape(v string) string {
+	return escaper.Replace(v)
+}
@@ -0,0 +1,304 @@
+package sgf
+
+import (
+	"bytes"
+	"ghj1976/aigo"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readCollection(t *testing.T) []*GameTree {
+	f, err := os.Open("testdata/collection.sgf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	trees, err := Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trees
+}
+
+func TestParseCollection(t *testing.T) {
+	trees := readCollection(t)
+	if len(trees) != 3 {
+		t.Fatalf("应该有 3 盘棋，实际 %d 盘", len(trees))
+	}
+	root := trees[0].Nodes[0]
+	if got := root.Values("AB"); !reflect.DeepEqual(got, []string{"dd", "pp"}) {
+		t.Errorf("AB: %v", got)
+	}
+	want := "Handicap game: two stones.\nThe comment has an escaped ] bracket, a backslash \\ and a soft break."
+	if got := root.Get("C"); got != want {
+		t.Errorf("转义和软换行:\n%q\n%q", got, want)
+	}
+	if len(trees[0].Nodes) != 4 || len(trees[0].Children) != 2 {
+		t.Errorf("第一盘棋 %d 个节点 %d 个分支", len(trees[0].Nodes), len(trees[0].Children))
+	}
+	// FF[3] 的属性名去掉小写字母
+	last := trees[2].Nodes[0]
+	if last.Get("GM") != "1" || last.Get("SZ") != "9:7" {
+		t.Errorf("旧格式的属性名: %v", last.Properties)
+	}
+	if got := trees[2].Nodes[3].Get("C"); got != "old style names" {
+		t.Errorf("Comment: %q", got)
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	trees := readCollection(t)
+	buf := &bytes.Buffer{}
+	if err := Write(buf, trees...); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ParseString(buf.String())
+	if err != nil {
+		t.Fatalf("%v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(trees, again) {
+		t.Errorf("写出再读入不一样:\n%s", buf.String())
+	}
+	if s := trees[0].String(); !strings.Contains(s, `escaped \] bracket, a backslash \\ and`) {
+		t.Errorf("没有转义:\n%s", s)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"(;B[aa]",
+		"(;C[unterminated)",
+		"(;B)",
+		"()",
+		"(;[aa])",
+	} {
+		if _, err := ParseString(s); err == nil {
+			t.Errorf("%q 应该出错", s)
+		}
+	}
+	for _, s := range []string{
+		"(;SZ[9];B[ee];W[ee])",
+		"(;SZ[9];B[zz])",
+		"(;SZ[9]AE[zz])",
+		"(;GM[2])",
+		"(;SZ[abc])",
+		"(;GM[1]FF[4]SZ[30];B[aa])",
+		"(;KM[six])",
+	} {
+		trees, err := ParseString(s)
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		if _, err := trees[0].Record(); err == nil {
+			t.Errorf("%q 应该出错", s)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	records, err := ReadRecords(strings.NewReader(readFile(t, "testdata/collection.sgf")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := records[0]
+	first := r.States[0]
+	if first.Rules.Name != "Japanese" || first.Rules.Komi != 0.5 || first.Handicap != 2 {
+		t.Errorf("规则 %v 贴目 %v 让子 %d", first.Rules, first.Rules.Komi, first.Handicap)
+	}
+	if r.BlackPlayer != "Alice" || r.WhitePlayer != "Bob" {
+		t.Errorf("对局者: %q %q", r.BlackPlayer, r.WhitePlayer)
+	}
+	setup := r.States[1]
+	if setup.Setup == nil || setup.PlayerTurn != aigo.White || setup.BoardPosition.Get(aigo.Point{Row: 16, Col: 4}) != aigo.Black {
+		t.Errorf("让子的局面:\n%v", setup)
+	}
+	moves := []string{}
+	for _, s := range r.States[2:] {
+		moves = append(moves, aigo.PrintMove(s.PreviousState.PlayerTurn, *s.LastMove))
+	}
+	want := []string{"White D4", "Black Q16", "White R14", "Black O17", "White R17", aigo.PrintMove(aigo.Black, aigo.NewResign())}
+	if !reflect.DeepEqual(moves, want) {
+		t.Errorf("主线: %v", moves)
+	}
+	if result := r.Last().Winner(); result == nil || result.SGF() != "W+R" || r.Result.SGF() != "W+R" {
+		t.Errorf("结果: %v %v", result, r.Result)
+	}
+
+	// tt 和空值都是跳过；黑棋认输没有轮到黑棋，不补认输
+	r = records[1]
+	if n := len(r.States); n != 6 || !r.States[2].LastMove.IsPass || !r.States[4].LastMove.IsPass {
+		t.Errorf("第二盘棋 %d 个局面", n)
+	}
+	if r.Result.Winner() != aigo.White || r.Last().LastMove.IsResign {
+		t.Errorf("第二盘棋的结果: %v", r.Result)
+	}
+
+	r = records[2]
+	if b := r.Last().BoardPosition; b.Width != 9 || b.Height != 7 || b.Get(aigo.Point{Row: 6, Col: 9}) != aigo.Black {
+		t.Errorf("9x7 的棋盘:\n%v", b)
+	}
+	if r.Result != nil {
+		t.Errorf("没有 RE 时结果应该是 nil: %v", r.Result)
+	}
+
+	// 双方跳过已经结束了，RE 是认输时不再补认输
+	trees, err := ParseString("(;SZ[9]RE[B+R];B[ee];W[];B[])")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err = trees[0].Record()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.States) != 4 || r.Last().LastMove.IsResign {
+		t.Errorf("结束以后的认输: %d 个局面", len(r.States))
+	}
+}
+
+// 超过 MaxBoardSize 的棋盘返回错误，不能 panic
+func TestOversizedBoard(t *testing.T) {
+	s := "(;GM[1]FF[4]SZ[30];B[aa])"
+	if _, err := ReadRecords(strings.NewReader(s)); err == nil {
+		t.Error("ReadRecords 应该出错")
+	}
+	trees, err := ParseString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trees[0].GameRecord(); err == nil {
+		t.Error("GameRecord 应该出错")
+	}
+}
+
+func readFile(t *testing.T, name string) string {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestPointCoordinates(t *testing.T) {
+	for s, want := range map[string]string{"dp": "D4", "aa": "A19", "sa": "T19", "as": "A1", "pd": "Q16"} {
+		p, err := DecodePoint(s, 19, 19)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := aigo.NewPlay(*p).StringChessRecord(); got != want {
+			t.Errorf("%s: %s, 应该是 %s", s, got, want)
+		}
+	}
+	for _, size := range [][2]uint16{{19, 19}, {13, 9}, {25, 25}} {
+		w, h := size[0], size[1]
+		for row := uint16(1); row <= h; row++ {
+			for col := uint16(1); col <= w; col++ {
+				p := aigo.Point{Row: row, Col: col}
+				s := EncodePoint(p, h)
+				q, err := DecodePoint(s, w, h)
+				if err != nil || q == nil || *q != p {
+					t.Fatalf("%dx%d %v -> %s -> %v %v", w, h, p, s, q, err)
+				}
+			}
+		}
+	}
+	if p, err := DecodePoint("tt", 19, 19); p != nil || err != nil {
+		t.Errorf("tt 是跳过: %v %v", p, err)
+	}
+	if p, err := DecodePoint("tt", 21, 21); p == nil || err != nil {
+		t.Errorf("21 路棋盘上 tt 是一个点: %v %v", p, err)
+	}
+}
+
+// 写出的棋谱读回来，每个局面都一样
+func checkWriteGame(t *testing.T, gs *aigo.GameState) {
+	buf := &bytes.Buffer{}
+	if err := WriteGame(buf, gs); err != nil {
+		t.Fatal(err)
+	}
+	records, err := ReadRecords(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := []*aigo.GameState{}
+	for s := gs; s != nil; s = s.PreviousState {
+		history = append([]*aigo.GameState{s}, history...)
+	}
+	states := records[0].States
+	if len(states) != len(history) {
+		t.Fatalf("局面数 %d, 应该是 %d", len(states), len(history))
+	}
+	for k, s := range states {
+		if s.ZobristHash128() != history[k].ZobristHash128() || s.PlayerTurn != history[k].PlayerTurn || s.Rules != history[k].Rules {
+			t.Fatalf("第 %d 个局面不一样:\n%v\n%v", k, s, history[k])
+		}
+	}
+	if want := gs.Winner(); want != nil && records[0].Result.SGF() != want.SGF() {
+		t.Errorf("结果 %v, 应该是 %v", records[0].Result, want)
+	}
+}
+
+// AE 拿掉棋子，拿掉一颗子以后棋链分开
+func TestRemoveStones(t *testing.T) {
+	trees, err := ParseString("(;SZ[5]AB[aa][ba][ca];AE[ba]AW[ee];B[cc])")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := trees[0].Record()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := record.Last()
+	b := gs.BoardPosition
+	if b.Get(aigo.Point{Row: 5, Col: 2}) != aigo.None || b.Get(aigo.Point{Row: 1, Col: 5}) != aigo.White || len(b.GetStoneGroup(aigo.Point{Row: 5, Col: 1}).Stones) != 1 {
+		t.Fatalf("AE 以后的棋盘:\n%v", gs)
+	}
+	checkWriteGame(t, gs)
+	if s := FromGameState(gs).String(); !strings.Contains(s, "AE[ba]") {
+		t.Errorf("写出的棋谱:\n%s", s)
+	}
+}
+
+func TestWriteGame(t *testing.T) {
+	gs := aigo.NewGameWithRules(9, 9, aigo.NewZealandRules)
+	for _, m := range []aigo.Move{
+		aigo.NewPlay(aigo.Point{Row: 5, Col: 5}),
+		aigo.NewPlay(aigo.Point{Row: 5, Col: 6}),
+		aigo.NewPass(),
+		aigo.NewPlay(aigo.Point{Row: 1, Col: 9}),
+		aigo.NewPass(),
+		aigo.NewPass(),
+	} {
+		var err error
+		if gs, err = gs.ApplyMove(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkWriteGame(t, gs)
+	if s := FromGameState(gs).String(); !strings.Contains(s, "RU[NZ]") || !strings.Contains(s, ";W[ii]") || !strings.Contains(s, ";B[]") {
+		t.Errorf("写出的棋谱:\n%s", s)
+	}
+
+	// 让子棋，白棋认输
+	gs, err := aigo.NewHandicapGame(9, 2, aigo.AGARules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gs, err = gs.ApplyMove(aigo.NewPlay(aigo.Point{Row: 5, Col: 5})); err != nil {
+		t.Fatal(err)
+	}
+	// 白棋连下两步，中间插入一个换手的局面
+	if gs, err = gs.ApplySetup(aigo.Setup{}, aigo.White); err != nil {
+		t.Fatal(err)
+	}
+	if gs, err = gs.ApplyMove(aigo.NewResign()); err != nil {
+		t.Fatal(err)
+	}
+	checkWriteGame(t, gs)
+	if s := FromGameState(gs).String(); !strings.Contains(s, "HA[2]") || !strings.Contains(s, "RE[B+R]") {
+		t.Errorf("写出的棋谱:\n%s", s)
+	}
+}
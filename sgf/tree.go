@@ -0,0 +1,302 @@
+package sgf
+
+import (
+	"fmt"
+	"ghj1976/aigo"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 棋谱树 aigo.GameRecord 和 SGF 之间的转换
+// SGF 的变化就是棋谱树的子节点；C 是注释，TR、SQ、CR、MA、SL、LB 是标记，
+// AB、AW、AE、PL 是摆棋，其他属性放在节点的 Extra 里原样保留。
+// 棋谱树的一个节点只有一个落子或者一次摆棋，SGF 节点里同时有摆棋和落子（包括根节点上的落子）时拆成几个节点，
+// 注释和标记放在最后一个上（根节点的放在根节点上），拆出来的节点 SameNode 为 true，写出时再合并成一个 SGF 节点。
+// 认输在 SGF 里没有对应的落子，写出时认输节点只保留注释和标记。
+
+var markupIDs = []struct {
+	id   string
+	kind aigo.MarkupKind
+}{
+	{"TR", aigo.MarkTriangle},
+	{"SQ", aigo.MarkSquare},
+	{"CR", aigo.MarkCircle},
+	{"MA", aigo.MarkCross},
+	{"SL", aigo.MarkSelected},
+}
+
+// 根节点上由 newGame 解释的属性
+var rootIDs = map[string]bool{"GM": true, "FF": true, "CA": true, "SZ": true, "KM": true, "RU": true, "HA": true}
+
+// 每个节点上有专门字段的属性
+var nodeIDs = map[string]bool{
+	"B": true, "W": true, "AB": true, "AW": true, "AE": true, "PL": true,
+	"C": true, "TR": true, "SQ": true, "CR": true, "MA": true, "SL": true, "LB": true,
+}
+
+// 转换成棋谱树，包括所有的变化，游标在根节点
+func (t *GameTree) GameRecord() (*aigo.GameRecord, error) {
+	root := t.Nodes[0]
+	gs, err := newGame(root)
+	if err != nil {
+		return nil, err
+	}
+	b := &recordBuilder{w: gs.BoardPosition.Width, h: gs.BoardPosition.Height}
+	if hasSetup(root) {
+		setup, err := readSetup(root, b.w, b.h)
+		if err != nil {
+			return nil, fmt.Errorf("sgf: root: %w", err)
+		}
+		if gs, err = gs.ApplySetup(setup, nextPlayer(t.MainLine(), gs.PlayerTurn)); err != nil {
+			return nil, fmt.Errorf("sgf: root: %w", err)
+		}
+	}
+	b.record = aigo.NewGameRecord(gs)
+	if err := b.moves(root, true); err != nil {
+		return nil, err
+	}
+	if err := b.annotate(b.record.Root, root, true); err != nil {
+		return nil, err
+	}
+	if err := b.tree(t, 1); err != nil {
+		return nil, err
+	}
+	b.record.Rewind()
+	return b.record, nil
+}
+
+type recordBuilder struct {
+	record *aigo.GameRecord
+	w, h   uint16
+}
+
+// 从 t 的第 start 个节点开始，在游标后面加上节点和所有变化
+func (b *recordBuilder) tree(t *GameTree, start int) error {
+	for k := start; k < len(t.Nodes); k++ {
+		n := t.Nodes[k]
+		line := append(append([]*Node{}, t.Nodes[k:]...), firstChild(t).MainLine()...)
+		if hasSetup(n) {
+			setup, err := readSetup(n, b.w, b.h)
+			if err != nil {
+				return fmt.Errorf("sgf: %w", err)
+			}
+			if _, err := b.record.AddSetup(setup, nextPlayer(line, b.record.State().PlayerTurn)); err != nil {
+				return fmt.Errorf("sgf: %w", err)
+			}
+		}
+		if !hasSetup(n) && !n.Has("B") && !n.Has("W") {
+			b.record.AddNode()
+		}
+		if err := b.moves(n, hasSetup(n)); err != nil {
+			return err
+		}
+		if err := b.annotate(b.record.Current, n, false); err != nil {
+			return err
+		}
+	}
+	current := b.record.Current
+	for _, c := range t.Children {
+		b.record.GoTo(current)
+		if err := b.tree(c, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 节点里的落子，same 表示这个 SGF 节点已经有了对应的棋谱树节点
+func (b *recordBuilder) moves(n *Node, same bool) error {
+	for _, color := range []aigo.Player{aigo.Black, aigo.White} {
+		if !n.Has(colorID(color)) {
+			continue
+		}
+		move, err := readMove(n, color, b.w, b.h)
+		if err != nil {
+			return fmt.Errorf("sgf: %w", err)
+		}
+		gn, err := b.record.AddMove(color, move)
+		if err != nil {
+			return fmt.Errorf("sgf: %s: %w", aigo.PrintMove(color, move), err)
+		}
+		gn.SameNode = same
+		same = true
+	}
+	return nil
+}
+
+// 注释、标记和其他属性
+func (b *recordBuilder) annotate(gn *aigo.GameNode, n *Node, root bool) error {
+	gn.Comment = n.Get("C")
+	for _, m := range markupIDs {
+		points, err := decodePointList(n.Values(m.id), b.w, b.h)
+		if err != nil {
+			return fmt.Errorf("sgf: %s: %w", m.id, err)
+		}
+		for _, p := range points {
+			gn.AddMarkup(m.kind, p, "")
+		}
+	}
+	for _, v := range n.Values("LB") {
+		k := strings.IndexByte(v, ':')
+		if k < 0 {
+			return fmt.Errorf("sgf: invalid label LB[%s]", v)
+		}
+		p, err := DecodePoint(v[:k], b.w, b.h)
+		if err != nil || p == nil {
+			return fmt.Errorf("sgf: invalid label LB[%s]", v)
+		}
+		gn.AddMarkup(aigo.MarkLabel, *p, v[k+1:])
+	}
+	for _, p := range n.Properties {
+		if nodeIDs[p.ID] || (root && rootIDs[p.ID]) {
+			continue
+		}
+		if gn.Extra == nil {
+			gn.Extra = map[string][]string{}
+		}
+		gn.Extra[p.ID] = p.Values
+	}
+	return nil
+}
+
+// 把棋谱树转换成 SGF，子节点多于一个时写成变化
+// 根节点的局面不是从空棋盘直接摆出来的时候，把根节点上的所有棋子写成 AB、AW
+func FromGameRecord(r *aigo.GameRecord) *GameTree {
+	gs := r.Root.State
+	w, h := gs.BoardPosition.Width, gs.BoardPosition.Height
+	root := &Node{}
+	root.Set("GM", "1")
+	root.Set("FF", "4")
+	root.Set("CA", "UTF-8")
+	root.Set("SZ", formatSize(w, h))
+	root.Set("KM", strconv.FormatFloat(gs.Rules.Komi, 'f', -1, 64))
+	root.Set("RU", rulesToSGF(gs.Rules))
+	if gs.Handicap > 0 {
+		root.Set("HA", strconv.Itoa(gs.Handicap))
+	}
+	setup := r.Root.Setup
+	if setup == nil || gs.PreviousState == nil || gs.PreviousState.PreviousState != nil {
+		setup = rootPosition(gs)
+	}
+	if setup != nil {
+		writeSetup(root, setup, gs.PlayerTurn, h)
+	}
+	writeNode(root, r.Root, h)
+
+	t := &GameTree{Nodes: []*Node{root}}
+	writeChildren(t, merge(root, r.Root, h), h)
+	return t
+}
+
+// 根节点上的所有棋子；空棋盘、轮到黑棋时返回 nil
+func rootPosition(gs *aigo.GameState) *aigo.Setup {
+	b := gs.BoardPosition
+	setup := &aigo.Setup{}
+	for row := b.Height; row >= 1; row-- {
+		for col := uint16(1); col <= b.Width; col++ {
+			p := aigo.Point{Row: row, Col: col}
+			switch b.Get(p) {
+			case aigo.Black:
+				setup.Black = append(setup.Black, p)
+			case aigo.White:
+				setup.White = append(setup.White, p)
+			}
+		}
+	}
+	if len(setup.Black) == 0 && len(setup.White) == 0 && gs.PlayerTurn == aigo.Black {
+		return nil
+	}
+	return setup
+}
+
+// gn 只有一个子节点时接在同一个序列里，多个子节点时每个写成一个变化
+func writeChildren(t *GameTree, gn *aigo.GameNode, h uint16) {
+	for len(gn.Children) == 1 {
+		var n *Node
+		n, gn = nodeOf(gn.Children[0], h)
+		t.Nodes = append(t.Nodes, n)
+	}
+	for _, c := range gn.Children {
+		n, last := nodeOf(c, h)
+		v := &GameTree{Nodes: []*Node{n}}
+		writeChildren(v, last, h)
+		t.Children = append(t.Children, v)
+	}
+}
+
+// gn 和后面合并进来的节点写成一个 SGF 节点，返回最后一个合并进来的节点
+func nodeOf(gn *aigo.GameNode, h uint16) (*Node, *aigo.GameNode) {
+	n := &Node{}
+	if gn.Setup != nil {
+		writeSetup(n, gn.Setup, gn.State.PlayerTurn, h)
+	}
+	writeNode(n, gn, h)
+	return n, merge(n, gn, h)
+}
+
+// gn 唯一的子节点 SameNode 为 true 时把它的落子、注释和标记写进 n，一直合并下去
+func merge(n *Node, gn *aigo.GameNode, h uint16) *aigo.GameNode {
+	for len(gn.Children) == 1 && gn.Children[0].SameNode {
+		gn = gn.Children[0]
+		writeNode(n, gn, h)
+	}
+	return gn
+}
+
+func writeSetup(n *Node, s *aigo.Setup, next aigo.Player, h uint16) {
+	if len(s.Empty) > 0 {
+		n.Set("AE", encodePoints(s.Empty, h)...)
+	}
+	if len(s.Black) > 0 {
+		n.Set("AB", encodePoints(s.Black, h)...)
+	}
+	if len(s.White) > 0 {
+		n.Set("AW", encodePoints(s.White, h)...)
+	}
+	n.Set("PL", colorID(next))
+}
+
+// 落子、注释、标记和其他属性
+func writeNode(n *Node, gn *aigo.GameNode, h uint16) {
+	if m := gn.Move; m != nil && !m.IsResign {
+		v := ""
+		if m.IsPlay {
+			v = EncodePoint(m.Pnt, h)
+		}
+		n.Set(colorID(gn.Player), v)
+	}
+	if gn.Comment != "" {
+		n.Set("C", gn.Comment)
+	}
+	for _, m := range markupIDs {
+		points := []string{}
+		for _, mk := range gn.Markup {
+			if mk.Kind == m.kind {
+				points = append(points, EncodePoint(mk.Point, h))
+			}
+		}
+		if len(points) > 0 {
+			n.Set(m.id, points...)
+		}
+	}
+	labels := []string{}
+	for _, mk := range gn.Markup {
+		if mk.Kind == aigo.MarkLabel {
+			labels = append(labels, EncodePoint(mk.Point, h)+":"+mk.Label)
+		}
+	}
+	if len(labels) > 0 {
+		n.Set("LB", labels...)
+	}
+	ids := make([]string, 0, len(gn.Extra))
+	for id := range gn.Extra {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !n.Has(id) {
+			n.Set(id, gn.Extra[id]...)
+		}
+	}
+}
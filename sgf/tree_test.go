@@ -0,0 +1,175 @@
+package sgf
+
+import (
+	"ghj1976/aigo"
+	"reflect"
+	"testing"
+)
+
+func readVariations(t *testing.T) (*GameTree, *aigo.GameRecord) {
+	trees, err := ParseString(readFile(t, "testdata/variations.sgf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := trees[0].GameRecord()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trees[0], r
+}
+
+// 两棵树的形状一样，每个节点的属性一样，不管属性的顺序
+func sameTree(a, b *GameTree) bool {
+	if len(a.Nodes) != len(b.Nodes) || len(a.Children) != len(b.Children) {
+		return false
+	}
+	for k := range a.Nodes {
+		if !reflect.DeepEqual(propertyMap(a.Nodes[k]), propertyMap(b.Nodes[k])) {
+			return false
+		}
+	}
+	for k := range a.Children {
+		if !sameTree(a.Children[k], b.Children[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+func propertyMap(n *Node) map[string][]string {
+	m := map[string][]string{}
+	for _, p := range n.Properties {
+		m[p.ID] = p.Values
+	}
+	return m
+}
+
+func TestGameRecordFromSGF(t *testing.T) {
+	_, r := readVariations(t)
+	root := r.Root
+	if root.Comment != "Root comment with a ] bracket" || root.Extra["GN"][0] != "Review game" || root.Extra["PB"][0] != "Black player" {
+		t.Errorf("根节点: %q %v", root.Comment, root.Extra)
+	}
+	if _, ok := root.Extra["SZ"]; ok {
+		t.Errorf("SZ 不应该在 Extra 里: %v", root.Extra)
+	}
+
+	main := r.MainLine()
+	if len(main) != 8 || !main[7].State.IsOver() {
+		t.Fatalf("主线 %d 个节点", len(main))
+	}
+	if n := main[1]; n.Player != aigo.Black || n.Comment != "Center opening" || !reflect.DeepEqual(n.Markup, []aigo.Markup{{Kind: aigo.MarkTriangle, Point: aigo.Point{Row: 5, Col: 5}}}) {
+		t.Errorf("第一步: %+v", n)
+	}
+	want := []aigo.Markup{
+		{Kind: aigo.MarkLabel, Point: aigo.Point{Row: 7, Col: 7}, Label: "A"},
+		{Kind: aigo.MarkLabel, Point: aigo.Point{Row: 3, Col: 3}, Label: "B"},
+	}
+	if n := main[2]; len(n.Children) != 2 || !reflect.DeepEqual(n.Markup, want) {
+		t.Errorf("第二步: %d 个变化 %+v", len(n.Children), n.Markup)
+	}
+	if n := main[5]; n.Player != aigo.White || main[4].Player != aigo.White || len(n.Children) != 2 {
+		t.Errorf("白棋连下两步: %+v", n)
+	}
+
+	// 第二个变化里摆棋
+	edited := main[5].Children[1]
+	if edited.Setup == nil || edited.Comment != "Position edited" || edited.State.PlayerTurn != aigo.Black || edited.State.BoardPosition.Get(aigo.Point{Row: 2, Col: 8}) != aigo.Black {
+		t.Errorf("摆棋节点: %+v", edited)
+	}
+	if n := edited.Children[0]; n.Extra["XX"][0] != "custom" || n.Markup[0].Kind != aigo.MarkSelected {
+		t.Errorf("摆棋以后的落子: %+v", n)
+	}
+
+	// 只有注释的节点，局面不变
+	other := main[2].Children[1]
+	comment := other.Children[0]
+	if comment.Move != nil || comment.Setup != nil || comment.State != other.State || comment.Comment != "Comment only node" {
+		t.Errorf("只有注释的节点: %+v", comment)
+	}
+	if r.Current != r.Root {
+		t.Errorf("游标应该在根节点")
+	}
+}
+
+func TestGameRecordRoundTrip(t *testing.T) {
+	tree, r := readVariations(t)
+	written := FromGameRecord(r)
+	if !sameTree(tree, written) {
+		t.Errorf("写出的棋谱跟原来的不一样:\n%s", written)
+	}
+	again, err := ParseString(written.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := again[0].GameRecord()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := FromGameRecord(r2).String(); s != written.String() {
+		t.Errorf("再转换一次不一样:\n%s\n%s", s, written)
+	}
+
+	// 根节点上的落子、同时有摆棋和落子的节点、同时有黑白落子的节点，写出时节点数和变化的位置不变
+	trees, err := ParseString("(;GM[1]FF[4]CA[UTF-8]SZ[9]KM[7.5]RU[Chinese]B[ee]C[Root move]\n;W[cc]\n(;AB[gg]PL[W]W[gc]C[Setup and move]\n;B[dd])\n(;B[cg]W[gg]))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r3, err := trees[0].GameRecord()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := r3.Root.Children[0]; n.Player != aigo.Black || !n.SameNode || r3.Root.Comment != "Root move" {
+		t.Errorf("根节点上的落子: %+v", n)
+	}
+	if written := FromGameRecord(r3); !sameTree(trees[0], written) {
+		t.Errorf("写出的棋谱跟原来的不一样:\n%s", written)
+	}
+}
+
+func TestGameRecordToSGF(t *testing.T) {
+	gs, err := aigo.NewHandicapGame(9, 2, aigo.JapaneseRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := aigo.NewGameRecord(gs)
+	r.Root.Comment = "Two stones"
+	for _, m := range []aigo.Move{aigo.NewPlay(aigo.Point{Row: 5, Col: 5}), aigo.NewPlay(aigo.Point{Row: 5, Col: 3})} {
+		if _, err := r.Play(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r.Previous()
+	if _, err := r.Play(aigo.NewPlay(aigo.Point{Row: 3, Col: 5})); err != nil {
+		t.Fatal(err)
+	}
+	r.Current.AddMarkup(aigo.MarkCircle, aigo.Point{Row: 3, Col: 5}, "")
+
+	tree := FromGameRecord(r)
+	want := "(;GM[1]FF[4]CA[UTF-8]SZ[9]KM[0.5]RU[Japanese]HA[2]AB[cg][gc]PL[W]C[Two stones]\n;W[ee]\n(;B[ce])\n(;B[eg]CR[eg]))\n"
+	if s := tree.String(); s != want {
+		t.Errorf("写出的棋谱:\n%s\n应该是:\n%s", s, want)
+	}
+	r2, err := tree.GameRecord()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r2.Root.State.ZobristHash128() != gs.ZobristHash128() || r2.Root.State.Handicap != 2 || r2.Root.State.PlayerTurn != aigo.White {
+		t.Errorf("让子的根节点:\n%v", r2.Root.State)
+	}
+	leaf := r2.Root.Children[0].Children[1]
+	if leaf.State.ZobristHash128() != r.Current.State.ZobristHash128() || len(leaf.Markup) != 1 {
+		t.Errorf("变化里的局面:\n%v", leaf.State)
+	}
+
+	// 从对局中间开始的棋谱，根节点写出所有棋子
+	r3 := aigo.NewGameRecord(r.Current.State)
+	tree = FromGameRecord(r3)
+	r4, err := tree.GameRecord()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r4.Root.State.ZobristHash128() != r3.Root.State.ZobristHash128() || r4.Root.State.PlayerTurn != aigo.White {
+		t.Errorf("对局中间的根节点:\n%s", tree)
+	}
+}
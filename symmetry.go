@@ -0,0 +1,238 @@
+package aigo
+
+import (
+	"log"
+)
+
+// 棋盘的对称变换
+// 正方形棋盘有 8 种：4 种旋转，以及左右、上下、两条对角线的翻转。
+// 长方形棋盘只有不改变长宽的 4 种：不变、旋转 180 度、左右翻转、上下翻转。
+// 开局库、训练数据去重和数据增强都要用到。
+type Symmetry byte
+
+const (
+	Identity      Symmetry = iota // 不变
+	Rotate90                      // 顺时针旋转 90 度
+	Rotate180                     // 旋转 180 度
+	Rotate270                     // 顺时针旋转 270 度
+	FlipLeftRight                 // 左右翻转
+	FlipUpDown                    // 上下翻转
+	Transpose                     // 沿左下到右上的对角线翻转，行列互换
+	AntiTranspose                 // 沿左上到右下的对角线翻转
+)
+
+func (s Symmetry) String() string {
+	switch s {
+	case Identity:
+		return "Identity"
+	case Rotate90:
+		return "Rotate90"
+	case Rotate180:
+		return "Rotate180"
+	case Rotate270:
+		return "Rotate270"
+	case FlipLeftRight:
+		return "FlipLeftRight"
+	case FlipUpDown:
+		return "FlipUpDown"
+	case Transpose:
+		return "Transpose"
+	default:
+		return "AntiTranspose"
+	}
+}
+
+// w x h 的棋盘可以使用的对称变换
+func Symmetries(w, h uint16) []Symmetry {
+	if w == h {
+		return []Symmetry{Identity, Rotate90, Rotate180, Rotate270, FlipLeftRight, FlipUpDown, Transpose, AntiTranspose}
+	}
+	return []Symmetry{Identity, Rotate180, FlipLeftRight, FlipUpDown}
+}
+
+// 变换在 w x h 的棋盘上是否可用，会交换长宽的变换只能用于正方形棋盘
+func (s Symmetry) Valid(w, h uint16) bool {
+	switch s {
+	case Identity, Rotate180, FlipLeftRight, FlipUpDown:
+		return true
+	}
+	return w == h
+}
+
+// 逆变换
+func (s Symmetry) Inverse() Symmetry {
+	switch s {
+	case Rotate90:
+		return Rotate270
+	case Rotate270:
+		return Rotate90
+	}
+	return s
+}
+
+// w x h 棋盘上的点变换以后的位置
+// 行号从下往上数，列号从左往右数，和 PrintBoard 的显示一致
+func (s Symmetry) Point(p Point, w, h uint16) Point {
+	switch s {
+	case Rotate90:
+		return Point{Row: w + 1 - p.Col, Col: p.Row}
+	case Rotate180:
+		return Point{Row: h + 1 - p.Row, Col: w + 1 - p.Col}
+	case Rotate270:
+		return Point{Row: p.Col, Col: h + 1 - p.Row}
+	case FlipLeftRight:
+		return Point{Row: p.Row, Col: w + 1 - p.Col}
+	case FlipUpDown:
+		return Point{Row: h + 1 - p.Row, Col: p.Col}
+	case Transpose:
+		return Point{Row: p.Col, Col: p.Row}
+	case AntiTranspose:
+		return Point{Row: w + 1 - p.Col, Col: h + 1 - p.Row}
+	}
+	return p
+}
+
+// w x h 棋盘上的动作变换以后的结果，跳过和认输不变
+func (s Symmetry) Move(m Move, w, h uint16) Move {
+	if m.IsPlay {
+		m.Pnt = s.Point(m.Pnt, w, h)
+	}
+	return m
+}
+
+// 变换不能用于这个大小的棋盘时直接 panic，是调用者的错误
+func (s Symmetry) mustBeValid(w, h uint16) {
+	if !s.Valid(w, h) {
+		log.Panicf("对称变换 %v 不能用于 %dx%d 的棋盘", s, w, h)
+	}
+}
+
+// 返回变换以后的新棋盘
+func (b *Board) Transform(s Symmetry) *Board {
+	s.mustBeValid(b.Width, b.Height)
+	nb := NewBoard(b.Width, b.Height)
+	for i, c := range b.grid {
+		if c != None {
+			// 对称变换不改变相邻关系，不会有提子
+			nb.PlaceStone(c, s.Point(b.point(int32(i)), b.Width, b.Height))
+		}
+	}
+	return nb
+}
+
+// 棋盘按 s 变换以后的 Zobrist 哈希，不需要真的生成棋盘
+func (b *Board) transformedHash(s Symmetry) Hash128 {
+	h := Zobrist.Empty
+	for i, c := range b.grid {
+		if c != None {
+			h = h.Xor(Zobrist.Point(s.Point(b.point(int32(i)), b.Width, b.Height), c))
+		}
+	}
+	return h
+}
+
+// 对称不变的 Zobrist 哈希：所有可用的对称变换里最小的哈希值
+// 同时返回得到这个值的变换，互相对称的棋盘结果相同
+func (b *Board) CanonicalHash() (Hash128, Symmetry) {
+	best, best_s := b.GetZobristHash128(), Identity
+	for _, s := range Symmetries(b.Width, b.Height)[1:] {
+		if h := b.transformedHash(s); hashLess(h, best) {
+			best, best_s = h, s
+		}
+	}
+	return best, best_s
+}
+
+// 对称不变的整个局面的 Zobrist 哈希，包括轮到谁下和劫的禁着点
+func (gs *GameState) CanonicalZobristHash() (Hash128, Symmetry) {
+	b := gs.BoardPosition
+	var best Hash128
+	best_s := Identity
+	for k, s := range Symmetries(b.Width, b.Height) {
+		h := b.transformedHash(s)
+		if gs.PlayerTurn == White {
+			h = h.Xor(Zobrist.WhiteToMove)
+		}
+		if gs.KoPoint != nil {
+			h = h.Xor(Zobrist.KoPoint(s.Point(*gs.KoPoint, b.Width, b.Height)))
+		}
+		if k == 0 || hashLess(h, best) {
+			best, best_s = h, s
+		}
+	}
+	return best, best_s
+}
+
+// 比较两个哈希值，先比高 64 位
+func hashLess(a, b Hash128) bool {
+	if a.Hi != b.Hi {
+		return a.Hi < b.Hi
+	}
+	return a.Lo < b.Lo
+}
+
+// 返回整盘棋按 s 变换以后的 GameState，包括所有的历史局面
+// 从最初的局面开始，按变换以后的动作和摆棋重新下一遍，劫和哈希历史都重新计算
+// 历史要是 ApplyMove / ApplySetup 得到的，原地 Play 的历史和当前局面共用棋盘，不能变换
+func (gs *GameState) Transform(s Symmetry) *GameState {
+	w, h := gs.BoardPosition.Width, gs.BoardPosition.Height
+	s.mustBeValid(w, h)
+
+	history := []*GameState{}
+	for e := gs; e != nil; e = e.PreviousState {
+		history = append(history, e)
+	}
+	root := history[len(history)-1]
+	ngs := &GameState{}
+	*ngs = *root
+	ngs.BoardPosition = root.BoardPosition.Transform(s)
+	ngs.PreviousZobristHashStateArr = []int64{ngs.BoardPosition.GetZobristHash()}
+	ngs.PreviousSituationHashArr = []int64{situationHash(ngs.BoardPosition.GetZobristHash(), ngs.PlayerTurn)}
+	ngs.KoPoint = transformPointPtr(s, root.KoPoint, w, h)
+	ngs.Setup = transformSetup(s, root.Setup, w, h)
+
+	for k := len(history) - 2; k >= 0; k-- {
+		e := history[k]
+		var err error
+		if e.Setup != nil {
+			ngs, err = ngs.ApplySetup(*transformSetup(s, e.Setup, w, h), e.PlayerTurn)
+		} else if e.LastMove != nil {
+			ngs, err = ngs.ApplyMove(s.Move(*e.LastMove, w, h))
+		} else {
+			ngs = NewGameState(e.BoardPosition.Transform(s), e.PlayerTurn, ngs, nil)
+		}
+		if err != nil {
+			log.Panicf("对称变换以后重新下棋出错: %v", err)
+		}
+		// 贴目、让子数这些不是下出来的，照搬原来的
+		ngs.Rules = e.Rules
+		ngs.Handicap = e.Handicap
+		ngs.Prisoners = e.Prisoners
+	}
+	return ngs
+}
+
+func transformPointPtr(s Symmetry, p *Point, w, h uint16) *Point {
+	if p == nil {
+		return nil
+	}
+	np := s.Point(*p, w, h)
+	return &np
+}
+
+func transformSetup(s Symmetry, setup *Setup, w, h uint16) *Setup {
+	if setup == nil {
+		return nil
+	}
+	ns := &Setup{}
+	for _, p := range setup.Black {
+		ns.Black = append(ns.Black, s.Point(p, w, h))
+	}
+	for _, p := range setup.White {
+		ns.White = append(ns.White, s.Point(p, w, h))
+	}
+	for _, p := range setup.Empty {
+		ns.Empty = append(ns.Empty, s.Point(p, w, h))
+	}
+	return ns
+}
@@ -0,0 +1,142 @@
+package aigo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// 用 ApplyMove 随机下一盘棋，中间夹着跳过
+func randomHistory(t *testing.T, w, h uint16, moves int, seed int64) *GameState {
+	rnd := rand.New(rand.NewSource(seed))
+	game := NewGameWithRules(w, h, JapaneseRules)
+	for k := 0; k < moves; k++ {
+		m := NewPass()
+		for try := 0; try < 20; try++ {
+			p := NewPlay(Point{Row: uint16(rnd.Intn(int(h))) + 1, Col: uint16(rnd.Intn(int(w))) + 1})
+			if game.IsValidMove(p) {
+				m = p
+				break
+			}
+		}
+		var err error
+		if game, err = game.ApplyMove(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return game
+}
+
+func TestSymmetryPoint(t *testing.T) {
+	p := Point{Row: 2, Col: 3}
+	seen := map[Point]bool{}
+	for _, s := range Symmetries(9, 9) {
+		q := s.Point(p, 9, 9)
+		seen[q] = true
+		if s.Inverse().Point(q, 9, 9) != p {
+			t.Errorf("%v 的逆变换不对", s)
+		}
+	}
+	if len(seen) != 8 {
+		t.Errorf("8 种变换的结果应该都不同: %v", seen)
+	}
+	if q := Rotate90.Point(Point{Row: 9, Col: 1}, 9, 9); q != (Point{Row: 9, Col: 9}) {
+		t.Errorf("左上角顺时针旋转以后应该在右上角: %v", q)
+	}
+	if m := Rotate90.Move(NewPass(), 9, 9); !m.IsPass {
+		t.Error("跳过变换以后还是跳过")
+	}
+
+	// 长方形棋盘只有 4 种变换
+	if n := len(Symmetries(7, 11)); n != 4 {
+		t.Errorf("长方形棋盘应该有 4 种变换: %d", n)
+	}
+	for _, s := range []Symmetry{Rotate90, Rotate270, Transpose, AntiTranspose} {
+		if s.Valid(7, 11) || !s.Valid(11, 11) {
+			t.Errorf("%v 只能用于正方形棋盘", s)
+		}
+	}
+	b := NewBoard(7, 11)
+	for _, s := range Symmetries(7, 11) {
+		q := s.Point(Point{Row: 11, Col: 2}, 7, 11)
+		if !b.IsOnGrid(q) || s.Inverse().Point(q, 7, 11) != (Point{Row: 11, Col: 2}) {
+			t.Errorf("%v 变换到了 %v", s, q)
+		}
+	}
+}
+
+func TestSymmetryBoard(t *testing.T) {
+	for _, size := range [][2]uint16{{9, 9}, {7, 11}} {
+		game := randomHistory(t, size[0], size[1], 60, 1)
+		b := game.BoardPosition
+		key, _ := b.CanonicalHash()
+		for _, s := range Symmetries(b.Width, b.Height) {
+			nb := b.Transform(s)
+			if nb.GetZobristHash128() != nb.RecomputeZobristHash() || nb.GetZobristHash128() != b.transformedHash(s) {
+				t.Errorf("%v 变换以后的哈希不对", s)
+			}
+			if !nb.Transform(s.Inverse()).Equal(b) {
+				t.Errorf("%v 再做逆变换应该还原", s)
+			}
+			if k, sym := nb.CanonicalHash(); k != key || nb.Transform(sym).GetZobristHash128() != key {
+				t.Errorf("%v 变换以后的规范哈希不同", s)
+			}
+			if len(nb.GetAllStoneGroups()) != len(b.GetAllStoneGroups()) {
+				t.Errorf("%v 变换以后棋链数不同", s)
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("长方形棋盘不能旋转 90 度")
+		}
+	}()
+	NewBoard(7, 11).Transform(Rotate90)
+}
+
+func TestSymmetryGameState(t *testing.T) {
+	game := randomHistory(t, 9, 9, 80, 2)
+	key, _ := game.CanonicalZobristHash()
+	for _, s := range Symmetries(9, 9) {
+		ngs := game.Transform(s)
+		if k, _ := ngs.CanonicalZobristHash(); k != key {
+			t.Errorf("%v 变换以后的规范哈希不同", s)
+		}
+		if ngs.Prisoners != game.Prisoners || ngs.PlayerTurn != game.PlayerTurn {
+			t.Errorf("%v 变换以后提子数或轮到谁下不同", s)
+		}
+		if len(ngs.PreviousZobristHashStateArr) != len(game.PreviousZobristHashStateArr) {
+			t.Errorf("%v 变换以后哈希历史长度不同", s)
+		}
+		// 每一步的棋盘、动作、劫都对应
+		e, ne := game, ngs
+		for e != nil {
+			if ne == nil || !e.BoardPosition.Transform(s).Equal(ne.BoardPosition) {
+				t.Fatalf("%v 变换以后历史局面不对", s)
+			}
+			if (e.LastMove == nil) != (ne.LastMove == nil) || (e.LastMove != nil && s.Move(*e.LastMove, 9, 9) != *ne.LastMove) {
+				t.Fatalf("%v 变换以后的动作不对: %v %v", s, e.LastMove, ne.LastMove)
+			}
+			if (e.KoPoint == nil) != (ne.KoPoint == nil) || (e.KoPoint != nil && s.Point(*e.KoPoint, 9, 9) != *ne.KoPoint) {
+				t.Fatalf("%v 变换以后劫的位置不对", s)
+			}
+			e, ne = e.PreviousState, ne.PreviousState
+		}
+		if ne != nil {
+			t.Errorf("%v 变换以后历史长度不同", s)
+		}
+	}
+
+	// 让子棋的摆棋和贴目也跟着变换
+	handicap, err := NewHandicapGame(9, 3, ChineseRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ngs := handicap.Transform(FlipLeftRight)
+	if ngs.Setup == nil || len(ngs.Setup.Black) != 3 || ngs.Rules.Komi != handicap.Rules.Komi || ngs.Handicap != 3 {
+		t.Errorf("让子棋变换以后不对: %+v", ngs)
+	}
+	if !handicap.BoardPosition.Transform(FlipLeftRight).Equal(ngs.BoardPosition) {
+		t.Error("让子的棋盘变换以后不对")
+	}
+}
@@ -0,0 +1,135 @@
+package aigo
+
+// 战术查询：打吃、提子、逃子、自己送吃、倒扑
+// 给随机走子策略、着法排序、局面评估之类的地方用。
+// Board 上的查询只看棋子，不考虑劫争；GameState 上的查询只返回合法的动作。
+// 查询直接在棋盘上 Play / Undo，查完以后棋盘恢复原样。
+
+// color 一方所有被打吃（只剩一口气）的棋链
+func (b *Board) ChainsInAtari(color Player) []*StoneGroup {
+	v := []*StoneGroup{}
+	for i, e := range b.grid {
+		if e == color && b.head[i] == int32(i) && b.libs[i] == 1 {
+			v = append(v, b.stoneGroupAt(int32(i)))
+		}
+	}
+	return v
+}
+
+// color 一方可以提掉对方棋子的点，也就是对方被打吃的棋链的气
+func (b *Board) CaptureMoves(color Player) []Point {
+	moves := []int32{}
+	for _, sg := range b.ChainsInAtari(color.Other()) {
+		moves = appendUniqueInt32(moves, b.index(sg.Liberties[0]))
+	}
+	return b.points(moves)
+}
+
+// 救 p 处被打吃的棋链的点：长出气来，或者提掉相邻的对方棋子，走完以后至少有两口气
+// p 处没有棋子或者棋链不止一口气时返回 nil
+func (b *Board) SavingMoves(p Point) []Point {
+	if b.LibertyCount(p) != 1 {
+		return nil
+	}
+	s := b.index(p)
+	color := b.grid[s]
+	r := &ladderReader{b: b, color: color}
+	moves := []int32{}
+	for _, m := range r.defenses(b.head[s]) {
+		if b.isSelfCapture(color, b.point(m)) {
+			continue
+		}
+		c, _ := b.Play(color, b.point(m))
+		if b.libs[b.head[s]] >= 2 {
+			moves = append(moves, m)
+		}
+		b.Undo(c)
+	}
+	return b.points(moves)
+}
+
+// color 在 p 落子以后自己的棋链是否只剩一口气
+// 提子以后还只有一口气的也算，比如提劫；p 不能落子（有子、自杀）时返回 false
+func (b *Board) IsSelfAtari(color Player, p Point) bool {
+	if !b.IsOnGrid(p) || b.Get(p) != None || b.isSelfCapture(color, p) {
+		return false
+	}
+	c, _ := b.Play(color, p)
+	atari := b.libs[b.head[b.index(p)]] == 1
+	b.Undo(c)
+	return atari
+}
+
+// color 在 p 落子是否是倒扑：
+// 落子后自己只剩一口气，对方提掉以后，提子的棋链也只剩一口气，而且不止一颗子，可以马上反提
+// 反提只有一颗子时是劫，不算倒扑
+func (b *Board) IsSnapback(color Player, p Point) bool {
+	if !b.IsSelfAtari(color, p) {
+		return false
+	}
+	i := b.index(p)
+	c1, _ := b.Play(color, p)
+	defer b.Undo(c1)
+	if len(c1.Captured) > 0 { // 自己提了子，对方提回来是劫争
+		return false
+	}
+	l := b.stoneGroupAt(b.head[i]).Liberties[0]
+	if b.isSelfCapture(color.Other(), l) {
+		return false
+	}
+	c2, _ := b.Play(color.Other(), l)
+	defer b.Undo(c2)
+	h := b.head[b.index(l)]
+	return len(c2.Captured) > 0 && b.libs[h] == 1 && b.size[h] > 1
+}
+
+// 下标转换成交叉点
+func (b *Board) points(list []int32) []Point {
+	v := make([]Point, 0, len(list))
+	for _, e := range list {
+		v = append(v, b.point(e))
+	}
+	return v
+}
+
+// 当前轮到的一方被打吃的棋链
+func (gs *GameState) ChainsInAtari() []*StoneGroup {
+	return gs.BoardPosition.ChainsInAtari(gs.PlayerTurn)
+}
+
+// 当前轮到的一方可以提子的合法动作
+func (gs *GameState) CaptureMoves() []Move {
+	return gs.legalPlays(gs.BoardPosition.CaptureMoves(gs.PlayerTurn))
+}
+
+// 当前轮到的一方救自己被打吃的棋链的合法动作
+func (gs *GameState) SavingMoves() []Move {
+	list := []Point{}
+	for _, sg := range gs.ChainsInAtari() {
+		for _, p := range gs.BoardPosition.SavingMoves(sg.Stones[0]) {
+			list = pointArrUpdate(list, p)
+		}
+	}
+	return gs.legalPlays(list)
+}
+
+// 当前轮到的一方下这一步是否自己送吃
+func (gs *GameState) IsSelfAtari(move Move) bool {
+	return move.IsPlay && gs.BoardPosition.IsSelfAtari(gs.PlayerTurn, move.Pnt)
+}
+
+// 当前轮到的一方下这一步是否是倒扑
+func (gs *GameState) IsSnapback(move Move) bool {
+	return move.IsPlay && gs.BoardPosition.IsSnapback(gs.PlayerTurn, move.Pnt)
+}
+
+// 把点里合法的落子挑出来
+func (gs *GameState) legalPlays(list []Point) []Move {
+	moves := []Move{}
+	for _, p := range list {
+		if m := NewPlay(p); gs.IsValidMove(m) {
+			moves = append(moves, m)
+		}
+	}
+	return moves
+}
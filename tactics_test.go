@@ -0,0 +1,121 @@
+package aigo
+
+import (
+	"testing"
+)
+
+func TestAtariQueries(t *testing.T) {
+	game := gameFromDiagram(t, ChineseRules, White,
+		".....",
+		".O...",
+		"OX...",
+		".O.X.",
+		"...XO",
+	)
+	b := game.BoardPosition
+	black, white := b.ChainsInAtari(Black), b.ChainsInAtari(White)
+	if len(black) != 1 || black[0].Stones[0] != (Point{Row: 3, Col: 2}) {
+		t.Errorf("黑棋被打吃的棋链不对: %v", black)
+	}
+	if len(white) != 1 || white[0].Stones[0] != (Point{Row: 1, Col: 5}) {
+		t.Errorf("白棋被打吃的棋链不对: %v", white)
+	}
+	if moves := b.CaptureMoves(White); !PointSliceEqualBCE(moves, []Point{{Row: 3, Col: 3}}) {
+		t.Errorf("白棋提子的点不对: %v", moves)
+	}
+	if moves := b.CaptureMoves(Black); !PointSliceEqualBCE(moves, []Point{{Row: 2, Col: 5}}) {
+		t.Errorf("黑棋提子的点不对: %v", moves)
+	}
+	if moves := b.SavingMoves(Point{Row: 3, Col: 2}); !PointSliceEqualBCE(moves, []Point{{Row: 3, Col: 3}}) {
+		t.Errorf("黑棋逃子的点不对: %v", moves)
+	}
+	if moves := b.SavingMoves(Point{Row: 1, Col: 5}); len(moves) != 0 {
+		t.Errorf("白棋长出去也只有一口气: %v", moves)
+	}
+	if moves := b.SavingMoves(Point{Row: 4, Col: 2}); moves != nil {
+		t.Errorf("没有被打吃: %v", moves)
+	}
+	if moves := game.CaptureMoves(); len(moves) != 1 || moves[0] != NewPlay(Point{Row: 3, Col: 3}) {
+		t.Errorf("轮到白棋，提子的动作不对: %v", moves)
+	}
+	if !game.IsSelfAtari(NewPlay(Point{Row: 2, Col: 5})) {
+		t.Error("白棋长出去是自己送吃")
+	}
+	if game.IsSelfAtari(NewPlay(Point{Row: 3, Col: 3})) || game.IsSelfAtari(NewPass()) {
+		t.Error("提子不是送吃")
+	}
+}
+
+func TestSavingMovesByCapture(t *testing.T) {
+	game := gameFromDiagram(t, ChineseRules, Black,
+		".....",
+		"OX...",
+		"XO...",
+		".....",
+		".....",
+	)
+	moves := game.SavingMoves()
+	want := []Move{NewPlay(Point{Row: 2, Col: 1}), NewPlay(Point{Row: 5, Col: 1})}
+	if len(moves) != 2 || moves[0] != want[0] || moves[1] != want[1] {
+		t.Errorf("长出和提子都能救: %v", moves)
+	}
+	if game.IsSelfAtari(NewPlay(Point{Row: 5, Col: 1})) {
+		t.Error("提子以后有两口气")
+	}
+	if !game.BoardPosition.IsSelfAtari(White, Point{Row: 5, Col: 1}) {
+		t.Error("白棋长出去只有一口气")
+	}
+	if game.BoardPosition.IsSelfAtari(White, Point{Row: 2, Col: 1}) {
+		t.Error("白棋提子以后有三口气")
+	}
+}
+
+func TestSnapback(t *testing.T) {
+	game := gameFromDiagram(t, ChineseRules, Black,
+		".....",
+		".....",
+		"XXXXX",
+		"XOOOX",
+		"XO..X",
+	)
+	before := game.BoardPosition.Copy()
+	if !game.IsSnapback(NewPlay(Point{Row: 1, Col: 3})) {
+		t.Error("扑进去是倒扑")
+	}
+	if !game.IsSelfAtari(NewPlay(Point{Row: 1, Col: 3})) {
+		t.Error("倒扑也是送吃")
+	}
+	if game.IsSnapback(NewPlay(Point{Row: 1, Col: 4})) {
+		t.Error("直接紧气不是倒扑")
+	}
+	if !game.BoardPosition.Equal(before) || game.BoardPosition.GetZobristHash() != before.GetZobristHash() {
+		t.Error("查询以后棋盘应该恢复原样")
+	}
+
+	// 反提只有一颗子是劫，不是倒扑
+	game = gameFromDiagram(t, ChineseRules, White,
+		".....",
+		".....",
+		".XO..",
+		"X.XO.",
+		".XO..",
+	)
+	if game.IsSnapback(NewPlay(Point{Row: 2, Col: 2})) {
+		t.Error("提劫不是倒扑")
+	}
+
+	// 扑进去没有提子，对方提掉以后只有一颗子能反提，是劫，不是倒扑
+	game = gameFromDiagram(t, ChineseRules, Black,
+		".....",
+		".....",
+		".....",
+		".XO..",
+		"X..O.",
+	)
+	if !game.IsSelfAtari(NewPlay(Point{Row: 1, Col: 3})) {
+		t.Fatal("扑进去是送吃")
+	}
+	if game.IsSnapback(NewPlay(Point{Row: 1, Col: 3})) {
+		t.Error("对方提了以后成劫，不是倒扑")
+	}
+}
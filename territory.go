@@ -8,4 +8,10 @@ type Territory struct {
 	NumWhiteStones    int     // 白子已占位置数量
 	NumDame           int     // 中性点数量
 	DamePoints        []Point // 中性点
+
+	// 下面的只有 EvaluateTerritoryWithSeki 填写
+	SekiGroups       []*StoneGroup // 双活的棋链
+	SharedLiberties  []Point       // 双活的公气，也算在中性点里
+	NumBlackSekiEyes int           // 黑色地盘里双活棋链的眼，日本规则不算地盘
+	NumWhiteSekiEyes int           // 白色地盘里双活棋链的眼
 }
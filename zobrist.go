@@ -1,1095 +1,84 @@
-﻿package aigo
+package aigo
 
-var (
-	EmptyBoardHashCode = int64(6279681989359485406)
-	BoardPointHashCode = MapInit()
+import "log"
+
+// Zobrist 哈希表
+// 以前是用 chapter_3.5 生成、粘贴进来的 19x19 表，现在用固定种子的伪随机数在启动时生成，
+// 每次运行、每台机器上得到的哈希值都一样，最大支持 25x25 的棋盘。
+
+const (
+	MaxBoardSize = 25                 // 支持的最大棋盘边长
+	ZobristSeed  = 0x5A6F627269737421 // 默认哈希表的随机数种子
 )
 
-func MapInit() map[BoardPoint]int64 {
-	mappp := make(map[BoardPoint]int64)
-	mappp[NewBoardPoint(1, 1, None)] = int64(4745027563703798683)
-	mappp[NewBoardPoint(1, 1, Black)] = int64(4201522796577779614)
-	mappp[NewBoardPoint(1, 1, White)] = int64(7974314918488071952)
-	mappp[NewBoardPoint(1, 2, None)] = int64(7014202626380312576)
-	mappp[NewBoardPoint(1, 2, Black)] = int64(2983767065062920609)
-	mappp[NewBoardPoint(1, 2, White)] = int64(6065211242926951418)
-	mappp[NewBoardPoint(1, 3, None)] = int64(4359095258252657003)
-	mappp[NewBoardPoint(1, 3, Black)] = int64(4686468341060737352)
-	mappp[NewBoardPoint(1, 3, White)] = int64(1625700003723487398)
-	mappp[NewBoardPoint(1, 4, None)] = int64(595520103670172106)
-	mappp[NewBoardPoint(1, 4, Black)] = int64(3579172913248043915)
-	mappp[NewBoardPoint(1, 4, White)] = int64(4070004725235588646)
-	mappp[NewBoardPoint(1, 5, None)] = int64(4229313279216719445)
-	mappp[NewBoardPoint(1, 5, Black)] = int64(2995183741306960625)
-	mappp[NewBoardPoint(1, 5, White)] = int64(7992008674753203261)
-	mappp[NewBoardPoint(1, 6, None)] = int64(7469298467298773232)
-	mappp[NewBoardPoint(1, 6, Black)] = int64(3515613170377055826)
-	mappp[NewBoardPoint(1, 6, White)] = int64(5923441758456319046)
-	mappp[NewBoardPoint(1, 7, None)] = int64(3689859570119044771)
-	mappp[NewBoardPoint(1, 7, Black)] = int64(2831406439381172842)
-	mappp[NewBoardPoint(1, 7, White)] = int64(975391005099942199)
-	mappp[NewBoardPoint(1, 8, None)] = int64(3932633564115673122)
-	mappp[NewBoardPoint(1, 8, Black)] = int64(6892355772192421239)
-	mappp[NewBoardPoint(1, 8, White)] = int64(7651651567826436377)
-	mappp[NewBoardPoint(1, 9, None)] = int64(8968657536238028603)
-	mappp[NewBoardPoint(1, 9, Black)] = int64(9114953397565969667)
-	mappp[NewBoardPoint(1, 9, White)] = int64(6290166753877431804)
-	mappp[NewBoardPoint(1, 10, None)] = int64(6747600440902136547)
-	mappp[NewBoardPoint(1, 10, Black)] = int64(4394374687054444441)
-	mappp[NewBoardPoint(1, 10, White)] = int64(8814393880961644704)
-	mappp[NewBoardPoint(1, 11, None)] = int64(8380099164184090540)
-	mappp[NewBoardPoint(1, 11, Black)] = int64(648667417938250783)
-	mappp[NewBoardPoint(1, 11, White)] = int64(2369372755223327738)
-	mappp[NewBoardPoint(1, 12, None)] = int64(3069430390750998472)
-	mappp[NewBoardPoint(1, 12, Black)] = int64(7468769415605922914)
-	mappp[NewBoardPoint(1, 12, White)] = int64(4867871095899427588)
-	mappp[NewBoardPoint(1, 13, None)] = int64(4469981885973266937)
-	mappp[NewBoardPoint(1, 13, Black)] = int64(3758576357935083523)
-	mappp[NewBoardPoint(1, 13, White)] = int64(3889664380759047000)
-	mappp[NewBoardPoint(1, 14, None)] = int64(1927861948068707563)
-	mappp[NewBoardPoint(1, 14, Black)] = int64(7272513128165961028)
-	mappp[NewBoardPoint(1, 14, White)] = int64(6530252949721145301)
-	mappp[NewBoardPoint(1, 15, None)] = int64(5642127006694331390)
-	mappp[NewBoardPoint(1, 15, Black)] = int64(1932318817127082765)
-	mappp[NewBoardPoint(1, 15, White)] = int64(7615521888207510967)
-	mappp[NewBoardPoint(1, 16, None)] = int64(4395452780294390623)
-	mappp[NewBoardPoint(1, 16, Black)] = int64(1803181896892362491)
-	mappp[NewBoardPoint(1, 16, White)] = int64(7821453142269068777)
-	mappp[NewBoardPoint(1, 17, None)] = int64(6175036080421092981)
-	mappp[NewBoardPoint(1, 17, Black)] = int64(6020566562957342487)
-	mappp[NewBoardPoint(1, 17, White)] = int64(3211807397649403428)
-	mappp[NewBoardPoint(1, 18, None)] = int64(7412082552761762170)
-	mappp[NewBoardPoint(1, 18, Black)] = int64(7943725863095177286)
-	mappp[NewBoardPoint(1, 18, White)] = int64(387671352514788279)
-	mappp[NewBoardPoint(1, 19, None)] = int64(822614688232994551)
-	mappp[NewBoardPoint(1, 19, Black)] = int64(8957828355649532702)
-	mappp[NewBoardPoint(1, 19, White)] = int64(6776272275436498798)
-	mappp[NewBoardPoint(2, 1, None)] = int64(4888863184963737591)
-	mappp[NewBoardPoint(2, 1, Black)] = int64(2824740823584748413)
-	mappp[NewBoardPoint(2, 1, White)] = int64(7140593025232239072)
-	mappp[NewBoardPoint(2, 2, None)] = int64(2814667903114150245)
-	mappp[NewBoardPoint(2, 2, Black)] = int64(7474677986936255738)
-	mappp[NewBoardPoint(2, 2, White)] = int64(137087419060673266)
-	mappp[NewBoardPoint(2, 3, None)] = int64(7478294922380023753)
-	mappp[NewBoardPoint(2, 3, Black)] = int64(4038797104352351696)
-	mappp[NewBoardPoint(2, 3, White)] = int64(668274760992974309)
-	mappp[NewBoardPoint(2, 4, None)] = int64(1668249052015396723)
-	mappp[NewBoardPoint(2, 4, Black)] = int64(7622727042258923100)
-	mappp[NewBoardPoint(2, 4, White)] = int64(946939168839091700)
-	mappp[NewBoardPoint(2, 5, None)] = int64(7740207667764089662)
-	mappp[NewBoardPoint(2, 5, Black)] = int64(8053188188943081066)
-	mappp[NewBoardPoint(2, 5, White)] = int64(2780984688637279292)
-	mappp[NewBoardPoint(2, 6, None)] = int64(3641215426915547012)
-	mappp[NewBoardPoint(2, 6, Black)] = int64(8219015196258839771)
-	mappp[NewBoardPoint(2, 6, White)] = int64(6797446452719952346)
-	mappp[NewBoardPoint(2, 7, None)] = int64(2378578152776963135)
-	mappp[NewBoardPoint(2, 7, Black)] = int64(7834324279043217337)
-	mappp[NewBoardPoint(2, 7, White)] = int64(7386200599279256561)
-	mappp[NewBoardPoint(2, 8, None)] = int64(5452578754214995754)
-	mappp[NewBoardPoint(2, 8, Black)] = int64(849489695075123795)
-	mappp[NewBoardPoint(2, 8, White)] = int64(2256753766633582605)
-	mappp[NewBoardPoint(2, 9, None)] = int64(7532515150630946332)
-	mappp[NewBoardPoint(2, 9, Black)] = int64(6690931780458204502)
-	mappp[NewBoardPoint(2, 9, White)] = int64(6275503620437825064)
-	mappp[NewBoardPoint(2, 10, None)] = int64(2424872206825167145)
-	mappp[NewBoardPoint(2, 10, Black)] = int64(4153649262452898867)
-	mappp[NewBoardPoint(2, 10, White)] = int64(1179326541421175848)
-	mappp[NewBoardPoint(2, 11, None)] = int64(333351103184029317)
-	mappp[NewBoardPoint(2, 11, Black)] = int64(2069783374451315649)
-	mappp[NewBoardPoint(2, 11, White)] = int64(3653040205841960557)
-	mappp[NewBoardPoint(2, 12, None)] = int64(3358565958864357082)
-	mappp[NewBoardPoint(2, 12, Black)] = int64(5058769267805604705)
-	mappp[NewBoardPoint(2, 12, White)] = int64(8957282781938775263)
-	mappp[NewBoardPoint(2, 13, None)] = int64(1318242329854289196)
-	mappp[NewBoardPoint(2, 13, Black)] = int64(7532301767102137552)
-	mappp[NewBoardPoint(2, 13, White)] = int64(9197879869440419026)
-	mappp[NewBoardPoint(2, 14, None)] = int64(2503662947948352307)
-	mappp[NewBoardPoint(2, 14, Black)] = int64(4688161554899195047)
-	mappp[NewBoardPoint(2, 14, White)] = int64(2176696175506660694)
-	mappp[NewBoardPoint(2, 15, None)] = int64(2402880338909613043)
-	mappp[NewBoardPoint(2, 15, Black)] = int64(54919773418940362)
-	mappp[NewBoardPoint(2, 15, White)] = int64(5067852929027945278)
-	mappp[NewBoardPoint(2, 16, None)] = int64(1396310877100488420)
-	mappp[NewBoardPoint(2, 16, Black)] = int64(5148301442373939389)
-	mappp[NewBoardPoint(2, 16, White)] = int64(8143561828285592189)
-	mappp[NewBoardPoint(2, 17, None)] = int64(2882837355399585513)
-	mappp[NewBoardPoint(2, 17, Black)] = int64(7078542689803420136)
-	mappp[NewBoardPoint(2, 17, White)] = int64(9217419567856355797)
-	mappp[NewBoardPoint(2, 18, None)] = int64(5774659984938459420)
-	mappp[NewBoardPoint(2, 18, Black)] = int64(130043619182419081)
-	mappp[NewBoardPoint(2, 18, White)] = int64(2867980824614132431)
-	mappp[NewBoardPoint(2, 19, None)] = int64(140101235696727893)
-	mappp[NewBoardPoint(2, 19, Black)] = int64(8466771630031851919)
-	mappp[NewBoardPoint(2, 19, White)] = int64(5261001592517647427)
-	mappp[NewBoardPoint(3, 1, None)] = int64(2535578054016901942)
-	mappp[NewBoardPoint(3, 1, Black)] = int64(567532331142814579)
-	mappp[NewBoardPoint(3, 1, White)] = int64(7535672424416333946)
-	mappp[NewBoardPoint(3, 2, None)] = int64(2304285255903827494)
-	mappp[NewBoardPoint(3, 2, Black)] = int64(4088286528608132587)
-	mappp[NewBoardPoint(3, 2, White)] = int64(2368304614858946411)
-	mappp[NewBoardPoint(3, 3, None)] = int64(6421728055054684266)
-	mappp[NewBoardPoint(3, 3, Black)] = int64(7754624022058425127)
-	mappp[NewBoardPoint(3, 3, White)] = int64(1171154856376879422)
-	mappp[NewBoardPoint(3, 4, None)] = int64(7898136554576807431)
-	mappp[NewBoardPoint(3, 4, Black)] = int64(4959012922994871141)
-	mappp[NewBoardPoint(3, 4, White)] = int64(6116907608907397119)
-	mappp[NewBoardPoint(3, 5, None)] = int64(3543201146705796971)
-	mappp[NewBoardPoint(3, 5, Black)] = int64(1233359791014283118)
-	mappp[NewBoardPoint(3, 5, White)] = int64(2840027472116805299)
-	mappp[NewBoardPoint(3, 6, None)] = int64(7138666877940867947)
-	mappp[NewBoardPoint(3, 6, Black)] = int64(5690216915788660500)
-	mappp[NewBoardPoint(3, 6, White)] = int64(2003910846894646644)
-	mappp[NewBoardPoint(3, 7, None)] = int64(2491495452397851872)
-	mappp[NewBoardPoint(3, 7, Black)] = int64(4166701239531232781)
-	mappp[NewBoardPoint(3, 7, White)] = int64(6030856239194713151)
-	mappp[NewBoardPoint(3, 8, None)] = int64(4060035983772020449)
-	mappp[NewBoardPoint(3, 8, Black)] = int64(8091489327749721143)
-	mappp[NewBoardPoint(3, 8, White)] = int64(8614555776928669979)
-	mappp[NewBoardPoint(3, 9, None)] = int64(175573219581678189)
-	mappp[NewBoardPoint(3, 9, Black)] = int64(4432585297505092381)
-	mappp[NewBoardPoint(3, 9, White)] = int64(8606776351344533871)
-	mappp[NewBoardPoint(3, 10, None)] = int64(8994952800967751657)
-	mappp[NewBoardPoint(3, 10, Black)] = int64(2133601020021013271)
-	mappp[NewBoardPoint(3, 10, White)] = int64(5992337959002734325)
-	mappp[NewBoardPoint(3, 11, None)] = int64(4311164194236470796)
-	mappp[NewBoardPoint(3, 11, Black)] = int64(1816555425575501672)
-	mappp[NewBoardPoint(3, 11, White)] = int64(7123429859814809030)
-	mappp[NewBoardPoint(3, 12, None)] = int64(9133023327398372620)
-	mappp[NewBoardPoint(3, 12, Black)] = int64(197072841432412784)
-	mappp[NewBoardPoint(3, 12, White)] = int64(585714852965961435)
-	mappp[NewBoardPoint(3, 13, None)] = int64(1858180899396173802)
-	mappp[NewBoardPoint(3, 13, Black)] = int64(8936966795050972937)
-	mappp[NewBoardPoint(3, 13, White)] = int64(254869944566113631)
-	mappp[NewBoardPoint(3, 14, None)] = int64(1862164419728466109)
-	mappp[NewBoardPoint(3, 14, Black)] = int64(3976109930082859491)
-	mappp[NewBoardPoint(3, 14, White)] = int64(3901128646585265184)
-	mappp[NewBoardPoint(3, 15, None)] = int64(3097090621830569435)
-	mappp[NewBoardPoint(3, 15, Black)] = int64(8644683005649366912)
-	mappp[NewBoardPoint(3, 15, White)] = int64(1895077605133820569)
-	mappp[NewBoardPoint(3, 16, None)] = int64(8099751376051001558)
-	mappp[NewBoardPoint(3, 16, Black)] = int64(6967754527765603752)
-	mappp[NewBoardPoint(3, 16, White)] = int64(3141400501320373597)
-	mappp[NewBoardPoint(3, 17, None)] = int64(2490790637125740234)
-	mappp[NewBoardPoint(3, 17, Black)] = int64(3788903766090887348)
-	mappp[NewBoardPoint(3, 17, White)] = int64(3648128756388482210)
-	mappp[NewBoardPoint(3, 18, None)] = int64(2845673109936861677)
-	mappp[NewBoardPoint(3, 18, Black)] = int64(1696463465746942724)
-	mappp[NewBoardPoint(3, 18, White)] = int64(3350168236394309623)
-	mappp[NewBoardPoint(3, 19, None)] = int64(4978446511725149476)
-	mappp[NewBoardPoint(3, 19, Black)] = int64(2062878486668984187)
-	mappp[NewBoardPoint(3, 19, White)] = int64(283378038284753486)
-	mappp[NewBoardPoint(4, 1, None)] = int64(4344802879444379534)
-	mappp[NewBoardPoint(4, 1, Black)] = int64(2247524372795908413)
-	mappp[NewBoardPoint(4, 1, White)] = int64(8792325177442076779)
-	mappp[NewBoardPoint(4, 2, None)] = int64(7055856360244790090)
-	mappp[NewBoardPoint(4, 2, Black)] = int64(1933274278282302485)
-	mappp[NewBoardPoint(4, 2, White)] = int64(631305302192868082)
-	mappp[NewBoardPoint(4, 3, None)] = int64(8812047994176463448)
-	mappp[NewBoardPoint(4, 3, Black)] = int64(1999282657256656919)
-	mappp[NewBoardPoint(4, 3, White)] = int64(4920167685844773638)
-	mappp[NewBoardPoint(4, 4, None)] = int64(9213151919573480919)
-	mappp[NewBoardPoint(4, 4, Black)] = int64(5395243415758210401)
-	mappp[NewBoardPoint(4, 4, White)] = int64(6010682946548414082)
-	mappp[NewBoardPoint(4, 5, None)] = int64(5870435614464821725)
-	mappp[NewBoardPoint(4, 5, Black)] = int64(7340369803125186510)
-	mappp[NewBoardPoint(4, 5, White)] = int64(8993702906546437728)
-	mappp[NewBoardPoint(4, 6, None)] = int64(1051848541016190552)
-	mappp[NewBoardPoint(4, 6, Black)] = int64(3228251920542984653)
-	mappp[NewBoardPoint(4, 6, White)] = int64(997268986998522867)
-	mappp[NewBoardPoint(4, 7, None)] = int64(8915521915414762246)
-	mappp[NewBoardPoint(4, 7, Black)] = int64(7312562739288157557)
-	mappp[NewBoardPoint(4, 7, White)] = int64(8505960411416356701)
-	mappp[NewBoardPoint(4, 8, None)] = int64(252889192677099074)
-	mappp[NewBoardPoint(4, 8, Black)] = int64(7464994345832478946)
-	mappp[NewBoardPoint(4, 8, White)] = int64(4902973119846135260)
-	mappp[NewBoardPoint(4, 9, None)] = int64(5472433841058717018)
-	mappp[NewBoardPoint(4, 9, Black)] = int64(5181693988261014675)
-	mappp[NewBoardPoint(4, 9, White)] = int64(3470549738622052094)
-	mappp[NewBoardPoint(4, 10, None)] = int64(456871416863653580)
-	mappp[NewBoardPoint(4, 10, Black)] = int64(5412524037382516807)
-	mappp[NewBoardPoint(4, 10, White)] = int64(3211262001047790477)
-	mappp[NewBoardPoint(4, 11, None)] = int64(3880964402268274391)
-	mappp[NewBoardPoint(4, 11, Black)] = int64(7440508284839894607)
-	mappp[NewBoardPoint(4, 11, White)] = int64(1416035867151085147)
-	mappp[NewBoardPoint(4, 12, None)] = int64(4428397771426361362)
-	mappp[NewBoardPoint(4, 12, Black)] = int64(5916192091567499484)
-	mappp[NewBoardPoint(4, 12, White)] = int64(5270642050875057415)
-	mappp[NewBoardPoint(4, 13, None)] = int64(6552854097057545649)
-	mappp[NewBoardPoint(4, 13, Black)] = int64(1866949497921219893)
-	mappp[NewBoardPoint(4, 13, White)] = int64(2725012424395224333)
-	mappp[NewBoardPoint(4, 14, None)] = int64(2941661543357306155)
-	mappp[NewBoardPoint(4, 14, Black)] = int64(794038694975074782)
-	mappp[NewBoardPoint(4, 14, White)] = int64(4634730767540038647)
-	mappp[NewBoardPoint(4, 15, None)] = int64(5830989808197080716)
-	mappp[NewBoardPoint(4, 15, Black)] = int64(6114758813232211675)
-	mappp[NewBoardPoint(4, 15, White)] = int64(2287435589842002152)
-	mappp[NewBoardPoint(4, 16, None)] = int64(8454258873394144639)
-	mappp[NewBoardPoint(4, 16, Black)] = int64(3198886631770504112)
-	mappp[NewBoardPoint(4, 16, White)] = int64(7785176540550484363)
-	mappp[NewBoardPoint(4, 17, None)] = int64(4666767361945750677)
-	mappp[NewBoardPoint(4, 17, Black)] = int64(5832017189499282461)
-	mappp[NewBoardPoint(4, 17, White)] = int64(4496063686813036957)
-	mappp[NewBoardPoint(4, 18, None)] = int64(4943572011098422566)
-	mappp[NewBoardPoint(4, 18, Black)] = int64(6844413889930261574)
-	mappp[NewBoardPoint(4, 18, White)] = int64(3190428778141297075)
-	mappp[NewBoardPoint(4, 19, None)] = int64(1755895226764519112)
-	mappp[NewBoardPoint(4, 19, Black)] = int64(8054355891338578703)
-	mappp[NewBoardPoint(4, 19, White)] = int64(7152361673657791735)
-	mappp[NewBoardPoint(5, 1, None)] = int64(5036429119017565296)
-	mappp[NewBoardPoint(5, 1, Black)] = int64(27519072032052115)
-	mappp[NewBoardPoint(5, 1, White)] = int64(8171550930548295725)
-	mappp[NewBoardPoint(5, 2, None)] = int64(9132575489706369046)
-	mappp[NewBoardPoint(5, 2, Black)] = int64(4556921158771206531)
-	mappp[NewBoardPoint(5, 2, White)] = int64(8395683060075604415)
-	mappp[NewBoardPoint(5, 3, None)] = int64(5446475954716586505)
-	mappp[NewBoardPoint(5, 3, Black)] = int64(1463903496858530414)
-	mappp[NewBoardPoint(5, 3, White)] = int64(1167151361880115141)
-	mappp[NewBoardPoint(5, 4, None)] = int64(851032170223141561)
-	mappp[NewBoardPoint(5, 4, Black)] = int64(2477792426454089646)
-	mappp[NewBoardPoint(5, 4, White)] = int64(6225303127993178642)
-	mappp[NewBoardPoint(5, 5, None)] = int64(7879191864450482811)
-	mappp[NewBoardPoint(5, 5, Black)] = int64(7282658868621353141)
-	mappp[NewBoardPoint(5, 5, White)] = int64(6845022855063395997)
-	mappp[NewBoardPoint(5, 6, None)] = int64(5552246478881370114)
-	mappp[NewBoardPoint(5, 6, Black)] = int64(2872312655051248866)
-	mappp[NewBoardPoint(5, 6, White)] = int64(3107571239668130222)
-	mappp[NewBoardPoint(5, 7, None)] = int64(4527079454100055951)
-	mappp[NewBoardPoint(5, 7, Black)] = int64(918389912471342077)
-	mappp[NewBoardPoint(5, 7, White)] = int64(6115183267340443770)
-	mappp[NewBoardPoint(5, 8, None)] = int64(9008444172681444782)
-	mappp[NewBoardPoint(5, 8, Black)] = int64(7057394814729125157)
-	mappp[NewBoardPoint(5, 8, White)] = int64(3764282698674974458)
-	mappp[NewBoardPoint(5, 9, None)] = int64(7831853022457791616)
-	mappp[NewBoardPoint(5, 9, Black)] = int64(6562514894444662291)
-	mappp[NewBoardPoint(5, 9, White)] = int64(935859536319421498)
-	mappp[NewBoardPoint(5, 10, None)] = int64(7613623784453729701)
-	mappp[NewBoardPoint(5, 10, Black)] = int64(135059754033717238)
-	mappp[NewBoardPoint(5, 10, White)] = int64(2276717090562637893)
-	mappp[NewBoardPoint(5, 11, None)] = int64(3253039236194602762)
-	mappp[NewBoardPoint(5, 11, Black)] = int64(57913205015935290)
-	mappp[NewBoardPoint(5, 11, White)] = int64(5266214952832426774)
-	mappp[NewBoardPoint(5, 12, None)] = int64(5668878077563466450)
-	mappp[NewBoardPoint(5, 12, Black)] = int64(3230611974155214550)
-	mappp[NewBoardPoint(5, 12, White)] = int64(2294154686827569535)
-	mappp[NewBoardPoint(5, 13, None)] = int64(4199505104581371466)
-	mappp[NewBoardPoint(5, 13, Black)] = int64(864142537979762298)
-	mappp[NewBoardPoint(5, 13, White)] = int64(831642762749640780)
-	mappp[NewBoardPoint(5, 14, None)] = int64(5049366686710829115)
-	mappp[NewBoardPoint(5, 14, Black)] = int64(5136879394357445974)
-	mappp[NewBoardPoint(5, 14, White)] = int64(5236678560945124468)
-	mappp[NewBoardPoint(5, 15, None)] = int64(611581455107832323)
-	mappp[NewBoardPoint(5, 15, Black)] = int64(4596160092398592229)
-	mappp[NewBoardPoint(5, 15, White)] = int64(2563571204601691883)
-	mappp[NewBoardPoint(5, 16, None)] = int64(3302063388404623907)
-	mappp[NewBoardPoint(5, 16, Black)] = int64(5724246586132530063)
-	mappp[NewBoardPoint(5, 16, White)] = int64(2794571788169337968)
-	mappp[NewBoardPoint(5, 17, None)] = int64(2720514601784057205)
-	mappp[NewBoardPoint(5, 17, Black)] = int64(7677889457395228656)
-	mappp[NewBoardPoint(5, 17, White)] = int64(2345072980289994032)
-	mappp[NewBoardPoint(5, 18, None)] = int64(5604808630673003828)
-	mappp[NewBoardPoint(5, 18, Black)] = int64(3699309635642320686)
-	mappp[NewBoardPoint(5, 18, White)] = int64(753560169142977726)
-	mappp[NewBoardPoint(5, 19, None)] = int64(3259544374054042734)
-	mappp[NewBoardPoint(5, 19, Black)] = int64(6244458313075268670)
-	mappp[NewBoardPoint(5, 19, White)] = int64(7960969901776382892)
-	mappp[NewBoardPoint(6, 1, None)] = int64(8557794551815134047)
-	mappp[NewBoardPoint(6, 1, Black)] = int64(3216114159583291352)
-	mappp[NewBoardPoint(6, 1, White)] = int64(1960159523276602301)
-	mappp[NewBoardPoint(6, 2, None)] = int64(1997613962097260150)
-	mappp[NewBoardPoint(6, 2, Black)] = int64(4880693682893718583)
-	mappp[NewBoardPoint(6, 2, White)] = int64(5908620015391176534)
-	mappp[NewBoardPoint(6, 3, None)] = int64(901177491703539185)
-	mappp[NewBoardPoint(6, 3, Black)] = int64(1570309397940630702)
-	mappp[NewBoardPoint(6, 3, White)] = int64(6439877272297241265)
-	mappp[NewBoardPoint(6, 4, None)] = int64(333335549679554338)
-	mappp[NewBoardPoint(6, 4, Black)] = int64(4632885331485423727)
-	mappp[NewBoardPoint(6, 4, White)] = int64(3723165512633585729)
-	mappp[NewBoardPoint(6, 5, None)] = int64(7321544685276550746)
-	mappp[NewBoardPoint(6, 5, Black)] = int64(6017436233281515745)
-	mappp[NewBoardPoint(6, 5, White)] = int64(201913594090046498)
-	mappp[NewBoardPoint(6, 6, None)] = int64(5676191494894510163)
-	mappp[NewBoardPoint(6, 6, Black)] = int64(3048966724376972456)
-	mappp[NewBoardPoint(6, 6, White)] = int64(1477912997083231090)
-	mappp[NewBoardPoint(6, 7, None)] = int64(8155903316758272061)
-	mappp[NewBoardPoint(6, 7, Black)] = int64(4448253157654503150)
-	mappp[NewBoardPoint(6, 7, White)] = int64(3941991837227838948)
-	mappp[NewBoardPoint(6, 8, None)] = int64(4497982120720071154)
-	mappp[NewBoardPoint(6, 8, Black)] = int64(509724323451388775)
-	mappp[NewBoardPoint(6, 8, White)] = int64(8979001406633870498)
-	mappp[NewBoardPoint(6, 9, None)] = int64(4308148798661458258)
-	mappp[NewBoardPoint(6, 9, Black)] = int64(6953444216830650037)
-	mappp[NewBoardPoint(6, 9, White)] = int64(6385506382165649240)
-	mappp[NewBoardPoint(6, 10, None)] = int64(8597908386707287961)
-	mappp[NewBoardPoint(6, 10, Black)] = int64(126570978442725799)
-	mappp[NewBoardPoint(6, 10, White)] = int64(6039431283189564693)
-	mappp[NewBoardPoint(6, 11, None)] = int64(1063225539516068410)
-	mappp[NewBoardPoint(6, 11, Black)] = int64(7234282405269959513)
-	mappp[NewBoardPoint(6, 11, White)] = int64(5141279901410754785)
-	mappp[NewBoardPoint(6, 12, None)] = int64(3055982290806952014)
-	mappp[NewBoardPoint(6, 12, Black)] = int64(9216971953417741623)
-	mappp[NewBoardPoint(6, 12, White)] = int64(5784120014724963349)
-	mappp[NewBoardPoint(6, 13, None)] = int64(7817302022918887603)
-	mappp[NewBoardPoint(6, 13, Black)] = int64(6045299169290085382)
-	mappp[NewBoardPoint(6, 13, White)] = int64(1321622597082424114)
-	mappp[NewBoardPoint(6, 14, None)] = int64(5678947682166449922)
-	mappp[NewBoardPoint(6, 14, Black)] = int64(6835818331225003374)
-	mappp[NewBoardPoint(6, 14, White)] = int64(3073706034925795166)
-	mappp[NewBoardPoint(6, 15, None)] = int64(1312112267056085663)
-	mappp[NewBoardPoint(6, 15, Black)] = int64(3343310645956236091)
-	mappp[NewBoardPoint(6, 15, White)] = int64(8517860098662089362)
-	mappp[NewBoardPoint(6, 16, None)] = int64(8638762058900704092)
-	mappp[NewBoardPoint(6, 16, Black)] = int64(7990284173150887600)
-	mappp[NewBoardPoint(6, 16, White)] = int64(6909447301359470038)
-	mappp[NewBoardPoint(6, 17, None)] = int64(4374031827384556154)
-	mappp[NewBoardPoint(6, 17, Black)] = int64(7967169174992726125)
-	mappp[NewBoardPoint(6, 17, White)] = int64(3205768750240164437)
-	mappp[NewBoardPoint(6, 18, None)] = int64(4179474061655148296)
-	mappp[NewBoardPoint(6, 18, Black)] = int64(1568708756236306511)
-	mappp[NewBoardPoint(6, 18, White)] = int64(1625883398532729321)
-	mappp[NewBoardPoint(6, 19, None)] = int64(3441051421246186530)
-	mappp[NewBoardPoint(6, 19, Black)] = int64(1105468341465481433)
-	mappp[NewBoardPoint(6, 19, White)] = int64(4337056790892308000)
-	mappp[NewBoardPoint(7, 1, None)] = int64(886113532324125086)
-	mappp[NewBoardPoint(7, 1, Black)] = int64(828135980669692584)
-	mappp[NewBoardPoint(7, 1, White)] = int64(256431391144878794)
-	mappp[NewBoardPoint(7, 2, None)] = int64(1996250685207240069)
-	mappp[NewBoardPoint(7, 2, Black)] = int64(7393492537295330299)
-	mappp[NewBoardPoint(7, 2, White)] = int64(6954630740776661267)
-	mappp[NewBoardPoint(7, 3, None)] = int64(3544911246227666522)
-	mappp[NewBoardPoint(7, 3, Black)] = int64(5662837387990144706)
-	mappp[NewBoardPoint(7, 3, White)] = int64(5967586022463502373)
-	mappp[NewBoardPoint(7, 4, None)] = int64(1867167497370536201)
-	mappp[NewBoardPoint(7, 4, Black)] = int64(7993528620100544837)
-	mappp[NewBoardPoint(7, 4, White)] = int64(2689170736211985219)
-	mappp[NewBoardPoint(7, 5, None)] = int64(2124475288422790995)
-	mappp[NewBoardPoint(7, 5, Black)] = int64(4011715484042086042)
-	mappp[NewBoardPoint(7, 5, White)] = int64(831581505001985016)
-	mappp[NewBoardPoint(7, 6, None)] = int64(1579249527937154934)
-	mappp[NewBoardPoint(7, 6, Black)] = int64(6431198269921660839)
-	mappp[NewBoardPoint(7, 6, White)] = int64(229016590125585830)
-	mappp[NewBoardPoint(7, 7, None)] = int64(4104179157075553476)
-	mappp[NewBoardPoint(7, 7, Black)] = int64(254440931109061044)
-	mappp[NewBoardPoint(7, 7, White)] = int64(356400492184911860)
-	mappp[NewBoardPoint(7, 8, None)] = int64(3159736409384119623)
-	mappp[NewBoardPoint(7, 8, Black)] = int64(7784000338103953632)
-	mappp[NewBoardPoint(7, 8, White)] = int64(1355961730575596099)
-	mappp[NewBoardPoint(7, 9, None)] = int64(3387598700829130245)
-	mappp[NewBoardPoint(7, 9, Black)] = int64(3803751254960903681)
-	mappp[NewBoardPoint(7, 9, White)] = int64(3910959660705941524)
-	mappp[NewBoardPoint(7, 10, None)] = int64(1971475070401547430)
-	mappp[NewBoardPoint(7, 10, Black)] = int64(1445332962592208150)
-	mappp[NewBoardPoint(7, 10, White)] = int64(8659971364809675229)
-	mappp[NewBoardPoint(7, 11, None)] = int64(709025145511902124)
-	mappp[NewBoardPoint(7, 11, Black)] = int64(1664123690099683877)
-	mappp[NewBoardPoint(7, 11, White)] = int64(1871317178237115919)
-	mappp[NewBoardPoint(7, 12, None)] = int64(327435479196565066)
-	mappp[NewBoardPoint(7, 12, Black)] = int64(3599390129463585813)
-	mappp[NewBoardPoint(7, 12, White)] = int64(884707410997566551)
-	mappp[NewBoardPoint(7, 13, None)] = int64(1818722328851377835)
-	mappp[NewBoardPoint(7, 13, Black)] = int64(604575675671073414)
-	mappp[NewBoardPoint(7, 13, White)] = int64(3489974241229561384)
-	mappp[NewBoardPoint(7, 14, None)] = int64(792632255406386383)
-	mappp[NewBoardPoint(7, 14, Black)] = int64(5979101653672932673)
-	mappp[NewBoardPoint(7, 14, White)] = int64(7589064542177599613)
-	mappp[NewBoardPoint(7, 15, None)] = int64(5983247392706463051)
-	mappp[NewBoardPoint(7, 15, Black)] = int64(3099363384580392617)
-	mappp[NewBoardPoint(7, 15, White)] = int64(1141588719666931396)
-	mappp[NewBoardPoint(7, 16, None)] = int64(7616059131597504717)
-	mappp[NewBoardPoint(7, 16, Black)] = int64(1745013709349797210)
-	mappp[NewBoardPoint(7, 16, White)] = int64(1806687433683271360)
-	mappp[NewBoardPoint(7, 17, None)] = int64(4217102063757751736)
-	mappp[NewBoardPoint(7, 17, Black)] = int64(8889958016912914022)
-	mappp[NewBoardPoint(7, 17, White)] = int64(6987061469656798587)
-	mappp[NewBoardPoint(7, 18, None)] = int64(6991584504521905610)
-	mappp[NewBoardPoint(7, 18, Black)] = int64(4695752403914148599)
-	mappp[NewBoardPoint(7, 18, White)] = int64(6125077724123022650)
-	mappp[NewBoardPoint(7, 19, None)] = int64(7381933873389669411)
-	mappp[NewBoardPoint(7, 19, Black)] = int64(6011010170355300074)
-	mappp[NewBoardPoint(7, 19, White)] = int64(8481833691780401428)
-	mappp[NewBoardPoint(8, 1, None)] = int64(1180753346108579795)
-	mappp[NewBoardPoint(8, 1, Black)] = int64(8740893306606088032)
-	mappp[NewBoardPoint(8, 1, White)] = int64(3781378750137072015)
-	mappp[NewBoardPoint(8, 2, None)] = int64(1991917936589491492)
-	mappp[NewBoardPoint(8, 2, Black)] = int64(5333507037629990431)
-	mappp[NewBoardPoint(8, 2, White)] = int64(5508222054064428884)
-	mappp[NewBoardPoint(8, 3, None)] = int64(1661754414523010980)
-	mappp[NewBoardPoint(8, 3, Black)] = int64(101918752730532952)
-	mappp[NewBoardPoint(8, 3, White)] = int64(7826287696981903583)
-	mappp[NewBoardPoint(8, 4, None)] = int64(6135172226267928085)
-	mappp[NewBoardPoint(8, 4, Black)] = int64(876543602528984798)
-	mappp[NewBoardPoint(8, 4, White)] = int64(866951627183250989)
-	mappp[NewBoardPoint(8, 5, None)] = int64(2206661949860149978)
-	mappp[NewBoardPoint(8, 5, Black)] = int64(919440540571028812)
-	mappp[NewBoardPoint(8, 5, White)] = int64(6269993804508600875)
-	mappp[NewBoardPoint(8, 6, None)] = int64(6550597690451528373)
-	mappp[NewBoardPoint(8, 6, Black)] = int64(455035543454462262)
-	mappp[NewBoardPoint(8, 6, White)] = int64(2178436279764343452)
-	mappp[NewBoardPoint(8, 7, None)] = int64(4100902988931544571)
-	mappp[NewBoardPoint(8, 7, Black)] = int64(5866752165230579286)
-	mappp[NewBoardPoint(8, 7, White)] = int64(5257014468486577205)
-	mappp[NewBoardPoint(8, 8, None)] = int64(6153932961309567194)
-	mappp[NewBoardPoint(8, 8, Black)] = int64(5846331541835153594)
-	mappp[NewBoardPoint(8, 8, White)] = int64(7651649350549762601)
-	mappp[NewBoardPoint(8, 9, None)] = int64(2922571297808167440)
-	mappp[NewBoardPoint(8, 9, Black)] = int64(4469834115782085807)
-	mappp[NewBoardPoint(8, 9, White)] = int64(2720427246638834089)
-	mappp[NewBoardPoint(8, 10, None)] = int64(1034606324475935741)
-	mappp[NewBoardPoint(8, 10, Black)] = int64(3842149319690952491)
-	mappp[NewBoardPoint(8, 10, White)] = int64(5382666798985351515)
-	mappp[NewBoardPoint(8, 11, None)] = int64(8930641527371388352)
-	mappp[NewBoardPoint(8, 11, Black)] = int64(8194890239086060164)
-	mappp[NewBoardPoint(8, 11, White)] = int64(1639668396075699446)
-	mappp[NewBoardPoint(8, 12, None)] = int64(3209831256960635722)
-	mappp[NewBoardPoint(8, 12, Black)] = int64(7267148545099012047)
-	mappp[NewBoardPoint(8, 12, White)] = int64(4165201868852895384)
-	mappp[NewBoardPoint(8, 13, None)] = int64(7640027811381638560)
-	mappp[NewBoardPoint(8, 13, Black)] = int64(6703238423712019173)
-	mappp[NewBoardPoint(8, 13, White)] = int64(7429780663435896699)
-	mappp[NewBoardPoint(8, 14, None)] = int64(2638569769372215590)
-	mappp[NewBoardPoint(8, 14, Black)] = int64(8768894324870534056)
-	mappp[NewBoardPoint(8, 14, White)] = int64(8797703822186267405)
-	mappp[NewBoardPoint(8, 15, None)] = int64(1633671510123380185)
-	mappp[NewBoardPoint(8, 15, Black)] = int64(538596641719540519)
-	mappp[NewBoardPoint(8, 15, White)] = int64(6499462004405859185)
-	mappp[NewBoardPoint(8, 16, None)] = int64(7050433440155564727)
-	mappp[NewBoardPoint(8, 16, Black)] = int64(6594395552126710835)
-	mappp[NewBoardPoint(8, 16, White)] = int64(8593154846491345558)
-	mappp[NewBoardPoint(8, 17, None)] = int64(3960140329460209647)
-	mappp[NewBoardPoint(8, 17, Black)] = int64(5647364489618149211)
-	mappp[NewBoardPoint(8, 17, White)] = int64(6192559194551057123)
-	mappp[NewBoardPoint(8, 18, None)] = int64(5171609150103824743)
-	mappp[NewBoardPoint(8, 18, Black)] = int64(3197295049454248558)
-	mappp[NewBoardPoint(8, 18, White)] = int64(3083949568909778630)
-	mappp[NewBoardPoint(8, 19, None)] = int64(3047481343392201045)
-	mappp[NewBoardPoint(8, 19, Black)] = int64(7231008606551201596)
-	mappp[NewBoardPoint(8, 19, White)] = int64(6358879961534636625)
-	mappp[NewBoardPoint(9, 1, None)] = int64(5434203010126407757)
-	mappp[NewBoardPoint(9, 1, Black)] = int64(4667483704458429254)
-	mappp[NewBoardPoint(9, 1, White)] = int64(8964122441971913454)
-	mappp[NewBoardPoint(9, 2, None)] = int64(2014765491983954377)
-	mappp[NewBoardPoint(9, 2, Black)] = int64(425204814909215169)
-	mappp[NewBoardPoint(9, 2, White)] = int64(5152344447583430106)
-	mappp[NewBoardPoint(9, 3, None)] = int64(8342128072843495639)
-	mappp[NewBoardPoint(9, 3, Black)] = int64(1199929852425181950)
-	mappp[NewBoardPoint(9, 3, White)] = int64(6736354747700114470)
-	mappp[NewBoardPoint(9, 4, None)] = int64(5606867716726137323)
-	mappp[NewBoardPoint(9, 4, Black)] = int64(5900629767080808598)
-	mappp[NewBoardPoint(9, 4, White)] = int64(1934902741929647387)
-	mappp[NewBoardPoint(9, 5, None)] = int64(186260393131468201)
-	mappp[NewBoardPoint(9, 5, Black)] = int64(6503382255060401671)
-	mappp[NewBoardPoint(9, 5, White)] = int64(5664941015591828142)
-	mappp[NewBoardPoint(9, 6, None)] = int64(5458547700267807149)
-	mappp[NewBoardPoint(9, 6, Black)] = int64(587128636633445986)
-	mappp[NewBoardPoint(9, 6, White)] = int64(1018672736152841083)
-	mappp[NewBoardPoint(9, 7, None)] = int64(8340249684182220884)
-	mappp[NewBoardPoint(9, 7, Black)] = int64(6769549678830231776)
-	mappp[NewBoardPoint(9, 7, White)] = int64(1905559679378468411)
-	mappp[NewBoardPoint(9, 8, None)] = int64(5603563288027911226)
-	mappp[NewBoardPoint(9, 8, Black)] = int64(6173513594412364849)
-	mappp[NewBoardPoint(9, 8, White)] = int64(1691850006911369313)
-	mappp[NewBoardPoint(9, 9, None)] = int64(2928815833580919000)
-	mappp[NewBoardPoint(9, 9, Black)] = int64(2784301869537966766)
-	mappp[NewBoardPoint(9, 9, White)] = int64(3504757080453676531)
-	mappp[NewBoardPoint(9, 10, None)] = int64(3346401366662238640)
-	mappp[NewBoardPoint(9, 10, Black)] = int64(4809549770297281518)
-	mappp[NewBoardPoint(9, 10, White)] = int64(1148357506986646958)
-	mappp[NewBoardPoint(9, 11, None)] = int64(3290287793482242040)
-	mappp[NewBoardPoint(9, 11, Black)] = int64(7432077130369386420)
-	mappp[NewBoardPoint(9, 11, White)] = int64(7080730602517074357)
-	mappp[NewBoardPoint(9, 12, None)] = int64(377505691556865173)
-	mappp[NewBoardPoint(9, 12, Black)] = int64(6248755653890858170)
-	mappp[NewBoardPoint(9, 12, White)] = int64(7329628085916770881)
-	mappp[NewBoardPoint(9, 13, None)] = int64(6945885403347716143)
-	mappp[NewBoardPoint(9, 13, Black)] = int64(8265310559050698849)
-	mappp[NewBoardPoint(9, 13, White)] = int64(2334691474129968102)
-	mappp[NewBoardPoint(9, 14, None)] = int64(6703148929046861562)
-	mappp[NewBoardPoint(9, 14, Black)] = int64(159450072331072106)
-	mappp[NewBoardPoint(9, 14, White)] = int64(3078338180759564477)
-	mappp[NewBoardPoint(9, 15, None)] = int64(4186213193547282832)
-	mappp[NewBoardPoint(9, 15, Black)] = int64(7066862009481804678)
-	mappp[NewBoardPoint(9, 15, White)] = int64(4311800841226526939)
-	mappp[NewBoardPoint(9, 16, None)] = int64(7543150118936612110)
-	mappp[NewBoardPoint(9, 16, Black)] = int64(4035993807355367578)
-	mappp[NewBoardPoint(9, 16, White)] = int64(8295466632322995864)
-	mappp[NewBoardPoint(9, 17, None)] = int64(1815644117785341422)
-	mappp[NewBoardPoint(9, 17, Black)] = int64(1539648776647802374)
-	mappp[NewBoardPoint(9, 17, White)] = int64(3321495240521043136)
-	mappp[NewBoardPoint(9, 18, None)] = int64(5613980665057838288)
-	mappp[NewBoardPoint(9, 18, Black)] = int64(908523632921943417)
-	mappp[NewBoardPoint(9, 18, White)] = int64(7380607769587231426)
-	mappp[NewBoardPoint(9, 19, None)] = int64(7902231527187477414)
-	mappp[NewBoardPoint(9, 19, Black)] = int64(4404393710203762977)
-	mappp[NewBoardPoint(9, 19, White)] = int64(1711678608835222879)
-	mappp[NewBoardPoint(10, 1, None)] = int64(9146717898590806380)
-	mappp[NewBoardPoint(10, 1, Black)] = int64(5161649345107353029)
-	mappp[NewBoardPoint(10, 1, White)] = int64(7640240514024087124)
-	mappp[NewBoardPoint(10, 2, None)] = int64(7486791521072039843)
-	mappp[NewBoardPoint(10, 2, Black)] = int64(3827850450605420721)
-	mappp[NewBoardPoint(10, 2, White)] = int64(2703316564487658532)
-	mappp[NewBoardPoint(10, 3, None)] = int64(1527950331233717617)
-	mappp[NewBoardPoint(10, 3, Black)] = int64(5729316983781207074)
-	mappp[NewBoardPoint(10, 3, White)] = int64(8362639751329035746)
-	mappp[NewBoardPoint(10, 4, None)] = int64(5371732155225958750)
-	mappp[NewBoardPoint(10, 4, Black)] = int64(4010646362925805231)
-	mappp[NewBoardPoint(10, 4, White)] = int64(4471021811756826988)
-	mappp[NewBoardPoint(10, 5, None)] = int64(2964479661225775155)
-	mappp[NewBoardPoint(10, 5, Black)] = int64(8610369111568794898)
-	mappp[NewBoardPoint(10, 5, White)] = int64(3682738622557525751)
-	mappp[NewBoardPoint(10, 6, None)] = int64(5311770675518272482)
-	mappp[NewBoardPoint(10, 6, Black)] = int64(7620326491530272741)
-	mappp[NewBoardPoint(10, 6, White)] = int64(4534465860315814575)
-	mappp[NewBoardPoint(10, 7, None)] = int64(6463742504394291147)
-	mappp[NewBoardPoint(10, 7, Black)] = int64(2568866941620778519)
-	mappp[NewBoardPoint(10, 7, White)] = int64(4930067997197475187)
-	mappp[NewBoardPoint(10, 8, None)] = int64(4822677802926399417)
-	mappp[NewBoardPoint(10, 8, Black)] = int64(6795609206359680169)
-	mappp[NewBoardPoint(10, 8, White)] = int64(8953203723839341510)
-	mappp[NewBoardPoint(10, 9, None)] = int64(7012730053247005900)
-	mappp[NewBoardPoint(10, 9, Black)] = int64(9061412183002855252)
-	mappp[NewBoardPoint(10, 9, White)] = int64(1303904592752373626)
-	mappp[NewBoardPoint(10, 10, None)] = int64(5308986643454056797)
-	mappp[NewBoardPoint(10, 10, Black)] = int64(3171924742086669123)
-	mappp[NewBoardPoint(10, 10, White)] = int64(6341061068184365870)
-	mappp[NewBoardPoint(10, 11, None)] = int64(4279537895210739943)
-	mappp[NewBoardPoint(10, 11, Black)] = int64(7999449213126948002)
-	mappp[NewBoardPoint(10, 11, White)] = int64(4408490431370548494)
-	mappp[NewBoardPoint(10, 12, None)] = int64(3392525348007489084)
-	mappp[NewBoardPoint(10, 12, Black)] = int64(8330372332937875973)
-	mappp[NewBoardPoint(10, 12, White)] = int64(8709018933225548070)
-	mappp[NewBoardPoint(10, 13, None)] = int64(5848197193140674112)
-	mappp[NewBoardPoint(10, 13, Black)] = int64(8451006344910517269)
-	mappp[NewBoardPoint(10, 13, White)] = int64(2573956830120171961)
-	mappp[NewBoardPoint(10, 14, None)] = int64(6866494567833013527)
-	mappp[NewBoardPoint(10, 14, Black)] = int64(9217772161664431317)
-	mappp[NewBoardPoint(10, 14, White)] = int64(1454828929356015574)
-	mappp[NewBoardPoint(10, 15, None)] = int64(4213284653674175737)
-	mappp[NewBoardPoint(10, 15, Black)] = int64(3981728833655218916)
-	mappp[NewBoardPoint(10, 15, White)] = int64(7122861142140969113)
-	mappp[NewBoardPoint(10, 16, None)] = int64(3588847636359034697)
-	mappp[NewBoardPoint(10, 16, Black)] = int64(1220954973785192080)
-	mappp[NewBoardPoint(10, 16, White)] = int64(1660875907210144036)
-	mappp[NewBoardPoint(10, 17, None)] = int64(4593123892219198700)
-	mappp[NewBoardPoint(10, 17, Black)] = int64(6725793788527859309)
-	mappp[NewBoardPoint(10, 17, White)] = int64(1711255590577612958)
-	mappp[NewBoardPoint(10, 18, None)] = int64(7777485053709936725)
-	mappp[NewBoardPoint(10, 18, Black)] = int64(6824646970411629675)
-	mappp[NewBoardPoint(10, 18, White)] = int64(422963485923394953)
-	mappp[NewBoardPoint(10, 19, None)] = int64(7340758723305064458)
-	mappp[NewBoardPoint(10, 19, Black)] = int64(1090959408538317414)
-	mappp[NewBoardPoint(10, 19, White)] = int64(3654441065872221333)
-	mappp[NewBoardPoint(11, 1, None)] = int64(4875452083696476835)
-	mappp[NewBoardPoint(11, 1, Black)] = int64(2368300949100143299)
-	mappp[NewBoardPoint(11, 1, White)] = int64(3085762127013562842)
-	mappp[NewBoardPoint(11, 2, None)] = int64(1053247133187067950)
-	mappp[NewBoardPoint(11, 2, Black)] = int64(1521917091648904590)
-	mappp[NewBoardPoint(11, 2, White)] = int64(3370651356644290870)
-	mappp[NewBoardPoint(11, 3, None)] = int64(8080696891428152836)
-	mappp[NewBoardPoint(11, 3, Black)] = int64(781860468889510312)
-	mappp[NewBoardPoint(11, 3, White)] = int64(7009879387864794956)
-	mappp[NewBoardPoint(11, 4, None)] = int64(7279031067158333145)
-	mappp[NewBoardPoint(11, 4, Black)] = int64(5477337533743024426)
-	mappp[NewBoardPoint(11, 4, White)] = int64(2512996106254838302)
-	mappp[NewBoardPoint(11, 5, None)] = int64(3429843539524661945)
-	mappp[NewBoardPoint(11, 5, Black)] = int64(713470276962409170)
-	mappp[NewBoardPoint(11, 5, White)] = int64(6157481663926212757)
-	mappp[NewBoardPoint(11, 6, None)] = int64(1205769221559756883)
-	mappp[NewBoardPoint(11, 6, Black)] = int64(2701744810344364138)
-	mappp[NewBoardPoint(11, 6, White)] = int64(1883704762159753690)
-	mappp[NewBoardPoint(11, 7, None)] = int64(5334938007719335491)
-	mappp[NewBoardPoint(11, 7, Black)] = int64(7478643185328052573)
-	mappp[NewBoardPoint(11, 7, White)] = int64(3006801529371030866)
-	mappp[NewBoardPoint(11, 8, None)] = int64(4914426138463303309)
-	mappp[NewBoardPoint(11, 8, Black)] = int64(4003355625721142905)
-	mappp[NewBoardPoint(11, 8, White)] = int64(3962716280923457275)
-	mappp[NewBoardPoint(11, 9, None)] = int64(4638736102934790503)
-	mappp[NewBoardPoint(11, 9, Black)] = int64(4423925470743889146)
-	mappp[NewBoardPoint(11, 9, White)] = int64(4872575170779109392)
-	mappp[NewBoardPoint(11, 10, None)] = int64(584247940616612163)
-	mappp[NewBoardPoint(11, 10, Black)] = int64(2568227924806589063)
-	mappp[NewBoardPoint(11, 10, White)] = int64(6414089592567949762)
-	mappp[NewBoardPoint(11, 11, None)] = int64(8129289877450348366)
-	mappp[NewBoardPoint(11, 11, Black)] = int64(4373758970110744138)
-	mappp[NewBoardPoint(11, 11, White)] = int64(7779912873052105500)
-	mappp[NewBoardPoint(11, 12, None)] = int64(7733352482212712557)
-	mappp[NewBoardPoint(11, 12, Black)] = int64(4814635228215370899)
-	mappp[NewBoardPoint(11, 12, White)] = int64(4218725375648286664)
-	mappp[NewBoardPoint(11, 13, None)] = int64(1430341779889265752)
-	mappp[NewBoardPoint(11, 13, Black)] = int64(3488824701841749000)
-	mappp[NewBoardPoint(11, 13, White)] = int64(7407291546817944051)
-	mappp[NewBoardPoint(11, 14, None)] = int64(2930416943288444440)
-	mappp[NewBoardPoint(11, 14, Black)] = int64(8582911382616619087)
-	mappp[NewBoardPoint(11, 14, White)] = int64(4609650463595649930)
-	mappp[NewBoardPoint(11, 15, None)] = int64(282890627318362140)
-	mappp[NewBoardPoint(11, 15, Black)] = int64(5464563599718138436)
-	mappp[NewBoardPoint(11, 15, White)] = int64(9023525131953045352)
-	mappp[NewBoardPoint(11, 16, None)] = int64(2511813536047612484)
-	mappp[NewBoardPoint(11, 16, Black)] = int64(1423656084339864690)
-	mappp[NewBoardPoint(11, 16, White)] = int64(3835604304392922709)
-	mappp[NewBoardPoint(11, 17, None)] = int64(6066255410442828715)
-	mappp[NewBoardPoint(11, 17, Black)] = int64(2399433779657273936)
-	mappp[NewBoardPoint(11, 17, White)] = int64(726442445228846084)
-	mappp[NewBoardPoint(11, 18, None)] = int64(2313547884126093975)
-	mappp[NewBoardPoint(11, 18, Black)] = int64(3908763818434142130)
-	mappp[NewBoardPoint(11, 18, White)] = int64(259827155985782391)
-	mappp[NewBoardPoint(11, 19, None)] = int64(7790609255826855247)
-	mappp[NewBoardPoint(11, 19, Black)] = int64(2460016153364813800)
-	mappp[NewBoardPoint(11, 19, White)] = int64(5520577175593158061)
-	mappp[NewBoardPoint(12, 1, None)] = int64(3099866293522733194)
-	mappp[NewBoardPoint(12, 1, Black)] = int64(7944349048157759164)
-	mappp[NewBoardPoint(12, 1, White)] = int64(7723937277194406255)
-	mappp[NewBoardPoint(12, 2, None)] = int64(7529058908815503)
-	mappp[NewBoardPoint(12, 2, Black)] = int64(6176483769304913634)
-	mappp[NewBoardPoint(12, 2, White)] = int64(120597950836779689)
-	mappp[NewBoardPoint(12, 3, None)] = int64(1170973874098209472)
-	mappp[NewBoardPoint(12, 3, Black)] = int64(7002041372011197591)
-	mappp[NewBoardPoint(12, 3, White)] = int64(4750775179239356301)
-	mappp[NewBoardPoint(12, 4, None)] = int64(2750758253490988519)
-	mappp[NewBoardPoint(12, 4, Black)] = int64(6940727465433268364)
-	mappp[NewBoardPoint(12, 4, White)] = int64(2004629148513846882)
-	mappp[NewBoardPoint(12, 5, None)] = int64(5756971456052457983)
-	mappp[NewBoardPoint(12, 5, Black)] = int64(6873181645711902153)
-	mappp[NewBoardPoint(12, 5, White)] = int64(2156357039457088630)
-	mappp[NewBoardPoint(12, 6, None)] = int64(6839346166300975018)
-	mappp[NewBoardPoint(12, 6, Black)] = int64(5915314848565475087)
-	mappp[NewBoardPoint(12, 6, White)] = int64(3195175685889982944)
-	mappp[NewBoardPoint(12, 7, None)] = int64(4598689526270949124)
-	mappp[NewBoardPoint(12, 7, Black)] = int64(3591985638168391440)
-	mappp[NewBoardPoint(12, 7, White)] = int64(9143830306403076947)
-	mappp[NewBoardPoint(12, 8, None)] = int64(6857688428917710367)
-	mappp[NewBoardPoint(12, 8, Black)] = int64(18145099303141395)
-	mappp[NewBoardPoint(12, 8, White)] = int64(2817026228124649316)
-	mappp[NewBoardPoint(12, 9, None)] = int64(210872180204112994)
-	mappp[NewBoardPoint(12, 9, Black)] = int64(5000028455965464037)
-	mappp[NewBoardPoint(12, 9, White)] = int64(5293156138121923875)
-	mappp[NewBoardPoint(12, 10, None)] = int64(8614085397675455160)
-	mappp[NewBoardPoint(12, 10, Black)] = int64(2930765697239249846)
-	mappp[NewBoardPoint(12, 10, White)] = int64(4386259068280166292)
-	mappp[NewBoardPoint(12, 11, None)] = int64(9195054790355866479)
-	mappp[NewBoardPoint(12, 11, Black)] = int64(1288073900487378979)
-	mappp[NewBoardPoint(12, 11, White)] = int64(9085314582762108682)
-	mappp[NewBoardPoint(12, 12, None)] = int64(8003730484112292996)
-	mappp[NewBoardPoint(12, 12, Black)] = int64(2567628397582791761)
-	mappp[NewBoardPoint(12, 12, White)] = int64(1541143752478028254)
-	mappp[NewBoardPoint(12, 13, None)] = int64(1770002302339474726)
-	mappp[NewBoardPoint(12, 13, Black)] = int64(4555449165021875805)
-	mappp[NewBoardPoint(12, 13, White)] = int64(588430256386771192)
-	mappp[NewBoardPoint(12, 14, None)] = int64(4908805492899368874)
-	mappp[NewBoardPoint(12, 14, Black)] = int64(7510420307028298844)
-	mappp[NewBoardPoint(12, 14, White)] = int64(4376383674204502580)
-	mappp[NewBoardPoint(12, 15, None)] = int64(7519021292450342677)
-	mappp[NewBoardPoint(12, 15, Black)] = int64(4265933055880548019)
-	mappp[NewBoardPoint(12, 15, White)] = int64(3297258759277977316)
-	mappp[NewBoardPoint(12, 16, None)] = int64(1849028107101554104)
-	mappp[NewBoardPoint(12, 16, Black)] = int64(1185770321766708947)
-	mappp[NewBoardPoint(12, 16, White)] = int64(2180733755541219307)
-	mappp[NewBoardPoint(12, 17, None)] = int64(2938857105428583192)
-	mappp[NewBoardPoint(12, 17, Black)] = int64(3850342668539304285)
-	mappp[NewBoardPoint(12, 17, White)] = int64(4338038206152734142)
-	mappp[NewBoardPoint(12, 18, None)] = int64(4442739103160290272)
-	mappp[NewBoardPoint(12, 18, Black)] = int64(3743134179646079964)
-	mappp[NewBoardPoint(12, 18, White)] = int64(6821930856385967546)
-	mappp[NewBoardPoint(12, 19, None)] = int64(3709246642133104623)
-	mappp[NewBoardPoint(12, 19, Black)] = int64(3255121755305947933)
-	mappp[NewBoardPoint(12, 19, White)] = int64(8701275906226468326)
-	mappp[NewBoardPoint(13, 1, None)] = int64(369490512284630731)
-	mappp[NewBoardPoint(13, 1, Black)] = int64(6372019294786024566)
-	mappp[NewBoardPoint(13, 1, White)] = int64(7119483499583724670)
-	mappp[NewBoardPoint(13, 2, None)] = int64(8807351457085110978)
-	mappp[NewBoardPoint(13, 2, Black)] = int64(7987550694085408594)
-	mappp[NewBoardPoint(13, 2, White)] = int64(8869368060222547954)
-	mappp[NewBoardPoint(13, 3, None)] = int64(1153034572514593827)
-	mappp[NewBoardPoint(13, 3, Black)] = int64(8291624372055746431)
-	mappp[NewBoardPoint(13, 3, White)] = int64(187291694084700264)
-	mappp[NewBoardPoint(13, 4, None)] = int64(7333259502730743042)
-	mappp[NewBoardPoint(13, 4, Black)] = int64(6179682645019182911)
-	mappp[NewBoardPoint(13, 4, White)] = int64(498060688146302442)
-	mappp[NewBoardPoint(13, 5, None)] = int64(6575611503650127997)
-	mappp[NewBoardPoint(13, 5, Black)] = int64(7828400074646442889)
-	mappp[NewBoardPoint(13, 5, White)] = int64(7779196514444438794)
-	mappp[NewBoardPoint(13, 6, None)] = int64(768517069559935196)
-	mappp[NewBoardPoint(13, 6, Black)] = int64(5160391649545241687)
-	mappp[NewBoardPoint(13, 6, White)] = int64(3691596529232713259)
-	mappp[NewBoardPoint(13, 7, None)] = int64(8905149359957031570)
-	mappp[NewBoardPoint(13, 7, Black)] = int64(1475181150179636663)
-	mappp[NewBoardPoint(13, 7, White)] = int64(6327829950974894493)
-	mappp[NewBoardPoint(13, 8, None)] = int64(5386527432467296416)
-	mappp[NewBoardPoint(13, 8, Black)] = int64(446656847153849282)
-	mappp[NewBoardPoint(13, 8, White)] = int64(4220121642271835746)
-	mappp[NewBoardPoint(13, 9, None)] = int64(3484508703554808030)
-	mappp[NewBoardPoint(13, 9, Black)] = int64(8099549300812507593)
-	mappp[NewBoardPoint(13, 9, White)] = int64(3354710068955060280)
-	mappp[NewBoardPoint(13, 10, None)] = int64(1558759560803031971)
-	mappp[NewBoardPoint(13, 10, Black)] = int64(2428359643415343761)
-	mappp[NewBoardPoint(13, 10, White)] = int64(6652874475134911733)
-	mappp[NewBoardPoint(13, 11, None)] = int64(1627719041124960174)
-	mappp[NewBoardPoint(13, 11, Black)] = int64(6313256626657999939)
-	mappp[NewBoardPoint(13, 11, White)] = int64(6629505623588278266)
-	mappp[NewBoardPoint(13, 12, None)] = int64(695042227914921350)
-	mappp[NewBoardPoint(13, 12, Black)] = int64(6734496787823438728)
-	mappp[NewBoardPoint(13, 12, White)] = int64(7836554439668421669)
-	mappp[NewBoardPoint(13, 13, None)] = int64(9221141921977857074)
-	mappp[NewBoardPoint(13, 13, Black)] = int64(8182942543635051153)
-	mappp[NewBoardPoint(13, 13, White)] = int64(6760091525693871702)
-	mappp[NewBoardPoint(13, 14, None)] = int64(3483909537665382881)
-	mappp[NewBoardPoint(13, 14, Black)] = int64(5501580305358076052)
-	mappp[NewBoardPoint(13, 14, White)] = int64(7172236097517911217)
-	mappp[NewBoardPoint(13, 15, None)] = int64(5415785958251147456)
-	mappp[NewBoardPoint(13, 15, Black)] = int64(4429364624751110054)
-	mappp[NewBoardPoint(13, 15, White)] = int64(4890131946738285944)
-	mappp[NewBoardPoint(13, 16, None)] = int64(6605357866503287179)
-	mappp[NewBoardPoint(13, 16, Black)] = int64(3342248222180460877)
-	mappp[NewBoardPoint(13, 16, White)] = int64(4699763328112008787)
-	mappp[NewBoardPoint(13, 17, None)] = int64(8131673100891351496)
-	mappp[NewBoardPoint(13, 17, Black)] = int64(3968405961615012140)
-	mappp[NewBoardPoint(13, 17, White)] = int64(6385704238597713224)
-	mappp[NewBoardPoint(13, 18, None)] = int64(1958783508105525130)
-	mappp[NewBoardPoint(13, 18, Black)] = int64(8338596730366049897)
-	mappp[NewBoardPoint(13, 18, White)] = int64(3203199626633999162)
-	mappp[NewBoardPoint(13, 19, None)] = int64(7610778563620783967)
-	mappp[NewBoardPoint(13, 19, Black)] = int64(8392125219478660470)
-	mappp[NewBoardPoint(13, 19, White)] = int64(6101603981460880168)
-	mappp[NewBoardPoint(14, 1, None)] = int64(6217116632326181352)
-	mappp[NewBoardPoint(14, 1, Black)] = int64(1340046201977646312)
-	mappp[NewBoardPoint(14, 1, White)] = int64(4533058306486773477)
-	mappp[NewBoardPoint(14, 2, None)] = int64(4849731440341701320)
-	mappp[NewBoardPoint(14, 2, Black)] = int64(762701856215124175)
-	mappp[NewBoardPoint(14, 2, White)] = int64(5451258033657933464)
-	mappp[NewBoardPoint(14, 3, None)] = int64(7723653998671978947)
-	mappp[NewBoardPoint(14, 3, Black)] = int64(6541130442943207625)
-	mappp[NewBoardPoint(14, 3, White)] = int64(4039160699399481682)
-	mappp[NewBoardPoint(14, 4, None)] = int64(2372529210175869743)
-	mappp[NewBoardPoint(14, 4, Black)] = int64(1261305751794059837)
-	mappp[NewBoardPoint(14, 4, White)] = int64(3508405432486870985)
-	mappp[NewBoardPoint(14, 5, None)] = int64(828873656540952222)
-	mappp[NewBoardPoint(14, 5, Black)] = int64(2693953160081563578)
-	mappp[NewBoardPoint(14, 5, White)] = int64(3701829921886089315)
-	mappp[NewBoardPoint(14, 6, None)] = int64(3932116219628200075)
-	mappp[NewBoardPoint(14, 6, Black)] = int64(6667730669693455320)
-	mappp[NewBoardPoint(14, 6, White)] = int64(861952265182844087)
-	mappp[NewBoardPoint(14, 7, None)] = int64(3545157738048355671)
-	mappp[NewBoardPoint(14, 7, Black)] = int64(70869513243076721)
-	mappp[NewBoardPoint(14, 7, White)] = int64(1833468495745158040)
-	mappp[NewBoardPoint(14, 8, None)] = int64(4278634338142130357)
-	mappp[NewBoardPoint(14, 8, Black)] = int64(122474238866651797)
-	mappp[NewBoardPoint(14, 8, White)] = int64(6750939054711361985)
-	mappp[NewBoardPoint(14, 9, None)] = int64(8840963008481536712)
-	mappp[NewBoardPoint(14, 9, Black)] = int64(7141689898246924599)
-	mappp[NewBoardPoint(14, 9, White)] = int64(79073965040796046)
-	mappp[NewBoardPoint(14, 10, None)] = int64(621177393512459351)
-	mappp[NewBoardPoint(14, 10, Black)] = int64(2650240709456812340)
-	mappp[NewBoardPoint(14, 10, White)] = int64(6867241946850451825)
-	mappp[NewBoardPoint(14, 11, None)] = int64(7834341949935765042)
-	mappp[NewBoardPoint(14, 11, Black)] = int64(689163082563890547)
-	mappp[NewBoardPoint(14, 11, White)] = int64(6008264306973469663)
-	mappp[NewBoardPoint(14, 12, None)] = int64(1669946318476145925)
-	mappp[NewBoardPoint(14, 12, Black)] = int64(9014440319080517054)
-	mappp[NewBoardPoint(14, 12, White)] = int64(1134973082137204243)
-	mappp[NewBoardPoint(14, 13, None)] = int64(2099022156070094908)
-	mappp[NewBoardPoint(14, 13, Black)] = int64(5884451656092181908)
-	mappp[NewBoardPoint(14, 13, White)] = int64(5569019613316951549)
-	mappp[NewBoardPoint(14, 14, None)] = int64(5182933805645139259)
-	mappp[NewBoardPoint(14, 14, Black)] = int64(7964379993166733507)
-	mappp[NewBoardPoint(14, 14, White)] = int64(90510011014758103)
-	mappp[NewBoardPoint(14, 15, None)] = int64(8533536829380345496)
-	mappp[NewBoardPoint(14, 15, Black)] = int64(3993069667525450617)
-	mappp[NewBoardPoint(14, 15, White)] = int64(3710961266091879798)
-	mappp[NewBoardPoint(14, 16, None)] = int64(4843513547580804210)
-	mappp[NewBoardPoint(14, 16, Black)] = int64(5151429227826058140)
-	mappp[NewBoardPoint(14, 16, White)] = int64(3812111892927521717)
-	mappp[NewBoardPoint(14, 17, None)] = int64(4274102430765678117)
-	mappp[NewBoardPoint(14, 17, Black)] = int64(474914028215466638)
-	mappp[NewBoardPoint(14, 17, White)] = int64(820314330758069234)
-	mappp[NewBoardPoint(14, 18, None)] = int64(1298281200925379537)
-	mappp[NewBoardPoint(14, 18, Black)] = int64(6781165524797397626)
-	mappp[NewBoardPoint(14, 18, White)] = int64(2367519635017244591)
-	mappp[NewBoardPoint(14, 19, None)] = int64(6369001142224481617)
-	mappp[NewBoardPoint(14, 19, Black)] = int64(3702796241485791669)
-	mappp[NewBoardPoint(14, 19, White)] = int64(2560212514190208737)
-	mappp[NewBoardPoint(15, 1, None)] = int64(2247068035787356048)
-	mappp[NewBoardPoint(15, 1, Black)] = int64(8863258304245893972)
-	mappp[NewBoardPoint(15, 1, White)] = int64(1924360931535228889)
-	mappp[NewBoardPoint(15, 2, None)] = int64(991371758509631934)
-	mappp[NewBoardPoint(15, 2, Black)] = int64(3869388295734213951)
-	mappp[NewBoardPoint(15, 2, White)] = int64(492787811722053442)
-	mappp[NewBoardPoint(15, 3, None)] = int64(710920206161567433)
-	mappp[NewBoardPoint(15, 3, Black)] = int64(3025738358484432099)
-	mappp[NewBoardPoint(15, 3, White)] = int64(1119219997725216186)
-	mappp[NewBoardPoint(15, 4, None)] = int64(8033939803974189894)
-	mappp[NewBoardPoint(15, 4, Black)] = int64(1453201571773178752)
-	mappp[NewBoardPoint(15, 4, White)] = int64(7170339971824460309)
-	mappp[NewBoardPoint(15, 5, None)] = int64(8428765920398091047)
-	mappp[NewBoardPoint(15, 5, Black)] = int64(4266437917574440806)
-	mappp[NewBoardPoint(15, 5, White)] = int64(7220096684319873110)
-	mappp[NewBoardPoint(15, 6, None)] = int64(1356256657474338404)
-	mappp[NewBoardPoint(15, 6, Black)] = int64(501406802289438964)
-	mappp[NewBoardPoint(15, 6, White)] = int64(8208010566105585763)
-	mappp[NewBoardPoint(15, 7, None)] = int64(7004550319605964276)
-	mappp[NewBoardPoint(15, 7, Black)] = int64(1717738719629478349)
-	mappp[NewBoardPoint(15, 7, White)] = int64(5202529126345623276)
-	mappp[NewBoardPoint(15, 8, None)] = int64(8027256115547527731)
-	mappp[NewBoardPoint(15, 8, Black)] = int64(4937990104280180881)
-	mappp[NewBoardPoint(15, 8, White)] = int64(5600405709602983937)
-	mappp[NewBoardPoint(15, 9, None)] = int64(8135632782982676264)
-	mappp[NewBoardPoint(15, 9, Black)] = int64(7681893181449886100)
-	mappp[NewBoardPoint(15, 9, White)] = int64(5772843461890676073)
-	mappp[NewBoardPoint(15, 10, None)] = int64(5428299071528722082)
-	mappp[NewBoardPoint(15, 10, Black)] = int64(4661167486755406186)
-	mappp[NewBoardPoint(15, 10, White)] = int64(7286846118855298035)
-	mappp[NewBoardPoint(15, 11, None)] = int64(8709348340487212694)
-	mappp[NewBoardPoint(15, 11, Black)] = int64(8925300844753641482)
-	mappp[NewBoardPoint(15, 11, White)] = int64(4743902995216454879)
-	mappp[NewBoardPoint(15, 12, None)] = int64(6779276414500331772)
-	mappp[NewBoardPoint(15, 12, Black)] = int64(2976850200549711192)
-	mappp[NewBoardPoint(15, 12, White)] = int64(491859761693946931)
-	mappp[NewBoardPoint(15, 13, None)] = int64(2522113529022214627)
-	mappp[NewBoardPoint(15, 13, Black)] = int64(2538850870690648797)
-	mappp[NewBoardPoint(15, 13, White)] = int64(1738774662609665073)
-	mappp[NewBoardPoint(15, 14, None)] = int64(6725663947403456642)
-	mappp[NewBoardPoint(15, 14, Black)] = int64(6733850423263222913)
-	mappp[NewBoardPoint(15, 14, White)] = int64(4979884644694601484)
-	mappp[NewBoardPoint(15, 15, None)] = int64(6513069746525893065)
-	mappp[NewBoardPoint(15, 15, Black)] = int64(6537435363254903919)
-	mappp[NewBoardPoint(15, 15, White)] = int64(5118344562730751747)
-	mappp[NewBoardPoint(15, 16, None)] = int64(6042603445576591976)
-	mappp[NewBoardPoint(15, 16, Black)] = int64(3219333119323284860)
-	mappp[NewBoardPoint(15, 16, White)] = int64(5563554553467652488)
-	mappp[NewBoardPoint(15, 17, None)] = int64(7278550261180246592)
-	mappp[NewBoardPoint(15, 17, Black)] = int64(177736919244611593)
-	mappp[NewBoardPoint(15, 17, White)] = int64(1429938188410868203)
-	mappp[NewBoardPoint(15, 18, None)] = int64(5702347709636773025)
-	mappp[NewBoardPoint(15, 18, Black)] = int64(6334106947770880426)
-	mappp[NewBoardPoint(15, 18, White)] = int64(658820006061268014)
-	mappp[NewBoardPoint(15, 19, None)] = int64(1163230269971687559)
-	mappp[NewBoardPoint(15, 19, Black)] = int64(781044950988304569)
-	mappp[NewBoardPoint(15, 19, White)] = int64(3431386018726180379)
-	mappp[NewBoardPoint(16, 1, None)] = int64(321654770010329907)
-	mappp[NewBoardPoint(16, 1, Black)] = int64(498542412789078144)
-	mappp[NewBoardPoint(16, 1, White)] = int64(3991504441800562106)
-	mappp[NewBoardPoint(16, 2, None)] = int64(4970051920234731341)
-	mappp[NewBoardPoint(16, 2, Black)] = int64(1310225795947379946)
-	mappp[NewBoardPoint(16, 2, White)] = int64(8446219656604415981)
-	mappp[NewBoardPoint(16, 3, None)] = int64(6270797544038756989)
-	mappp[NewBoardPoint(16, 3, Black)] = int64(5868894932927006466)
-	mappp[NewBoardPoint(16, 3, White)] = int64(3141861283404748104)
-	mappp[NewBoardPoint(16, 4, None)] = int64(7191143229025941202)
-	mappp[NewBoardPoint(16, 4, Black)] = int64(7256394861915745667)
-	mappp[NewBoardPoint(16, 4, White)] = int64(4020629485939392565)
-	mappp[NewBoardPoint(16, 5, None)] = int64(681579018912441469)
-	mappp[NewBoardPoint(16, 5, Black)] = int64(869431511452579788)
-	mappp[NewBoardPoint(16, 5, White)] = int64(8103187144934323942)
-	mappp[NewBoardPoint(16, 6, None)] = int64(2878402627444181698)
-	mappp[NewBoardPoint(16, 6, Black)] = int64(6767733029387960529)
-	mappp[NewBoardPoint(16, 6, White)] = int64(7278232130547712060)
-	mappp[NewBoardPoint(16, 7, None)] = int64(8960932640199989146)
-	mappp[NewBoardPoint(16, 7, Black)] = int64(199753619966797445)
-	mappp[NewBoardPoint(16, 7, White)] = int64(3693420230554775666)
-	mappp[NewBoardPoint(16, 8, None)] = int64(3746659006303061217)
-	mappp[NewBoardPoint(16, 8, Black)] = int64(5426216683323203222)
-	mappp[NewBoardPoint(16, 8, White)] = int64(8814885468046878893)
-	mappp[NewBoardPoint(16, 9, None)] = int64(3993912984490957635)
-	mappp[NewBoardPoint(16, 9, Black)] = int64(6790888090246372907)
-	mappp[NewBoardPoint(16, 9, White)] = int64(3908166096095698306)
-	mappp[NewBoardPoint(16, 10, None)] = int64(5724988731457782408)
-	mappp[NewBoardPoint(16, 10, Black)] = int64(2080053947545900484)
-	mappp[NewBoardPoint(16, 10, White)] = int64(3064167884136102778)
-	mappp[NewBoardPoint(16, 11, None)] = int64(2627963607608356172)
-	mappp[NewBoardPoint(16, 11, Black)] = int64(1846000193536366456)
-	mappp[NewBoardPoint(16, 11, White)] = int64(3499462730740590230)
-	mappp[NewBoardPoint(16, 12, None)] = int64(3265373705190590210)
-	mappp[NewBoardPoint(16, 12, Black)] = int64(4683200458393907424)
-	mappp[NewBoardPoint(16, 12, White)] = int64(856690580613415571)
-	mappp[NewBoardPoint(16, 13, None)] = int64(4803853275364435799)
-	mappp[NewBoardPoint(16, 13, Black)] = int64(1733856294617632175)
-	mappp[NewBoardPoint(16, 13, White)] = int64(3942556604812137436)
-	mappp[NewBoardPoint(16, 14, None)] = int64(4273707407402696276)
-	mappp[NewBoardPoint(16, 14, Black)] = int64(5906377780531402280)
-	mappp[NewBoardPoint(16, 14, White)] = int64(5140520838879500974)
-	mappp[NewBoardPoint(16, 15, None)] = int64(4475857234363255973)
-	mappp[NewBoardPoint(16, 15, Black)] = int64(3361193645068352985)
-	mappp[NewBoardPoint(16, 15, White)] = int64(7090886573533788763)
-	mappp[NewBoardPoint(16, 16, None)] = int64(316371528965198651)
-	mappp[NewBoardPoint(16, 16, Black)] = int64(8277684597837313502)
-	mappp[NewBoardPoint(16, 16, White)] = int64(3133450571825054174)
-	mappp[NewBoardPoint(16, 17, None)] = int64(3730694571542401232)
-	mappp[NewBoardPoint(16, 17, Black)] = int64(4274656417726688572)
-	mappp[NewBoardPoint(16, 17, White)] = int64(6138034184118295434)
-	mappp[NewBoardPoint(16, 18, None)] = int64(1372887468188255970)
-	mappp[NewBoardPoint(16, 18, Black)] = int64(3454860830050931946)
-	mappp[NewBoardPoint(16, 18, White)] = int64(7799741903616328757)
-	mappp[NewBoardPoint(16, 19, None)] = int64(4228671250574219609)
-	mappp[NewBoardPoint(16, 19, Black)] = int64(5873258745336764617)
-	mappp[NewBoardPoint(16, 19, White)] = int64(6452882306168350032)
-	mappp[NewBoardPoint(17, 1, None)] = int64(475591256425521123)
-	mappp[NewBoardPoint(17, 1, Black)] = int64(2147791729577197499)
-	mappp[NewBoardPoint(17, 1, White)] = int64(2666081362908477405)
-	mappp[NewBoardPoint(17, 2, None)] = int64(6594975536080069708)
-	mappp[NewBoardPoint(17, 2, Black)] = int64(1000091610372157537)
-	mappp[NewBoardPoint(17, 2, White)] = int64(925247865865857173)
-	mappp[NewBoardPoint(17, 3, None)] = int64(1760823871581822556)
-	mappp[NewBoardPoint(17, 3, Black)] = int64(2966521988020903593)
-	mappp[NewBoardPoint(17, 3, White)] = int64(47029247991026938)
-	mappp[NewBoardPoint(17, 4, None)] = int64(3673747675252499252)
-	mappp[NewBoardPoint(17, 4, Black)] = int64(1081370638819209805)
-	mappp[NewBoardPoint(17, 4, White)] = int64(827936596539833021)
-	mappp[NewBoardPoint(17, 5, None)] = int64(5352152081614867779)
-	mappp[NewBoardPoint(17, 5, Black)] = int64(8056010746772416051)
-	mappp[NewBoardPoint(17, 5, White)] = int64(5286756054684889690)
-	mappp[NewBoardPoint(17, 6, None)] = int64(5174833375545642701)
-	mappp[NewBoardPoint(17, 6, Black)] = int64(1524695683303361641)
-	mappp[NewBoardPoint(17, 6, White)] = int64(1208186200715475866)
-	mappp[NewBoardPoint(17, 7, None)] = int64(1293305350583514785)
-	mappp[NewBoardPoint(17, 7, Black)] = int64(6967021582653828901)
-	mappp[NewBoardPoint(17, 7, White)] = int64(6697760877132336248)
-	mappp[NewBoardPoint(17, 8, None)] = int64(1854064482183312354)
-	mappp[NewBoardPoint(17, 8, Black)] = int64(3879740664638877424)
-	mappp[NewBoardPoint(17, 8, White)] = int64(4884454398434264345)
-	mappp[NewBoardPoint(17, 9, None)] = int64(1064490364146788280)
-	mappp[NewBoardPoint(17, 9, Black)] = int64(3970839187067804089)
-	mappp[NewBoardPoint(17, 9, White)] = int64(8578714429537658792)
-	mappp[NewBoardPoint(17, 10, None)] = int64(2594880757404063104)
-	mappp[NewBoardPoint(17, 10, Black)] = int64(2661080097558079190)
-	mappp[NewBoardPoint(17, 10, White)] = int64(3120180812342452897)
-	mappp[NewBoardPoint(17, 11, None)] = int64(1501418005835731306)
-	mappp[NewBoardPoint(17, 11, Black)] = int64(8445407117535696315)
-	mappp[NewBoardPoint(17, 11, White)] = int64(4865967515514283827)
-	mappp[NewBoardPoint(17, 12, None)] = int64(3474911505634283425)
-	mappp[NewBoardPoint(17, 12, Black)] = int64(4626821743012895477)
-	mappp[NewBoardPoint(17, 12, White)] = int64(3286202097006700740)
-	mappp[NewBoardPoint(17, 13, None)] = int64(7275913896320891192)
-	mappp[NewBoardPoint(17, 13, Black)] = int64(4736456200863429371)
-	mappp[NewBoardPoint(17, 13, White)] = int64(5166174186822426726)
-	mappp[NewBoardPoint(17, 14, None)] = int64(4699170494305169066)
-	mappp[NewBoardPoint(17, 14, Black)] = int64(5739384864853320033)
-	mappp[NewBoardPoint(17, 14, White)] = int64(4992051356826522037)
-	mappp[NewBoardPoint(17, 15, None)] = int64(1440505346054990082)
-	mappp[NewBoardPoint(17, 15, Black)] = int64(6800033001533614455)
-	mappp[NewBoardPoint(17, 15, White)] = int64(5140741257361837224)
-	mappp[NewBoardPoint(17, 16, None)] = int64(6337076534748133104)
-	mappp[NewBoardPoint(17, 16, Black)] = int64(8239186792156560767)
-	mappp[NewBoardPoint(17, 16, White)] = int64(5889640082829493699)
-	mappp[NewBoardPoint(17, 17, None)] = int64(2273150156442320389)
-	mappp[NewBoardPoint(17, 17, Black)] = int64(888653945653423781)
-	mappp[NewBoardPoint(17, 17, White)] = int64(3657711507409858188)
-	mappp[NewBoardPoint(17, 18, None)] = int64(1984616077516578843)
-	mappp[NewBoardPoint(17, 18, Black)] = int64(647501863138125557)
-	mappp[NewBoardPoint(17, 18, White)] = int64(6618489964006361103)
-	mappp[NewBoardPoint(17, 19, None)] = int64(7562276092856328872)
-	mappp[NewBoardPoint(17, 19, Black)] = int64(1060489765239960579)
-	mappp[NewBoardPoint(17, 19, White)] = int64(752501619255363486)
-	mappp[NewBoardPoint(18, 1, None)] = int64(6932011995835039857)
-	mappp[NewBoardPoint(18, 1, Black)] = int64(1764764447175786704)
-	mappp[NewBoardPoint(18, 1, White)] = int64(6339824815693616618)
-	mappp[NewBoardPoint(18, 2, None)] = int64(2124478800135531295)
-	mappp[NewBoardPoint(18, 2, Black)] = int64(8547990350374371932)
-	mappp[NewBoardPoint(18, 2, White)] = int64(7495347784193616940)
-	mappp[NewBoardPoint(18, 3, None)] = int64(3592736983808197286)
-	mappp[NewBoardPoint(18, 3, Black)] = int64(3446656220581184093)
-	mappp[NewBoardPoint(18, 3, White)] = int64(7773162913567102643)
-	mappp[NewBoardPoint(18, 4, None)] = int64(4823126760422195837)
-	mappp[NewBoardPoint(18, 4, Black)] = int64(1155681992665751406)
-	mappp[NewBoardPoint(18, 4, White)] = int64(5884245332371519623)
-	mappp[NewBoardPoint(18, 5, None)] = int64(5355825881791923949)
-	mappp[NewBoardPoint(18, 5, Black)] = int64(8426984780009072659)
-	mappp[NewBoardPoint(18, 5, White)] = int64(6954100198418646093)
-	mappp[NewBoardPoint(18, 6, None)] = int64(2443466971800598522)
-	mappp[NewBoardPoint(18, 6, Black)] = int64(4247081972266721596)
-	mappp[NewBoardPoint(18, 6, White)] = int64(7257450150805985147)
-	mappp[NewBoardPoint(18, 7, None)] = int64(5117693282354521558)
-	mappp[NewBoardPoint(18, 7, Black)] = int64(7105888882064386322)
-	mappp[NewBoardPoint(18, 7, White)] = int64(3385235240406435131)
-	mappp[NewBoardPoint(18, 8, None)] = int64(8284106770092520963)
-	mappp[NewBoardPoint(18, 8, Black)] = int64(3494372143675125971)
-	mappp[NewBoardPoint(18, 8, White)] = int64(1712545787394038478)
-	mappp[NewBoardPoint(18, 9, None)] = int64(1139358604790012662)
-	mappp[NewBoardPoint(18, 9, Black)] = int64(6575629638377780062)
-	mappp[NewBoardPoint(18, 9, White)] = int64(8505105235043668912)
-	mappp[NewBoardPoint(18, 10, None)] = int64(5290596971348654241)
-	mappp[NewBoardPoint(18, 10, Black)] = int64(495310332261051980)
-	mappp[NewBoardPoint(18, 10, White)] = int64(6838822077576049431)
-	mappp[NewBoardPoint(18, 11, None)] = int64(3179475391053170235)
-	mappp[NewBoardPoint(18, 11, Black)] = int64(2197577092418239856)
-	mappp[NewBoardPoint(18, 11, White)] = int64(362512313797658735)
-	mappp[NewBoardPoint(18, 12, None)] = int64(3507063553771534021)
-	mappp[NewBoardPoint(18, 12, Black)] = int64(1500810058715354480)
-	mappp[NewBoardPoint(18, 12, White)] = int64(1487401461612533053)
-	mappp[NewBoardPoint(18, 13, None)] = int64(7390134755816977116)
-	mappp[NewBoardPoint(18, 13, Black)] = int64(5149159307723591935)
-	mappp[NewBoardPoint(18, 13, White)] = int64(5903225508349025448)
-	mappp[NewBoardPoint(18, 14, None)] = int64(5740162258242597145)
-	mappp[NewBoardPoint(18, 14, Black)] = int64(1107142630100765581)
-	mappp[NewBoardPoint(18, 14, White)] = int64(8536706664263989593)
-	mappp[NewBoardPoint(18, 15, None)] = int64(3895628580830437043)
-	mappp[NewBoardPoint(18, 15, Black)] = int64(830507597146895642)
-	mappp[NewBoardPoint(18, 15, White)] = int64(6203522734191413120)
-	mappp[NewBoardPoint(18, 16, None)] = int64(4820032292453309127)
-	mappp[NewBoardPoint(18, 16, Black)] = int64(7475218428245574397)
-	mappp[NewBoardPoint(18, 16, White)] = int64(5409601909015758275)
-	mappp[NewBoardPoint(18, 17, None)] = int64(5716683067524952309)
-	mappp[NewBoardPoint(18, 17, Black)] = int64(2969363806075274153)
-	mappp[NewBoardPoint(18, 17, White)] = int64(6370698574228962276)
-	mappp[NewBoardPoint(18, 18, None)] = int64(4770275766325804014)
-	mappp[NewBoardPoint(18, 18, Black)] = int64(3868356096539960190)
-	mappp[NewBoardPoint(18, 18, White)] = int64(4494196242853943944)
-	mappp[NewBoardPoint(18, 19, None)] = int64(4550965489940213195)
-	mappp[NewBoardPoint(18, 19, Black)] = int64(5362208740725604408)
-	mappp[NewBoardPoint(18, 19, White)] = int64(151785560862674463)
-	mappp[NewBoardPoint(19, 1, None)] = int64(6085888125027529427)
-	mappp[NewBoardPoint(19, 1, Black)] = int64(8847886121391130772)
-	mappp[NewBoardPoint(19, 1, White)] = int64(324789426866467101)
-	mappp[NewBoardPoint(19, 2, None)] = int64(2360393533323186868)
-	mappp[NewBoardPoint(19, 2, Black)] = int64(366929930646846952)
-	mappp[NewBoardPoint(19, 2, White)] = int64(5331786380964929894)
-	mappp[NewBoardPoint(19, 3, None)] = int64(6265584984687189760)
-	mappp[NewBoardPoint(19, 3, Black)] = int64(1105475837719012462)
-	mappp[NewBoardPoint(19, 3, White)] = int64(5675617815436110531)
-	mappp[NewBoardPoint(19, 4, None)] = int64(437929100272706064)
-	mappp[NewBoardPoint(19, 4, Black)] = int64(9053115766238040149)
-	mappp[NewBoardPoint(19, 4, White)] = int64(5722457256942646341)
-	mappp[NewBoardPoint(19, 5, None)] = int64(1257259367702460350)
-	mappp[NewBoardPoint(19, 5, Black)] = int64(1128149118352784513)
-	mappp[NewBoardPoint(19, 5, White)] = int64(7346222510139808093)
-	mappp[NewBoardPoint(19, 6, None)] = int64(4786379262365354735)
-	mappp[NewBoardPoint(19, 6, Black)] = int64(1066896483983675092)
-	mappp[NewBoardPoint(19, 6, White)] = int64(4347108333707695190)
-	mappp[NewBoardPoint(19, 7, None)] = int64(6040750576516885933)
-	mappp[NewBoardPoint(19, 7, Black)] = int64(3327732851936106137)
-	mappp[NewBoardPoint(19, 7, White)] = int64(5553786594989227911)
-	mappp[NewBoardPoint(19, 8, None)] = int64(1244278103807637522)
-	mappp[NewBoardPoint(19, 8, Black)] = int64(1424739792349121431)
-	mappp[NewBoardPoint(19, 8, White)] = int64(1673569723856744762)
-	mappp[NewBoardPoint(19, 9, None)] = int64(8598484160475954093)
-	mappp[NewBoardPoint(19, 9, Black)] = int64(3711513059392970827)
-	mappp[NewBoardPoint(19, 9, White)] = int64(2940043128588886576)
-	mappp[NewBoardPoint(19, 10, None)] = int64(4552716615281709009)
-	mappp[NewBoardPoint(19, 10, Black)] = int64(2701148285772167346)
-	mappp[NewBoardPoint(19, 10, White)] = int64(5737874500632907314)
-	mappp[NewBoardPoint(19, 11, None)] = int64(5502723987076626811)
-	mappp[NewBoardPoint(19, 11, Black)] = int64(9164678817629275360)
-	mappp[NewBoardPoint(19, 11, White)] = int64(6908256315546128356)
-	mappp[NewBoardPoint(19, 12, None)] = int64(7927463116490582840)
-	mappp[NewBoardPoint(19, 12, Black)] = int64(8198910571218259185)
-	mappp[NewBoardPoint(19, 12, White)] = int64(1819748462623947505)
-	mappp[NewBoardPoint(19, 13, None)] = int64(1409610355445538934)
-	mappp[NewBoardPoint(19, 13, Black)] = int64(5909117038341874395)
-	mappp[NewBoardPoint(19, 13, White)] = int64(5487798035216498488)
-	mappp[NewBoardPoint(19, 14, None)] = int64(1039031606042517183)
-	mappp[NewBoardPoint(19, 14, Black)] = int64(8795931016781352003)
-	mappp[NewBoardPoint(19, 14, White)] = int64(2792724449926459760)
-	mappp[NewBoardPoint(19, 15, None)] = int64(2297973552953135915)
-	mappp[NewBoardPoint(19, 15, Black)] = int64(2821552277474511500)
-	mappp[NewBoardPoint(19, 15, White)] = int64(3760142366615390687)
-	mappp[NewBoardPoint(19, 16, None)] = int64(8983422783487470518)
-	mappp[NewBoardPoint(19, 16, Black)] = int64(5246753984117255487)
-	mappp[NewBoardPoint(19, 16, White)] = int64(7824990698616037487)
-	mappp[NewBoardPoint(19, 17, None)] = int64(2926274500439279321)
-	mappp[NewBoardPoint(19, 17, Black)] = int64(4055648688865682152)
-	mappp[NewBoardPoint(19, 17, White)] = int64(6996170066782455113)
-	mappp[NewBoardPoint(19, 18, None)] = int64(6375861221753395575)
-	mappp[NewBoardPoint(19, 18, Black)] = int64(8484286058752239248)
-	mappp[NewBoardPoint(19, 18, White)] = int64(1706348634358662172)
-	mappp[NewBoardPoint(19, 19, None)] = int64(9052612540127030977)
-	mappp[NewBoardPoint(19, 19, Black)] = int64(570079219100623828)
-	mappp[NewBoardPoint(19, 19, White)] = int64(4716517613799916277)
-	return mappp
+// 128位的哈希值，Lo 就是平时用的 64 位哈希值，Hi 用来进一步减少冲突
+type Hash128 struct {
+	Hi, Lo int64
+}
+
+// 异或两个 128 位哈希值
+func (h Hash128) Xor(o Hash128) Hash128 {
+	return Hash128{Hi: h.Hi ^ o.Hi, Lo: h.Lo ^ o.Lo}
 }
 
+// Zobrist 哈希表
+// 交叉点按 (Row-1)*MaxBoardSize + (Col-1) 编号，与棋盘的实际大小无关
+type ZobristTable struct {
+	Seed        uint64                                  // 生成哈希表用的随机数种子
+	Empty       Hash128                                 // 空棋盘的哈希值
+	Stones      [MaxBoardSize * MaxBoardSize][2]Hash128 // 每个交叉点上有黑子、白子时的键
+	Ko          [MaxBoardSize * MaxBoardSize]Hash128    // 每个交叉点是劫的禁着点时的键
+	WhiteToMove Hash128                                 // 轮到白棋下时的键
+}
+
+// 用指定的种子生成哈希表，同样的种子总是得到同样的表
+func NewZobristTable(seed uint64) *ZobristTable {
+	z := &ZobristTable{Seed: seed}
+	state := seed
+	next := func() Hash128 {
+		return Hash128{Hi: int64(splitMix64(&state)), Lo: int64(splitMix64(&state))}
+	}
+	z.Empty = next()
+	for i := range z.Stones {
+		z.Stones[i][0] = next()
+		z.Stones[i][1] = next()
+	}
+	for i := range z.Ko {
+		z.Ko[i] = next()
+	}
+	z.WhiteToMove = next()
+	return z
+}
+
+// SplitMix64 伪随机数，实现简单，与 Go 版本无关
+// http://xoshiro.di.unimi.it/splitmix64.c
+func splitMix64(state *uint64) uint64 {
+	*state += 0x9E3779B97F4A7C15
+	z := *state
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
+
+// 交叉点在哈希表中的编号
+func zobristIndex(p Point) int {
+	if p.Row < 1 || p.Col < 1 || p.Row > MaxBoardSize || p.Col > MaxBoardSize {
+		log.Panicf("%v 超出了 Zobrist 哈希表的范围", p)
+	}
+	return int(p.Row-1)*MaxBoardSize + int(p.Col-1)
+}
+
+// 某个交叉点放上某种颜色棋子对应的键
+func (z *ZobristTable) Point(p Point, color Player) Hash128 {
+	return z.Stones[zobristIndex(p)][color-Black]
+}
+
+// 某个交叉点是劫的禁着点对应的键
+func (z *ZobristTable) KoPoint(p Point) Hash128 {
+	return z.Ko[zobristIndex(p)]
+}
+
+var (
+	Zobrist            = NewZobristTable(ZobristSeed) // 默认的哈希表，棋盘和游戏状态都使用它
+	EmptyBoardHashCode = Zobrist.Empty.Lo             // 空棋盘的哈希值
+)
@@ -0,0 +1,70 @@
+package aigo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// 同样的种子必须生成同样的表，键之间不能重复
+func TestZobristTableDeterministic(t *testing.T) {
+	z1, z2 := NewZobristTable(ZobristSeed), NewZobristTable(ZobristSeed)
+	if *z1 != *z2 {
+		t.Fatal("同样的种子生成的哈希表不一样")
+	}
+	if *NewZobristTable(ZobristSeed + 1) == *z1 {
+		t.Fatal("不同的种子生成的哈希表一样")
+	}
+	seen := map[int64]bool{z1.Empty.Lo: true, z1.WhiteToMove.Lo: true}
+	for i := range z1.Stones {
+		for _, k := range []Hash128{z1.Stones[i][0], z1.Stones[i][1], z1.Ko[i]} {
+			if seen[k.Lo] {
+				t.Fatalf("第%d个交叉点的键重复了", i)
+			}
+			seen[k.Lo] = true
+		}
+	}
+}
+
+// 随机落子、悔棋后，增量维护的哈希值要等于从头计算的值
+func TestZobristIncremental(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	for _, size := range []uint16{5, 19, 25} {
+		game := NewGameOfSize(size, size)
+		undos := []*UndoRecord{}
+		for i := 0; i < 300; i++ {
+			if len(undos) > 0 && rnd.Intn(4) == 0 {
+				game.Undo(undos[len(undos)-1])
+				undos = undos[:len(undos)-1]
+			} else {
+				p := Point{Row: uint16(rnd.Intn(int(size)) + 1), Col: uint16(rnd.Intn(int(size)) + 1)}
+				if game.BoardPosition.Get(p) != None {
+					continue
+				}
+				u, err := game.Play(NewPlay(p))
+				if err != nil {
+					t.Fatal(err)
+				}
+				undos = append(undos, u)
+			}
+			b := game.BoardPosition
+			if b.GetZobristHash128() != b.RecomputeZobristHash() {
+				t.Fatalf("%dx%d 第%d步哈希不一致", size, size, i)
+			}
+			if c := b.Copy(); c.GetZobristHash128() != b.GetZobristHash128() {
+				t.Fatalf("复制棋盘后哈希不一致")
+			}
+		}
+	}
+}
+
+// 局面哈希要区分轮到谁下
+func TestZobristSideToMove(t *testing.T) {
+	game := NewGameOfSize(9, 9)
+	passed, _ := game.ApplyMove(NewPass())
+	if game.BoardPosition.GetZobristHash() != passed.BoardPosition.GetZobristHash() {
+		t.Fatal("跳过不应该改变棋盘哈希")
+	}
+	if game.ZobristHash() == passed.ZobristHash() {
+		t.Fatal("轮到不同的人下，局面哈希应该不同")
+	}
+}